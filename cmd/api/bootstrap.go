@@ -47,13 +47,52 @@ func runApplication() error {
 	}
 	defer mediaCleanup()
 
-	appCtx := setupAppContext(cfg, db, redis, logger, mediaSvc) // app context
+	// Setup mailer
+	mail, mailErr := setupMailer(cfg)
+	if mailErr != nil {
+		log.Error().Err(mailErr).Msg("mailer setup failed")
+		return mailErr
+	}
+
+	// Setup jwt signing & verification keys
+	jwtHandler, jwtErr := setupJWT(cfg)
+	if jwtErr != nil {
+		log.Error().Err(jwtErr).Msg("jwt setup failed")
+		return jwtErr
+	}
+
+	// Setup password hashing & policy
+	passwordHasher, hasherErr := setupPasswordHasher(cfg)
+	if hasherErr != nil {
+		log.Error().Err(hasherErr).Msg("password hasher setup failed")
+		return hasherErr
+	}
+
+	passwordPolicy, policyErr := setupPasswordPolicy(cfg)
+	if policyErr != nil {
+		log.Error().Err(policyErr).Msg("password policy setup failed")
+		return policyErr
+	}
+
+	// Setup role permissions
+	authorizer, authzErr := setupAuthorizer(cfg, db)
+	if authzErr != nil {
+		log.Error().Err(authzErr).Msg("authorization setup failed")
+		return authzErr
+	}
+
+	appCtx := setupAppContext(cfg, db, redis, logger, mediaSvc, mail, jwtHandler, passwordHasher, passwordPolicy, authorizer) // app context
 
 	// Run development-only cleanup of old rate-limit keys
 	if cfg.AppEnv == constants.EnvDev {
 		app.CleanupOldRateLimitKeysOnStartup(appCtx)
 	}
 
+	// Start background jobs, they stop once the server has shut down
+	jobsCtx, jobsCancel := context.WithCancel(context.Background())
+	defer jobsCancel()
+	setupJobs(jobsCtx, appCtx)
+
 	router := setupRouter(appCtx)          // router
 	server := setupHTTPServer(cfg, router) // server
 
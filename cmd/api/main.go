@@ -25,6 +25,11 @@ import (
 //	@name						Authorization
 //	@description				Type "Bearer " followed by a space and then your token.
 
+//	@securityDefinitions.apikey	ApiKeyAuth
+//	@in							header
+//	@name						X-API-Key
+//	@description				Personal api key created at /api-keys.
+
 // @externalDocs.description	OpenAPI
 // @externalDocs.url			https://swagger.io/resources/open-api/
 func main() {
@@ -1,21 +1,46 @@
 package main
 
 import (
+	"time"
+
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 
 	"github.com/mrhpn/go-rest-api/internal/app"
+	"github.com/mrhpn/go-rest-api/internal/audit"
 	"github.com/mrhpn/go-rest-api/internal/config"
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/kvstore"
+	"github.com/mrhpn/go-rest-api/internal/mailer"
+	"github.com/mrhpn/go-rest-api/internal/modules/apikeys"
 	"github.com/mrhpn/go-rest-api/internal/modules/media"
+	"github.com/mrhpn/go-rest-api/internal/modules/organizations"
+	"github.com/mrhpn/go-rest-api/internal/modules/users"
 	"github.com/mrhpn/go-rest-api/internal/security"
 )
 
-func setupAppContext(cfg *config.Config, db *gorm.DB, redis *redis.Client, logger zerolog.Logger, media media.Service) *app.Context {
-	securityHandler := security.NewJWTHandler(
-		cfg.JWT.Secret,
-		cfg.JWT.AccessTokenExpirationSecond,
-		cfg.JWT.RefreshTokenExpirationSecond,
+func setupAppContext(
+	cfg *config.Config,
+	db *gorm.DB,
+	redis *redis.Client,
+	logger zerolog.Logger,
+	media media.Service,
+	mail mailer.Mailer,
+	securityHandler *security.JWTHandler,
+	passwordHasher *security.PasswordHasher,
+	passwordPolicy *security.PasswordPolicy,
+	authorizer *security.Authorizer,
+) *app.Context {
+	kv := setupKVStore(cfg, redis)
+	auditor := audit.NewRecorder(db)
+	tenants := organizations.NewService(
+		organizations.NewRepository(db),
+		users.NewRepository(db),
+		authorizer,
+		auditor,
+		securityHandler,
 	)
 
 	return &app.Context{
@@ -24,6 +49,46 @@ func setupAppContext(cfg *config.Config, db *gorm.DB, redis *redis.Client, logge
 		Cfg:             cfg,
 		Logger:          logger,
 		SecurityHandler: securityHandler,
+		PasswordHasher:  passwordHasher,
+		PasswordPolicy:  passwordPolicy,
+		Authorizer:      authorizer,
+		TokenRevocation: security.NewTokenRevocation(kv, maxTokenExpirySecond(cfg), cfg.JWT.LeewaySecond),
+		APIKeys:         apikeys.NewService(apikeys.NewRepository(db), users.NewRepository(db)),
+		AccountLockout:  security.NewAccountLockout(kv, lockoutOptions(cfg)),
+		OIDC:            setupOIDC(cfg),
+		Tenants:         tenants,
+		Audit:           auditor,
+		KV:              kv,
 		MediaService:    media,
+		Mailer:          mail,
+	}
+}
+
+func setupKVStore(cfg *config.Config, redis *redis.Client) kvstore.Store {
+	if cfg.Redis.Enabled && redis != nil {
+		return kvstore.NewRedisStore(redis)
+	}
+
+	// Use in-memory store (not suitable for multi-instance deployments)
+	log.Warn().Msg("Redis not enabled, using in-memory key-value store (not suitable for multi-instance deployments)")
+	return kvstore.NewMemoryStore()
+}
+
+// maxTokenExpirySecond is the longest lifetime of a token checked against the revocation store.
+func maxTokenExpirySecond(cfg *config.Config) int {
+	return max(
+		cfg.JWT.AccessTokenExpirationSecond,
+		constants.ImpersonationTokenExpirationSecond,
+		constants.MFAChallengeTokenExpirationSecond,
+	)
+}
+
+func lockoutOptions(cfg *config.Config) security.LockoutOptions {
+	return security.LockoutOptions{
+		MaxAttempts:  cfg.Auth.LoginMaxFailedAttempts,
+		Window:       time.Duration(cfg.Auth.LoginFailureWindowSecond) * time.Second,
+		LockDuration: time.Duration(cfg.Auth.LoginLockoutDurationSecond) * time.Second,
+		BaseDelay:    time.Duration(cfg.Auth.LoginDelayBaseMillisecond) * time.Millisecond,
+		MaxDelay:     time.Duration(cfg.Auth.LoginDelayMaxMillisecond) * time.Millisecond,
 	}
 }
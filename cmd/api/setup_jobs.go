@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/app"
+	"github.com/mrhpn/go-rest-api/internal/modules/privacy"
+	"github.com/mrhpn/go-rest-api/internal/modules/retention"
+	"github.com/mrhpn/go-rest-api/internal/modules/users"
+	"github.com/mrhpn/go-rest-api/internal/scheduler"
+)
+
+// setupJobs starts the background jobs. They stop when ctx is done.
+func setupJobs(ctx context.Context, appCtx *app.Context) {
+	var jobs []scheduler.Job
+
+	if days := appCtx.Cfg.Auth.DormantAfterDays; days > 0 {
+		jobs = append(jobs, users.NewDormancyJob(
+			users.NewRepository(appCtx.DB),
+			time.Duration(days)*24*time.Hour,
+			time.Duration(appCtx.Cfg.Auth.DormancyCheckIntervalSecond)*time.Second,
+		))
+	}
+
+	privacyS := privacy.NewService(
+		privacy.NewRepository(appCtx.DB),
+		appCtx.MediaService,
+		appCtx.TokenRevocation,
+		appCtx.Audit,
+		appCtx.Cfg.Privacy,
+	)
+	privacyInterval := time.Duration(appCtx.Cfg.Privacy.JobIntervalSecond) * time.Second
+	jobs = append(jobs,
+		privacy.NewExportJob(privacyS, privacyInterval),
+		privacy.NewErasureJob(privacyS, privacyInterval),
+	)
+
+	if retentionCfg := appCtx.Cfg.Retention; retentionCfg.DeletedUsersDays > 0 || retentionCfg.DeletedPostsDays > 0 {
+		purge := retention.NewPurgeJob(
+			retention.NewService(retention.NewRepository(appCtx.DB), appCtx.MediaService, retentionCfg),
+			time.Duration(retentionCfg.IntervalSecond)*time.Second,
+		)
+		// the in-memory store can't coordinate replicas, without redis every instance purges
+		if appCtx.Cfg.Redis.Enabled {
+			purge = scheduler.Exclusive(purge, appCtx.KV)
+		}
+		jobs = append(jobs, purge)
+	}
+
+	scheduler.Start(ctx, jobs...)
+}
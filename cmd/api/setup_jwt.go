@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mrhpn/go-rest-api/internal/config"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+func setupJWT(cfg *config.Config) (*security.JWTHandler, error) {
+	var (
+		signer    *security.Key
+		verifiers []*security.Key
+		err       error
+	)
+
+	if cfg.JWT.PrivateKeyPath != "" {
+		signer, err = security.LoadPrivateKey(cfg.JWT.KeyID, cfg.JWT.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jwt signing key: %w", err)
+		}
+
+		// keep accepting tokens signed with the shared secret while migrating away from HS256
+		if cfg.JWT.Secret != "" {
+			verifiers = append(verifiers, security.NewHMACKey("", cfg.JWT.Secret))
+		}
+	} else {
+		signer = security.NewHMACKey(cfg.JWT.KeyID, cfg.JWT.Secret)
+	}
+
+	for _, f := range cfg.JWT.VerificationKeys {
+		key, keyErr := security.LoadPublicKey(f.ID, f.Path)
+		if keyErr != nil {
+			return nil, fmt.Errorf("failed to load jwt verification key: %w", keyErr)
+		}
+		verifiers = append(verifiers, key)
+	}
+
+	keys, err := security.NewKeySet(signer, verifiers...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt keys: %w", err)
+	}
+
+	log.Info().
+		Str("alg", signer.Method.Alg()).
+		Str("kid", signer.ID).
+		Int("verification_keys", len(verifiers)).
+		Msg("✅ JWT signing keys loaded")
+
+	return security.NewJWTHandler(keys, security.JWTOptions{
+		Issuer:             cfg.JWT.Issuer,
+		Audience:           cfg.JWT.Audience,
+		AccessTokenExpiry:  time.Duration(cfg.JWT.AccessTokenExpirationSecond) * time.Second,
+		RefreshTokenExpiry: time.Duration(cfg.JWT.RefreshTokenExpirationSecond) * time.Second,
+		Leeway:             time.Duration(cfg.JWT.LeewaySecond) * time.Second,
+	}), nil
+}
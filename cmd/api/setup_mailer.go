@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mrhpn/go-rest-api/internal/config"
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/mailer"
+)
+
+func setupMailer(cfg *config.Config) (mailer.Mailer, error) {
+	switch strings.ToLower(cfg.Mail.Provider) {
+	case constants.MailProviderFile:
+		m, err := mailer.NewFileMailer(cfg.Mail.From, cfg.Mail.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup file mailer: %w", err)
+		}
+		return m, nil
+	default:
+		return mailer.NewLogMailer(cfg.Mail.From), nil
+	}
+}
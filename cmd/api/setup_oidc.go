@@ -0,0 +1,37 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mrhpn/go-rest-api/internal/config"
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/oidc"
+)
+
+// setupOIDC registers the configured identity providers. Providers are only contacted on
+// the first login, so an unreachable provider doesn't keep the api from starting.
+func setupOIDC(cfg *config.Config) *oidc.Registry {
+	client := &http.Client{Timeout: constants.OIDCHTTPTimeoutSecond * time.Second}
+
+	providers := make([]*oidc.Provider, 0, len(cfg.OIDC.Providers))
+	for _, p := range cfg.OIDC.Providers {
+		providers = append(providers, oidc.NewProvider(oidc.ProviderConfig{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+			Leeway:       time.Duration(cfg.JWT.LeewaySecond) * time.Second,
+		}, client))
+	}
+
+	registry := oidc.NewRegistry(providers...)
+	if len(providers) > 0 {
+		log.Info().Strs("providers", registry.Names()).Msg("✅ OIDC providers registered")
+	}
+	return registry
+}
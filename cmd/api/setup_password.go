@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mrhpn/go-rest-api/internal/config"
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+func setupPasswordHasher(cfg *config.Config) (*security.PasswordHasher, error) {
+	hasher, err := security.NewPasswordHasher(security.PasswordHashOptions{
+		Algorithm:         cfg.Password.HashAlgorithm,
+		BcryptCost:        cfg.Password.BcryptCost,
+		Argon2Memory:      cfg.Password.Argon2MemoryKiB,
+		Argon2Iterations:  cfg.Password.Argon2Iterations,
+		Argon2Parallelism: cfg.Password.Argon2Parallelism,
+		Argon2SaltLength:  constants.Argon2SaltLength,
+		Argon2KeyLength:   constants.Argon2KeyLength,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid password hashing config: %w", err)
+	}
+	return hasher, nil
+}
+
+func setupPasswordPolicy(cfg *config.Config) (*security.PasswordPolicy, error) {
+	opts := security.PasswordPolicyOptions{
+		MinLength:      cfg.Password.MinLength,
+		MaxLength:      cfg.Password.MaxLength,
+		MinCharClasses: cfg.Password.MinCharClasses,
+		RejectEmail:    cfg.Password.RejectEmail,
+		MinStrength:    cfg.Password.MinStrength,
+	}
+
+	if cfg.Password.BreachedListPath != "" {
+		breached, err := security.LoadBreachedPasswords(cfg.Password.BreachedListPath)
+		if err != nil {
+			return nil, err
+		}
+		opts.Breached = breached
+
+		log.Info().Int("hashes", breached.Len()).Msg("✅ Breached password list loaded")
+	}
+
+	return security.NewPasswordPolicy(opts), nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"github.com/mrhpn/go-rest-api/internal/config"
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/modules/permissions"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// setupAuthorizer builds the role to permission mapping. With RBAC_SOURCE=database the grants in
+// the role_permissions table replace the configured permissions of every role they list.
+// The mapping is loaded once, changes in the table apply after a restart.
+func setupAuthorizer(cfg *config.Config, db *gorm.DB) (*security.Authorizer, error) {
+	rolePermissions := maps.Clone(cfg.RBAC.RolePermissions)
+
+	if cfg.RBAC.Source == constants.RBACSourceDatabase {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.DB.QueryTimeoutSecond)*time.Second)
+		defer cancel()
+
+		stored, err := permissions.NewRepository(db).ListByRole(ctx)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(rolePermissions, stored)
+
+		log.Info().Interface("roles", slices.Sorted(maps.Keys(stored))).Msg("✅ Role permissions loaded from database")
+	}
+
+	authorizer, err := security.NewAuthorizer(rolePermissions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid role permissions: %w", err)
+	}
+	return authorizer, nil
+}
@@ -11,7 +11,7 @@ import (
 )
 
 func setupRouter(ctx *app.Context) *gin.Engine {
-	httpx.RegisterValidators()
+	httpx.RegisterValidators(ctx.PasswordPolicy)
 
 	if ctx.Cfg.AppEnv != constants.EnvDev {
 		gin.SetMode(gin.ReleaseMode)
@@ -35,7 +35,7 @@ func setupRouter(ctx *app.Context) *gin.Engine {
 	router.Use(middlewares.RequestID(ctx.Cfg.AppEnv))
 
 	// ⏱️ Request timeout middleware
-	router.Use(middlewares.RequestTimeout(ctx.Cfg.HTTP.RequestTimeoutSecond))
+	router.Use(middlewares.RequestTimeout(ctx.Cfg.HTTP.RequestTimeoutSecond, routes.StreamingPaths()...))
 
 	// 📝 Request logger middleware
 	router.Use(middlewares.RequestLogger())
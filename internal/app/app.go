@@ -5,9 +5,14 @@ import (
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 
+	"github.com/mrhpn/go-rest-api/internal/audit"
 	"github.com/mrhpn/go-rest-api/internal/config"
+	"github.com/mrhpn/go-rest-api/internal/kvstore"
+	"github.com/mrhpn/go-rest-api/internal/mailer"
 	"github.com/mrhpn/go-rest-api/internal/modules/media"
+	"github.com/mrhpn/go-rest-api/internal/oidc"
 	"github.com/mrhpn/go-rest-api/internal/security"
+	"github.com/mrhpn/go-rest-api/internal/tenancy"
 )
 
 // Context is the application context containing all required dependencies.
@@ -17,5 +22,16 @@ type Context struct {
 	Cfg             *config.Config
 	Logger          zerolog.Logger
 	SecurityHandler *security.JWTHandler
+	PasswordHasher  *security.PasswordHasher
+	PasswordPolicy  *security.PasswordPolicy
+	Authorizer      *security.Authorizer
+	TokenRevocation *security.TokenRevocation
+	APIKeys         security.APIKeyAuthenticator
+	AccountLockout  *security.AccountLockout
+	OIDC            *oidc.Registry
+	Tenants         tenancy.Resolver
+	Audit           audit.Recorder
+	KV              kvstore.Store
 	MediaService    media.Service
+	Mailer          mailer.Mailer
 }
@@ -0,0 +1,24 @@
+package audit
+
+import "context"
+
+type contextKey string
+
+const requestInfoKey contextKey = "audit_request_info"
+
+// RequestInfo holds the details of the http request an action was performed in
+type RequestInfo struct {
+	RequestID string
+	IPAddress string
+	UserAgent string
+}
+
+// WithRequestInfo returns a copy of ctx carrying request details for audit records
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey, info)
+}
+
+func requestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey).(RequestInfo)
+	return info
+}
@@ -0,0 +1,2 @@
+// Package audit records security-relevant actions (who did what to whom, from where) in the audit_logs table.
+package audit
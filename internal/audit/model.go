@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"github.com/mrhpn/go-rest-api/internal/model"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionAccountLocked        = "account.locked"
+	ActionAccountUnlocked      = "account.unlocked"
+	ActionIdentityLinked       = "identity.linked"
+	ActionSessionRevoked       = "session.revoked"
+	ActionRoleChanged          = "user.role_changed"
+	ActionPasswordChanged      = "user.password_changed"
+	ActionEmailChanged         = "user.email_changed"
+	ActionErasureRequested     = "user.erasure_requested"
+	ActionErasureCancelled     = "user.erasure_cancelled"
+	ActionUserErased           = "user.erased"
+	ActionUserBlocked          = "user.blocked"
+	ActionUserRestored         = "user.restored"
+	ActionUserDeleted          = "user.deleted"
+	ActionImpersonationStarted = "impersonation.started"
+	ActionImpersonatedRequest  = "impersonation.request"
+	ActionMemberAdded          = "organization.member_added"
+	ActionMemberRoleChanged    = "organization.member_role_changed"
+	ActionMemberRemoved        = "organization.member_removed"
+)
+
+// Target types of audited actions
+const (
+	TargetUser         = "user"
+	TargetOrganization = "organization"
+)
+
+// Log represents the db model for an audit log record. Records are append-only.
+type Log struct {
+	model.Base
+
+	ActorID    *string `gorm:"type:char(26);index"` // nil for anonymous actions (e.g. failed logins)
+	Action     string  `gorm:"type:varchar(64);not null;index"`
+	TargetType string  `gorm:"type:varchar(32);not null"`
+	TargetID   string  `gorm:"type:varchar(64);not null;default:''"`
+	IPAddress  string  `gorm:"type:varchar(45);not null;default:''"`
+	UserAgent  string  `gorm:"type:text;not null;default:''"`
+	RequestID  string  `gorm:"type:varchar(64);not null;default:''"`
+	Metadata   string  `gorm:"type:jsonb;not null;default:'{}'"`
+}
+
+// TableName specifies the table name for the Log model
+func (Log) TableName() string {
+	return "audit_logs"
+}
+
+// Entry describes an action to record. The actor and request details are taken from the context.
+type Entry struct {
+	Action     string
+	TargetType string
+	TargetID   string
+	Metadata   map[string]any
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+
+	"gorm.io/gorm"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	repo "github.com/mrhpn/go-rest-api/internal/repository"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// Recorder writes audit log records.
+type Recorder interface {
+	// Record stores the entry, attributing it to the authenticated user and request found in ctx.
+	// Under impersonation the admin is the actor and the impersonated user is noted as on_behalf_of.
+	Record(ctx context.Context, entry Entry) error
+}
+
+// dbRecorder implements the Recorder interface on top of the audit_logs table.
+type dbRecorder struct {
+	repo.Base
+}
+
+// NewRecorder constructs a Recorder backed by a GORM database.
+func NewRecorder(db *gorm.DB) Recorder {
+	return &dbRecorder{
+		Base: repo.Base{
+			DBInstance: db,
+		},
+	}
+}
+
+func (r *dbRecorder) Record(ctx context.Context, entry Entry) error {
+	claims, authenticated := security.ClaimsFromContext(ctx)
+	if authenticated && claims.IsImpersonated() {
+		// the admin behind an impersonation token is the actor, the impersonated user is kept alongside
+		entry.Metadata = maps.Clone(entry.Metadata)
+		if entry.Metadata == nil {
+			entry.Metadata = make(map[string]any, 1)
+		}
+		entry.Metadata["on_behalf_of"] = claims.UserID
+	}
+
+	metadata := []byte("{}")
+	if len(entry.Metadata) > 0 {
+		var err error
+		if metadata, err = json.Marshal(entry.Metadata); err != nil {
+			return apperror.Wrap(
+				apperror.Internal,
+				apperror.ErrInternal.Code,
+				"failed to encode audit metadata",
+				err,
+			)
+		}
+	}
+
+	info := requestInfoFromContext(ctx)
+	record := &Log{
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		IPAddress:  info.IPAddress,
+		UserAgent:  info.UserAgent,
+		RequestID:  info.RequestID,
+		Metadata:   string(metadata),
+	}
+	switch {
+	case authenticated && claims.IsImpersonated():
+		record.ActorID = &claims.Actor.UserID
+	case authenticated:
+		record.ActorID = &claims.UserID
+	}
+
+	if err := r.DB(ctx).Create(record).Error; err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to create audit log",
+			err,
+		)
+	}
+	return nil
+}
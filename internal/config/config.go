@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/security"
 )
 
 // Config represents the full application configuration loaded at startup.
@@ -21,8 +23,16 @@ type Config struct {
 	DB        DBConfig
 	Redis     RedisConfig
 	JWT       JWTConfig
+	Auth      AuthConfig
+	Password  PasswordConfig
+	OIDC      OIDCConfig
+	RBAC      RBACConfig
+	Tenancy   TenancyConfig
 	Log       LogConfig
 	Storage   StorageConfig
+	Mail      MailConfig
+	Privacy   PrivacyConfig
+	Retention RetentionConfig
 }
 
 // HTTPConfig represents the http-related config
@@ -64,9 +74,95 @@ type RedisConfig struct {
 
 // JWTConfig represents app's auth (jwt) related config
 type JWTConfig struct {
-	Secret                       string
-	AccessTokenExpirationSecond  int // in seconds
-	RefreshTokenExpirationSecond int // in seconds
+	Secret                       string // HS256 shared secret, optional once PrivateKeyPath is set
+	AccessTokenExpirationSecond  int    // in seconds
+	RefreshTokenExpirationSecond int    // in seconds
+
+	KeyID            string       // kid of the signing key, defaults to a fingerprint for asymmetric keys
+	PrivateKeyPath   string       // PEM RSA/ECDSA/Ed25519 private key, switches signing from HS256 to RS256/ES256/EdDSA
+	VerificationKeys []JWTKeyFile // retired public keys still accepted during rotation
+
+	Issuer       string   // iss of issued tokens, required on validation
+	Audience     []string // aud of issued tokens, the first one is required on validation
+	LeewaySecond int      // tolerated clock skew between services
+}
+
+// JWTKeyFile points to a PEM key file with an optional kid
+type JWTKeyFile struct {
+	ID   string
+	Path string
+}
+
+// AuthConfig represents app's account-flow (password reset, etc.) related config
+type AuthConfig struct {
+	PasswordResetURL                   string // frontend page that receives the reset token as ?token=
+	PasswordResetTokenExpirationSecond int    // in seconds
+
+	RegistrationEnabled                    bool   // enables public sign-up at /auth/register
+	EmailVerificationURL                   string // frontend page that receives the verification token as ?token=
+	EmailVerificationTokenExpirationSecond int    // in seconds
+
+	EmailChangeURL                   string // frontend page that receives the email change token as ?token=
+	EmailChangeTokenExpirationSecond int    // in seconds
+
+	MFAIssuer        string          // name shown in authenticator apps
+	MFARequiredRoles []security.Role // roles that cannot log in without a second factor
+
+	LoginMaxFailedAttempts     int // failed logins within the window that lock an account
+	LoginFailureWindowSecond   int // in seconds
+	LoginLockoutDurationSecond int // in seconds, locked accounts unlock automatically afterwards
+	LoginDelayBaseMillisecond  int // delay after the first failure, doubled after each further failure
+	LoginDelayMaxMillisecond   int // upper bound of the progressive delay
+
+	DormantAfterDays            int // users who haven't logged in for this many days are marked dormant. 0 = disabled
+	DormancyCheckIntervalSecond int // in seconds, how often the dormancy job runs
+}
+
+// PasswordConfig represents password hashing and password policy related config
+type PasswordConfig struct {
+	HashAlgorithm     string // argon2id | bcrypt, used for new hashes. older hashes are upgraded on login
+	BcryptCost        int
+	Argon2MemoryKiB   int
+	Argon2Iterations  int
+	Argon2Parallelism int
+
+	MinLength        int
+	MaxLength        int
+	MinCharClasses   int    // out of lowercase, uppercase, digits, symbols. 0 disables the rule
+	RejectEmail      bool   // reject passwords containing the account's email
+	MinStrength      int    // estimated strength score from 0 (very weak) to 4 (very strong). 0 disables the rule
+	BreachedListPath string // file with SHA-1 hashes of breached passwords, empty disables the check
+}
+
+// OIDCConfig represents OpenID Connect login related config
+type OIDCConfig struct {
+	Providers          []OIDCProviderConfig
+	SuccessRedirectURL string // frontend page the browser returns to after an oidc login
+	AutoRegister       bool   // create accounts for unknown verified emails instead of rejecting the login
+}
+
+// OIDCProviderConfig represents a single OpenID Connect identity provider
+type OIDCProviderConfig struct {
+	Name         string // lowercase, used in urls, e.g. /auth/oidc/google/login
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // <OIDC_CALLBACK_BASE_URL>/<name>/callback, must be registered at the provider
+	Scopes       []string
+}
+
+// RBACConfig represents the role to permission mapping related config
+type RBACConfig struct {
+	Source          string                                  // config | database, database rows replace the mapping of their roles
+	RolePermissions map[security.Role][]security.Permission // defaults, overridden per role by RBAC_PERMISSIONS_<ROLE>
+}
+
+// TenancyConfig represents multi-tenant (organization) related config
+type TenancyConfig struct {
+	Header           string // request header naming the organization by id or slug
+	BaseDomain       string // resolves <slug>.<BaseDomain> hosts to organizations, empty disables subdomains
+	Required         bool   // reject requests to tenant-scoped endpoints that don't name an organization, instead of scoping them to rows of no organization
+	RowLevelSecurity bool   // also set the organization in the postgres session for row-level security policies, implies Required
 }
 
 // LogConfig represents app's logger related config
@@ -90,24 +186,40 @@ type StorageConfig struct {
 	LocalPath  string
 }
 
+// MailConfig represents app's mailer related config
+type MailConfig struct {
+	Provider string // log | file
+	From     string
+	FilePath string // directory used by the file provider
+}
+
+// PrivacyConfig represents personal data export & erasure related config
+type PrivacyConfig struct {
+	ExportRetentionDays int    // ready export archives are deleted after this many days
+	ErasureGraceDays    int    // days between an erasure request and the erasure, during which it can be cancelled
+	EraseProfile        string // anonymize | delete, what happens to the user record
+	ErasePosts          string // anonymize | delete, anonymize keeps the posts under the anonymized user
+	JobIntervalSecond   int    // in seconds, how often pending exports and due erasures are processed
+}
+
+// RetentionConfig represents the purge of soft-deleted records
+type RetentionConfig struct {
+	DeletedUsersDays int // users deleted longer ago are removed for good (with their content). 0 = kept forever
+	DeletedPostsDays int // posts deleted longer ago are removed for good. 0 = kept forever
+	BatchSize        int // rows removed per statement
+	IntervalSecond   int // in seconds, how often the purge runs
+}
+
 // Load loads the application configuration from environment variables.
 // It returns an error if any required configuration is missing.
 func Load() (*Config, error) {
-	originsRaw := getEnv("ALLOWED_ORIGINS", "*")
-	var allowedOrigins []string
-	if originsRaw == "*" {
-		allowedOrigins = []string{"*"}
-	} else {
-		allowedOrigins = strings.Split(originsRaw, ",")
-	}
-
 	cfg := &Config{
 		AppEnv: getEnv("APP_ENV", constants.EnvDev),
 		Port:   getEnv("APP_PORT", "8080"),
 		DBURL:  getEnv("DATABASE_URL", ""),
 
 		HTTP: HTTPConfig{
-			AllowedOrigins:       allowedOrigins,
+			AllowedOrigins:       getEnvAsOrigins("ALLOWED_ORIGINS"),
 			MaxRequestBodySize:   constants.RequestMaxBodySizeMB,
 			RequestTimeoutSecond: constants.RequestTimeoutSecond,
 		},
@@ -144,6 +256,27 @@ func Load() (*Config, error) {
 			Secret:                       getEnv("JWT_SECRET", ""),
 			AccessTokenExpirationSecond:  getEnvAsInt("ACCESS_TOKEN_EXPIRATION_TIME_SECOND", constants.AccessTokenExpirationSecond),
 			RefreshTokenExpirationSecond: getEnvAsInt("REFRESH_TOKEN_EXPIRATION_TIME_SECOND", constants.RefreshTokenExpirationSecond),
+			KeyID:                        getEnv("JWT_KEY_ID", ""),
+			PrivateKeyPath:               getEnv("JWT_PRIVATE_KEY_PATH", ""),
+			VerificationKeys:             getEnvAsKeyFiles("JWT_VERIFICATION_KEYS"),
+			Issuer:                       getEnv("JWT_ISSUER", constants.JWTDefaultIssuer),
+			Audience:                     getEnvAsList("JWT_AUDIENCE", constants.JWTDefaultAudience),
+			LeewaySecond:                 getEnvAsInt("JWT_LEEWAY_SECOND", constants.JWTLeewaySecond),
+		},
+
+		Auth: loadAuthConfig(),
+
+		Password: loadPasswordConfig(),
+
+		OIDC: loadOIDCConfig(),
+
+		RBAC: loadRBACConfig(),
+
+		Tenancy: TenancyConfig{
+			Header:           getEnv("TENANCY_HEADER", constants.TenantHeaderName),
+			BaseDomain:       strings.ToLower(strings.TrimPrefix(getEnv("TENANCY_BASE_DOMAIN", ""), ".")),
+			Required:         getEnvAsBool("TENANCY_REQUIRED", false),
+			RowLevelSecurity: getEnvAsBool("TENANCY_ROW_LEVEL_SECURITY", false),
 		},
 
 		Log: LogConfig{
@@ -164,28 +297,259 @@ func Load() (*Config, error) {
 			UseSSL:     getEnvAsBool("STORAGE_USE_SSL", false),
 			LocalPath:  getEnv("STORAGE_LOCAL_PATH", "./uploads"),
 		},
+
+		Mail: MailConfig{
+			Provider: getEnv("MAIL_PROVIDER", constants.MailProviderLog),
+			From:     getEnv("MAIL_FROM", "no-reply@localhost"),
+			FilePath: getEnv("MAIL_FILE_PATH", "./mails"),
+		},
+
+		Privacy: PrivacyConfig{
+			ExportRetentionDays: getEnvAsInt("PRIVACY_EXPORT_RETENTION_DAYS", constants.DataExportRetentionDays),
+			ErasureGraceDays:    getEnvAsInt("PRIVACY_ERASURE_GRACE_DAYS", constants.ErasureGracePeriodDays),
+			EraseProfile:        strings.ToLower(getEnv("PRIVACY_ERASE_PROFILE", constants.ErasureModeAnonymize)),
+			ErasePosts:          strings.ToLower(getEnv("PRIVACY_ERASE_POSTS", constants.ErasureModeDelete)),
+			JobIntervalSecond:   getEnvAsInt("PRIVACY_JOB_INTERVAL_SECOND", constants.PrivacyJobIntervalSecond),
+		},
+
+		Retention: RetentionConfig{
+			DeletedUsersDays: getEnvAsInt("RETENTION_DELETED_USERS_DAYS", constants.RetentionDeletedUsersDays),
+			DeletedPostsDays: getEnvAsInt("RETENTION_DELETED_POSTS_DAYS", constants.RetentionDeletedPostsDays),
+			BatchSize:        getEnvAsInt("RETENTION_BATCH_SIZE", constants.RetentionBatchSize),
+			IntervalSecond:   getEnvAsInt("RETENTION_INTERVAL_SECOND", constants.RetentionIntervalSecond),
+		},
 	}
 
-	if cfg.DBURL == "" {
-		return nil, errors.New("env: DATABASE_URL is missing")
+	if err := cfg.validate(); err != nil {
+		return nil, err
 	}
-	if cfg.JWT.Secret == "" || len(cfg.JWT.Secret) < constants.JWTSecretMinLength {
-		return nil, fmt.Errorf("env: JWT_SECRET is missing or less than %d characters", constants.JWTSecretMinLength)
+
+	return cfg, nil
+}
+
+// loadAuthConfig loads the account-flow related config
+func loadAuthConfig() AuthConfig {
+	return AuthConfig{
+		PasswordResetURL:                   getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+		PasswordResetTokenExpirationSecond: getEnvAsInt("PASSWORD_RESET_TOKEN_EXPIRATION_SECOND", constants.PasswordResetTokenExpirationSecond),
+
+		RegistrationEnabled:  getEnvAsBool("AUTH_REGISTRATION_ENABLED", false),
+		EmailVerificationURL: getEnv("EMAIL_VERIFICATION_URL", "http://localhost:3000/verify-email"),
+		EmailVerificationTokenExpirationSecond: getEnvAsInt(
+			"EMAIL_VERIFICATION_TOKEN_EXPIRATION_SECOND",
+			constants.EmailVerificationTokenExpirationSecond,
+		),
+
+		EmailChangeURL:                   getEnv("EMAIL_CHANGE_URL", "http://localhost:3000/confirm-email"),
+		EmailChangeTokenExpirationSecond: getEnvAsInt("EMAIL_CHANGE_TOKEN_EXPIRATION_SECOND", constants.EmailChangeTokenExpirationSecond),
+
+		MFAIssuer:        getEnv("MFA_ISSUER", "go-rest-api"),
+		MFARequiredRoles: getEnvAsRoles("MFA_REQUIRED_ROLES"),
+
+		LoginMaxFailedAttempts:     getEnvAsInt("LOGIN_MAX_FAILED_ATTEMPTS", constants.LoginMaxFailedAttempts),
+		LoginFailureWindowSecond:   getEnvAsInt("LOGIN_FAILURE_WINDOW_SECOND", constants.LoginFailureWindowSecond),
+		LoginLockoutDurationSecond: getEnvAsInt("LOGIN_LOCKOUT_DURATION_SECOND", constants.LoginLockoutDurationSecond),
+		LoginDelayBaseMillisecond:  getEnvAsInt("LOGIN_DELAY_BASE_MILLISECOND", constants.LoginDelayBaseMillisecond),
+		LoginDelayMaxMillisecond:   getEnvAsInt("LOGIN_DELAY_MAX_MILLISECOND", constants.LoginDelayMaxMillisecond),
+
+		DormantAfterDays:            getEnvAsInt("USER_DORMANT_AFTER_DAYS", constants.UserDormantAfterDays),
+		DormancyCheckIntervalSecond: getEnvAsInt("USER_DORMANCY_CHECK_INTERVAL_SECOND", constants.UserDormancyCheckIntervalSecond),
+	}
+}
+
+// loadPasswordConfig loads the password hashing and password policy related config
+func loadPasswordConfig() PasswordConfig {
+	return PasswordConfig{
+		HashAlgorithm:     strings.ToLower(getEnv("PASSWORD_HASH_ALGORITHM", constants.PasswordHashAlgorithm)),
+		BcryptCost:        getEnvAsInt("PASSWORD_BCRYPT_COST", constants.DefaultBcryptCost),
+		Argon2MemoryKiB:   getEnvAsInt("PASSWORD_ARGON2_MEMORY_KIB", constants.Argon2MemoryKiB),
+		Argon2Iterations:  getEnvAsInt("PASSWORD_ARGON2_ITERATIONS", constants.Argon2Iterations),
+		Argon2Parallelism: getEnvAsInt("PASSWORD_ARGON2_PARALLELISM", constants.Argon2Parallelism),
+
+		MinLength:        getEnvAsInt("PASSWORD_MIN_LENGTH", constants.PasswordMinLength),
+		MaxLength:        getEnvAsInt("PASSWORD_MAX_LENGTH", constants.PasswordMaxLength),
+		MinCharClasses:   getEnvAsInt("PASSWORD_MIN_CHAR_CLASSES", constants.PasswordMinCharClasses),
+		RejectEmail:      getEnvAsBool("PASSWORD_REJECT_EMAIL", true),
+		MinStrength:      getEnvAsInt("PASSWORD_MIN_STRENGTH", constants.PasswordMinStrength),
+		BreachedListPath: getEnv("PASSWORD_BREACHED_LIST_PATH", ""),
 	}
-	switch strings.ToLower(cfg.Storage.Provider) {
+}
+
+// loadOIDCConfig loads the OpenID Connect providers listed in OIDC_PROVIDERS. Every provider
+// is configured with OIDC_<NAME>_* variables, e.g. OIDC_GOOGLE_ISSUER for the provider "google"
+func loadOIDCConfig() OIDCConfig {
+	callbackBaseURL := strings.TrimSuffix(getEnv("OIDC_CALLBACK_BASE_URL", "http://localhost:8080"+constants.APIAuthPath+"/oidc"), "/")
+
+	cfg := OIDCConfig{
+		SuccessRedirectURL: getEnv("OIDC_SUCCESS_REDIRECT_URL", "http://localhost:3000/auth/callback"),
+		AutoRegister:       getEnvAsBool("OIDC_AUTO_REGISTER", false),
+	}
+	for _, name := range getEnvAsList("OIDC_PROVIDERS", "") {
+		name = strings.ToLower(name)
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		cfg.Providers = append(cfg.Providers, OIDCProviderConfig{
+			Name:         name,
+			Issuer:       getEnv(prefix+"ISSUER", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  callbackBaseURL + "/" + name + "/callback",
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", constants.OIDCDefaultScopes)),
+		})
+	}
+	return cfg
+}
+
+// loadRBACConfig loads the role to permission mapping. RBAC_PERMISSIONS_<ROLE> replaces the default
+// permissions of a role, e.g. RBAC_PERMISSIONS_EMPLOYEE=users:read,posts:*
+func loadRBACConfig() RBACConfig {
+	cfg := RBACConfig{
+		Source:          strings.ToLower(getEnv("RBAC_SOURCE", constants.RBACSourceConfig)),
+		RolePermissions: security.DefaultRolePermissions(),
+	}
+	for _, role := range security.AllRoles() {
+		key := "RBAC_PERMISSIONS_" + strings.ToUpper(role.String())
+		if _, ok := os.LookupEnv(key); !ok {
+			continue
+		}
+		// set but empty revokes every permission of the role
+		permissions := []security.Permission{}
+		for _, p := range getEnvAsList(key, "") {
+			permissions = append(permissions, security.Permission(strings.ToLower(p)))
+		}
+		cfg.RolePermissions[role] = permissions
+	}
+	return cfg
+}
+
+// validate reports the first missing or invalid setting
+func (c *Config) validate() error {
+	if c.DBURL == "" {
+		return errors.New("env: DATABASE_URL is missing")
+	}
+	if (c.JWT.PrivateKeyPath == "" || c.JWT.Secret != "") && len(c.JWT.Secret) < constants.JWTSecretMinLength {
+		return fmt.Errorf("env: JWT_SECRET is missing or less than %d characters", constants.JWTSecretMinLength)
+	}
+	if c.JWT.Issuer == "" || len(c.JWT.Audience) == 0 {
+		return errors.New("env: JWT_ISSUER and JWT_AUDIENCE must not be empty")
+	}
+	switch strings.ToLower(c.Storage.Provider) {
 	case "minio":
-		if cfg.Storage.Host == "" {
-			return nil, errors.New("env: STORAGE_HOST is missing")
+		if c.Storage.Host == "" {
+			return errors.New("env: STORAGE_HOST is missing")
 		}
 	case "local":
-		if cfg.Storage.LocalPath == "" {
-			return nil, errors.New("env: STORAGE_LOCAL_PATH is missing")
+		if c.Storage.LocalPath == "" {
+			return errors.New("env: STORAGE_LOCAL_PATH is missing")
 		}
 	default:
-		return nil, errors.New("env: STORAGE_PROVIDER is invalid (should be minio | local)")
+		return errors.New("env: STORAGE_PROVIDER is invalid (should be minio | local)")
+	}
+	switch strings.ToLower(c.Mail.Provider) {
+	case constants.MailProviderLog, constants.MailProviderFile:
+	default:
+		return errors.New("env: MAIL_PROVIDER is invalid (should be log | file)")
+	}
+	if err := c.Password.validate(); err != nil {
+		return err
+	}
+	if err := c.Privacy.validate(); err != nil {
+		return err
+	}
+	if c.Retention.DeletedUsersDays < 0 || c.Retention.DeletedPostsDays < 0 || c.Retention.BatchSize < 1 {
+		return errors.New("env: RETENTION_DELETED_*_DAYS must not be negative and RETENTION_BATCH_SIZE at least 1")
+	}
+	if err := c.OIDC.validate(); err != nil {
+		return err
+	}
+	if err := c.RBAC.validate(); err != nil {
+		return err
+	}
+	if c.Auth.LoginMaxFailedAttempts < 1 {
+		return errors.New("env: LOGIN_MAX_FAILED_ATTEMPTS must be at least 1")
+	}
+	for _, role := range c.Auth.MFARequiredRoles {
+		if !security.IsValidRole(role) {
+			return fmt.Errorf("env: MFA_REQUIRED_ROLES contains an invalid role %q", role)
+		}
 	}
 
-	return cfg, nil
+	return nil
+}
+
+// validate reports unknown erasure modes and combinations that can't be applied
+func (c *PrivacyConfig) validate() error {
+	for name, mode := range map[string]string{
+		"PRIVACY_ERASE_PROFILE": c.EraseProfile,
+		"PRIVACY_ERASE_POSTS":   c.ErasePosts,
+	} {
+		if mode != constants.ErasureModeAnonymize && mode != constants.ErasureModeDelete {
+			return fmt.Errorf("env: %s is invalid (should be anonymize | delete)", name)
+		}
+	}
+	// deleting the user cascades to its posts, there is nothing left to keep them under
+	if c.EraseProfile == constants.ErasureModeDelete && c.ErasePosts == constants.ErasureModeAnonymize {
+		return errors.New("env: PRIVACY_ERASE_POSTS=anonymize requires PRIVACY_ERASE_PROFILE=anonymize")
+	}
+	if c.ExportRetentionDays < 1 || c.ErasureGraceDays < 0 {
+		return errors.New("env: PRIVACY_EXPORT_RETENTION_DAYS must be at least 1 and PRIVACY_ERASURE_GRACE_DAYS not negative")
+	}
+	return nil
+}
+
+// validate reports an unusable password policy
+func (c *PasswordConfig) validate() error {
+	if c.MinLength < 1 || c.MaxLength < c.MinLength {
+		return errors.New("env: PASSWORD_MIN_LENGTH must be at least 1 and not above PASSWORD_MAX_LENGTH")
+	}
+	if c.HashAlgorithm == security.PasswordHashBcrypt && c.MaxLength > constants.BcryptMaxPasswordBytes {
+		return fmt.Errorf("env: PASSWORD_MAX_LENGTH must be at most %d with bcrypt", constants.BcryptMaxPasswordBytes)
+	}
+	if c.MinCharClasses < 0 || c.MinCharClasses > constants.PasswordCharClassCount {
+		return fmt.Errorf("env: PASSWORD_MIN_CHAR_CLASSES must be between 0 and %d", constants.PasswordCharClassCount)
+	}
+	if c.MinStrength < 0 || c.MinStrength > constants.PasswordMaxStrength {
+		return fmt.Errorf("env: PASSWORD_MIN_STRENGTH must be between 0 and %d", constants.PasswordMaxStrength)
+	}
+	return nil
+}
+
+// validate reports an incomplete identity provider
+func (c *OIDCConfig) validate() error {
+	seen := make(map[string]bool, len(c.Providers))
+	for _, p := range c.Providers {
+		if strings.Trim(p.Name, "abcdefghijklmnopqrstuvwxyz0123456789-") != "" {
+			return fmt.Errorf("env: OIDC_PROVIDERS contains an invalid name %q (use a-z, 0-9 and -)", p.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("env: OIDC_PROVIDERS contains %q twice", p.Name)
+		}
+		seen[p.Name] = true
+
+		if p.Issuer == "" || p.ClientID == "" {
+			return fmt.Errorf("env: issuer and client id of oidc provider %q are missing", p.Name)
+		}
+		if !slices.Contains(p.Scopes, "openid") {
+			return fmt.Errorf("env: scopes of oidc provider %q must include openid", p.Name)
+		}
+	}
+	return nil
+}
+
+// validate reports an unknown source or permission
+func (c *RBACConfig) validate() error {
+	switch c.Source {
+	case constants.RBACSourceConfig, constants.RBACSourceDatabase:
+	default:
+		return errors.New("env: RBAC_SOURCE is invalid (should be config | database)")
+	}
+	for role, permissions := range c.RolePermissions {
+		for _, p := range permissions {
+			if !security.IsValidPermission(p) {
+				return fmt.Errorf("env: RBAC_PERMISSIONS_%s contains an invalid permission %q", strings.ToUpper(role.String()), p)
+			}
+		}
+	}
+	return nil
 }
 
 func getEnv(key, fallback string) string {
@@ -210,3 +574,50 @@ func getEnvAsBool(key string, fallback bool) bool {
 	}
 	return fallback
 }
+
+// getEnvAsOrigins parses a comma separated list of CORS origins, "*" (default) allows any origin
+func getEnvAsOrigins(key string) []string {
+	originsRaw := getEnv(key, "*")
+	if originsRaw == "*" {
+		return []string{"*"}
+	}
+	return strings.Split(originsRaw, ",")
+}
+
+// getEnvAsList parses a comma separated list, ignoring empty entries
+func getEnvAsList(key, fallback string) []string {
+	var list []string
+	for _, v := range strings.Split(getEnv(key, fallback), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// getEnvAsRoles parses a comma separated list of roles, e.g. "superadmin,admin"
+func getEnvAsRoles(key string) []security.Role {
+	var roles []security.Role
+	for _, r := range getEnvAsList(key, "") {
+		roles = append(roles, security.Role(strings.ToLower(r)))
+	}
+	return roles
+}
+
+// getEnvAsKeyFiles parses a comma separated list of key files, each optionally prefixed with its kid,
+// e.g. "2025-01=/keys/old.pub,/keys/older.pub"
+func getEnvAsKeyFiles(key string) []JWTKeyFile {
+	var files []JWTKeyFile
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if id, path, ok := strings.Cut(entry, "="); ok {
+			files = append(files, JWTKeyFile{ID: strings.TrimSpace(id), Path: strings.TrimSpace(path)})
+			continue
+		}
+		files = append(files, JWTKeyFile{Path: entry})
+	}
+	return files
+}
@@ -33,7 +33,22 @@ const (
 
 // Security constants
 const (
-	DefaultBcryptCost = 14
+	DefaultBcryptCost = 14 // only used when PASSWORD_HASH_ALGORITHM=bcrypt
+
+	PasswordHashAlgorithm = "argon2id"
+	Argon2MemoryKiB       = 19456 // 19 MiB, OWASP minimum recommendation for argon2id
+	Argon2Iterations      = 2
+	Argon2Parallelism     = 1
+	Argon2SaltLength      = 16
+	Argon2KeyLength       = 32
+
+	PasswordMinLength      = 8
+	PasswordMaxLength      = 128
+	PasswordMinCharClasses = 3 // out of lowercase, uppercase, digits, symbols
+	PasswordCharClassCount = 4
+	PasswordMinStrength    = 2 // 0 (very weak) to 4 (very strong)
+	PasswordMaxStrength    = 4
+	BcryptMaxPasswordBytes = 72 // bcrypt ignores everything after the first 72 bytes
 
 	RateLimit     = "100-M" // 100 requests per minute
 	RateLimitAuth = "7-M"   // 7 requests per minute for auth routes
@@ -42,11 +57,119 @@ const (
 	RefreshTokenExpirationSecond = 86400
 
 	JWTSecretMinLength = 32
+	JWTKeyIDByteLength = 8 // default kid is the first bytes of the public key's sha256 fingerprint
+	RSAMinKeyBits      = 2048
+
+	JWTDefaultIssuer   = "go-rest-api"
+	JWTDefaultAudience = "go-rest-api"
+	JWTLeewaySecond    = 30
+
+	JWKSCacheMaxAgeSecond = 300 // lets verifiers pick up rotated keys within minutes
 
 	RateLimitKey       = "ratelimit"
 	RateLimitKeyPrefix = RateLimitKey + ":"
 
 	RefreshTokenCookieName = "refresh_token"
+
+	RevokedTokenKeyPrefix   = "auth:revoked:jti:"
+	RevokedUserKeyPrefix    = "auth:revoked:user:"
+	RevokedSessionKeyPrefix = "auth:revoked:sid:"
+
+	LockoutFailuresKeyPrefix = "auth:lockout:failures:"
+	LockoutDelayKeyPrefix    = "auth:lockout:delay:"
+	LockoutLockKeyPrefix     = "auth:lockout:lock:"
+
+	LoginMaxFailedAttempts     = 5
+	LoginFailureWindowSecond   = 900 // 15 minutes
+	LoginLockoutDurationSecond = 900 // 15 minutes
+	LoginDelayBaseMillisecond  = 500
+	LoginDelayMaxMillisecond   = 30000
+
+	UserDormantAfterDays            = 180
+	UserDormancyCheckIntervalSecond = 3600 // 1 hour
+
+	UserImportMaxRows     = 1000 // every password is hashed within the request
+	UserImportMaxFileSize = 5 * MB
+	UserExportFlushRows   = 500 // rows written between flushes of a streamed export
+	UserBulkMaxTargets    = 500 // users changed by one bulk action, keep it within a request
+
+	UserTokenByteLength                = 32
+	PasswordResetTokenExpirationSecond = 1800 // 30 minutes
+
+	EmailVerificationTokenExpirationSecond = 86400 // 24 hours
+	EmailChangeTokenExpirationSecond       = 3600  // 1 hour
+
+	APIKeyTokenPrefix              = "gra_" // makes leaked keys easy to recognize by secret scanners
+	APIKeyLookupByteLength         = 6      // hex encoded public part used to find the key
+	APIKeySecretByteLength         = 32
+	APIKeyDefaultExpirationDay     = 90
+	APIKeyMaxExpirationDay         = 365
+	APIKeyMaxPerUser               = 20
+	APIKeyLastUsedResolutionSecond = 60 // last_used_at is updated at most once per minute
+	APIKeyHeaderName               = "X-API-Key"
+	APIKeyAuthScheme               = "ApiKey "
+
+	OIDCFlowCookieName           = "oidc_flow"
+	OIDCFlowExpirationSecond     = 600 // 10 minutes to sign in at the identity provider
+	OIDCFlowByteLength           = 32  // entropy of state, nonce and pkce code verifier
+	OIDCDefaultScopes            = "openid email profile"
+	OIDCHTTPTimeoutSecond        = 10
+	OIDCMaxResponseBytes         = 1 << 20 // 1 MiB, discovery documents and key sets are far smaller
+	OIDCKeyRefreshIntervalSecond = 60      // unknown kids refetch the provider's keys at most once a minute
+
+	ImpersonationTokenExpirationSecond = 900 // 15 minutes, cannot be refreshed
+
+	MFAChallengeTokenExpirationSecond = 300 // 5 minutes
+	MFARecoveryCodeCount              = 10
+	MFARecoveryCodeByteLength         = 5 // 8 base32 characters
+
+	TOTPSecretByteLength = 20 // 160 bits as recommended by RFC 4226
+	TOTPDigits           = 6
+	TOTPPeriodSecond     = 30
+	TOTPSkewSteps        = 1 // accept codes from one step before/after to tolerate clock drift
+)
+
+// Authorization constants
+const (
+	RBACSourceConfig   = "config"   // role permissions from defaults & RBAC_PERMISSIONS_<ROLE>
+	RBACSourceDatabase = "database" // role permissions from the role_permissions table, on top of config
+)
+
+// Tenancy constants
+const (
+	TenantHeaderName = "X-Organization" // id or slug of the organization a request acts in
+
+	// TenantSettingName is the postgres setting row-level security policies compare organization_id with
+	TenantSettingName = "app.organization_id"
+	// TenantBypassSettingName is the postgres setting that lets a session see the rows of every organization
+	// despite row-level security. The app's connections set it, request transactions of a tenant reset it.
+	TenantBypassSettingName = "app.bypass_tenancy"
+)
+
+// Mail constants
+const (
+	MailProviderLog  = "log"
+	MailProviderFile = "file"
+)
+
+// Privacy constants
+const (
+	ErasureModeAnonymize = "anonymize" // personal data is scrubbed, the record stays
+	ErasureModeDelete    = "delete"    // the record is removed
+
+	DataExportRetentionDays    = 7
+	ErasureGracePeriodDays     = 30
+	PrivacyJobIntervalSecond   = 60
+	PrivacyJobBatchSize        = 10
+	DataExportStaleAfterMinute = 30 // processing exports not finished by then are retried
+)
+
+// Retention constants
+const (
+	RetentionDeletedUsersDays = 90
+	RetentionDeletedPostsDays = 90
+	RetentionBatchSize        = 500
+	RetentionIntervalSecond   = 3600 // 1 hour
 )
 
 // Media constants
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/rs/zerolog/log"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -145,8 +147,13 @@ func connectWithRetry(dsn string, gormConfig *gorm.Config, dbCfg *config.DBConfi
 		retryDelay = constants.DBRetryDelaySecond * time.Second
 	}
 
+	dialector, err := openPostgres(dsn)
+	if err != nil {
+		return nil, err
+	}
+
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		db, err = gorm.Open(postgres.Open(dsn), gormConfig)
+		db, err = gorm.Open(dialector, gormConfig)
 		if err == nil {
 			break
 		}
@@ -170,6 +177,19 @@ func connectWithRetry(dsn string, gormConfig *gorm.Config, dbCfg *config.DBConfi
 	return db, nil
 }
 
+// openPostgres prepares the connections of the app. They bypass the row-level security of tenant-owned
+// tables, which denies every row to sessions without an organization: platform code (jobs, auth, ...)
+// sees every organization, only request transactions scoped to one turn the bypass off.
+func openPostgres(dsn string) (gorm.Dialector, error) {
+	connCfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database url: %w", err)
+	}
+	connCfg.RuntimeParams[constants.TenantBypassSettingName] = "on"
+
+	return postgres.New(postgres.Config{Conn: stdlib.OpenDB(*connCfg)}), nil
+}
+
 type poolConfig struct {
 	maxOpenConns    int
 	maxIdleConns    int
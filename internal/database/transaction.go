@@ -16,12 +16,17 @@ type txKey struct{}
 //   - Rolls back if fn returns an error
 //   - Rolls back if fn panics
 //   - Propagates context cancellation to all queries
+//   - Within a transaction already in ctx, uses a savepoint instead, so an error only rolls back
+//     the work of fn and leaves the outer transaction usable
 //
 // This is a thin wrapper around gorm.DB.Transaction.
 func Transaction(
 	ctx context.Context,
 	db *gorm.DB,
 	fn func(context.Context) error) error {
+	if tx := GetTx(ctx); tx != nil {
+		db = tx
+	}
 	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// inject the transaction in the context
 		txCtx := context.WithValue(ctx, txKey{}, tx)
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 
 	"github.com/mrhpn/go-rest-api/internal/apperror"
@@ -39,6 +40,15 @@ func BindAndValidateQuery(c *gin.Context, req any) error {
 	return nil
 }
 
+// ValidateStruct validates a struct decoded by other means than binding (e.g. a row of an uploaded
+// file) with the binding rules, and reports failures like the Bind functions do.
+func ValidateStruct(req any) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return handleBindingError(err)
+	}
+	return nil
+}
+
 func handleBindingError(err error) error {
 	var ve validator.ValidationErrors
 	if errors.As(err, &ve) {
@@ -2,6 +2,7 @@ package httpx
 
 import (
 	"net/http"
+	"net/url"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
@@ -73,7 +74,36 @@ func Fail(c *gin.Context, status int, code string, message string, fields map[st
 // It automatically logs errors and maps them to appropriate HTTP responses
 func FailWithError(c *gin.Context, err error) {
 	mapped := mapError(err)
+	logError(c, err, mapped)
 
+	Fail(
+		c,
+		mapped.Status,
+		mapped.Code,
+		mapped.Message,
+		mapped.Fields,
+	)
+}
+
+// RedirectWithError is FailWithError for flows that run in the browser instead of an api client,
+// e.g. oidc callbacks. It logs the error and redirects to rawURL with the error code as ?error=
+func RedirectWithError(c *gin.Context, rawURL string, err error) {
+	mapped := mapError(err)
+	logError(c, err, mapped)
+
+	u, parseErr := url.Parse(rawURL)
+	if parseErr != nil {
+		Fail(c, mapped.Status, mapped.Code, mapped.Message, mapped.Fields)
+		return
+	}
+	q := u.Query()
+	q.Set("error", mapped.Code)
+	u.RawQuery = q.Encode()
+
+	c.Redirect(http.StatusFound, u.String())
+}
+
+func logError(c *gin.Context, err error, mapped mappedError) {
 	// Log error with request context (request_id is already in context from RequestID middleware)
 	logger := log.Ctx(ReqCtx(c))
 
@@ -93,12 +123,4 @@ func FailWithError(c *gin.Context, err error) {
 		Str("path", c.Request.URL.Path).
 		Str("method", c.Request.Method).
 		Msg("request failed")
-
-	Fail(
-		c,
-		mapped.Status,
-		mapped.Code,
-		mapped.Message,
-		mapped.Fields,
-	)
 }
@@ -1,16 +1,23 @@
 package httpx
 
 import (
+	"reflect"
+	"strings"
+
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/oklog/ulid/v2"
+
+	"github.com/mrhpn/go-rest-api/internal/security"
 )
 
 // RegisterValidators registers validators used in http-request steps
-func RegisterValidators() {
+func RegisterValidators(passwordPolicy *security.PasswordPolicy) {
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		// Register custom validators here
 		_ = v.RegisterValidation("ulid", validateULID)
+		_ = v.RegisterValidation("slug", validateSlug)
+		registerPasswordValidators(v, passwordPolicy)
 	}
 }
 
@@ -19,3 +26,50 @@ func validateULID(fl validator.FieldLevel) bool {
 	_, err := ulid.Parse(ulidStr)
 	return err == nil
 }
+
+// validateSlug accepts lowercase letters, digits and inner hyphens, so the value is usable in urls and subdomains
+func validateSlug(fl validator.FieldLevel) bool {
+	slug := fl.Field().String()
+	if slug == "" || strings.HasPrefix(slug, "-") || strings.HasSuffix(slug, "-") {
+		return false
+	}
+	return strings.Trim(slug, "abcdefghijklmnopqrstuvwxyz0123456789-") == ""
+}
+
+// registerPasswordValidators registers one validator per password policy rule and the "password"
+// alias that runs all of them. The failing rule is reported as the actual tag (with the configured
+// limit as param), so that a field-level message can explain what is wrong.
+func registerPasswordValidators(v *validator.Validate, policy *security.PasswordPolicy) {
+	rules := policy.Rules()
+	tags := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		_ = v.RegisterValidation(string(rule), func(fl validator.FieldLevel) bool {
+			return policy.Satisfies(rule, fl.Field().String(), siblingEmail(fl))
+		})
+
+		tag := string(rule)
+		if param := policy.Param(rule); param != "" {
+			tag += "=" + param
+		}
+		tags = append(tags, tag)
+	}
+
+	v.RegisterAlias("password", strings.Join(tags, ","))
+}
+
+// siblingEmail returns the Email field of the struct being validated, if it has one
+func siblingEmail(fl validator.FieldLevel) string {
+	parent := fl.Parent()
+	if parent.Kind() == reflect.Pointer {
+		parent = parent.Elem()
+	}
+	if parent.Kind() != reflect.Struct {
+		return ""
+	}
+
+	email := parent.FieldByName("Email")
+	if !email.IsValid() || email.Kind() != reflect.String {
+		return ""
+	}
+	return email.String()
+}
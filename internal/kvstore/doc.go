@@ -0,0 +1,3 @@
+// Package kvstore provides a small expiring key-value store backed by Redis, with an in-memory fallback
+// for single-instance deployments where Redis is disabled.
+package kvstore
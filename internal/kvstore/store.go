@@ -0,0 +1,26 @@
+package kvstore
+
+import (
+	"context"
+	"time"
+)
+
+// Store is a minimal key-value store with per-key expiry, used for short-lived security state.
+type Store interface {
+	// Get returns the value stored at key and whether it exists.
+	Get(ctx context.Context, key string) (string, bool, error)
+
+	// Set stores value at key. A zero ttl keeps the key until it is deleted.
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+
+	// SetNX stores value at key only if the key doesn't exist and reports whether it did. A zero ttl
+	// keeps the key until it is deleted.
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+
+	// Delete removes the given keys. Missing keys are ignored.
+	Delete(ctx context.Context, keys ...string) error
+
+	// Incr atomically increments the integer counter at key and returns the new value.
+	// The ttl is only applied when the counter is created, so it expires a fixed time after the first increment.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
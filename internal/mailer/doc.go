@@ -0,0 +1,2 @@
+// Package mailer provides a pluggable abstraction for sending transactional emails.
+package mailer
@@ -0,0 +1,46 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// fileMailer implements the Mailer interface by writing each email as an .eml file (local development only).
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+// NewFileMailer constructs a Mailer that stores emails as .eml files inside dir.
+func NewFileMailer(from, dir string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	log.Info().Msg("✅ Mailer (file) — emails will be written to " + dir)
+	return &fileMailer{from: from, dir: dir}, nil
+}
+
+func (m *fileMailer) Send(ctx context.Context, msg Message) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	path := filepath.Join(m.dir, ulid.Make().String()+".eml")
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write email file: %w", err)
+	}
+
+	log.Ctx(ctx).Debug().Str("to", msg.To).Str("path", path).Msg("email written to file")
+	return nil
+}
@@ -0,0 +1,28 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// logMailer implements the Mailer interface by writing emails to the application log (local development only).
+type logMailer struct {
+	from string
+}
+
+// NewLogMailer constructs a Mailer that logs emails instead of delivering them.
+func NewLogMailer(from string) Mailer {
+	log.Info().Msg("✅ Mailer (log) — emails will be written to the application log")
+	return &logMailer{from: from}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	log.Ctx(ctx).Info().
+		Str("from", m.from).
+		Str("to", msg.To).
+		Str("subject", msg.Subject).
+		Str("body", msg.Body).
+		Msg("email sent (log mailer)")
+	return nil
+}
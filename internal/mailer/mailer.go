@@ -0,0 +1,16 @@
+package mailer
+
+import "context"
+
+// Message represents a plain-text transactional email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer defines the contract for delivering transactional emails such as password resets.
+type Mailer interface {
+	// Send delivers the message or returns an error if it could not be handed over for delivery.
+	Send(ctx context.Context, msg Message) error
+}
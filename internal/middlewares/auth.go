@@ -12,53 +12,81 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/mrhpn/go-rest-api/internal/app"
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/audit"
+	"github.com/mrhpn/go-rest-api/internal/constants"
 	"github.com/mrhpn/go-rest-api/internal/httpx"
-	"github.com/mrhpn/go-rest-api/internal/modules/auth"
 	"github.com/mrhpn/go-rest-api/internal/security"
 )
 
-type contextKey string
-
-const userKey contextKey = "user_identity"
-
-// RequireAuth validates the JWT and injects claims into the context
+// RequireAuth authenticates the request with a Bearer JWT or an API key
+// (Authorization: ApiKey <key> or X-API-Key) and injects the claims into the context
 func RequireAuth(ctx *app.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 1. check for Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			httpx.Fail(
-				c,
-				http.StatusUnauthorized,
-				auth.ErrUnauthorized.Code,
-				auth.ErrUnauthorized.Message,
-				nil,
-			)
-			return
+		// 1. resolve the caller's identity from the credentials
+		var (
+			claims *security.UserClaims
+			err    error
+		)
+		if apiKey := apiKeyFromRequest(c); apiKey != "" {
+			claims, err = authenticateAPIKey(c, ctx, apiKey)
+		} else {
+			claims, err = authenticateJWT(c, ctx)
 		}
-
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-
-		// 2. parse and validate JWT
-		claims, err := ctx.SecurityHandler.ValidateToken(tokenString)
 		if err != nil {
-			// Map security errors to proper HTTP responses
 			httpx.FailWithError(c, err)
 			return
 		}
 
-		// 3. tag the logger with UserID for better traceability
-		l := log.Ctx(httpx.ReqCtx(c)).
+		// 2. tag the logger with UserID for better traceability
+		lc := log.Ctx(httpx.ReqCtx(c)).
 			With().
 			Str("user_id", claims.UserID).
-			Str("role", string(claims.Role)).
-			Logger()
+			Str("role", string(claims.Role))
+		if claims.APIKeyID != "" {
+			lc = lc.Str("api_key_id", claims.APIKeyID)
+		}
+		if claims.IsImpersonated() {
+			lc = lc.Str("actor_id", claims.Actor.UserID)
+		}
+		l := lc.Logger()
 
-		// 4. inject claims into req context
-		reqCtx := context.WithValue(httpx.ReqCtx(c), userKey, claims)
+		// 3. inject claims into req context
+		reqCtx := security.WithClaims(httpx.ReqCtx(c), claims)
 		c.Request = c.Request.WithContext(l.WithContext(reqCtx))
 
 		c.Next()
+
+		// 4. every request made under impersonation ends up in the audit log
+		if claims.IsImpersonated() {
+			recordImpersonatedRequest(c, ctx, claims)
+		}
+	}
+}
+
+// RejectImpersonation blocks requests made with an impersonation token, e.g. for managing credentials
+// or sessions of the impersonated user. It must run after RequireAuth.
+func RejectImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, ok := security.ClaimsFromContext(httpx.ReqCtx(c)); ok && claims.IsImpersonated() {
+			httpx.FailWithError(c, security.ErrImpersonationNotAllowed)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RejectAPIKeys blocks requests authenticated with an API key, e.g. for managing credentials.
+// It must run after RequireAuth.
+func RejectAPIKeys() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, ok := security.ClaimsFromContext(httpx.ReqCtx(c)); ok && claims.APIKeyID != "" {
+			httpx.FailWithError(c, security.ErrAPIKeyNotAllowed)
+			return
+		}
+
+		c.Next()
 	}
 }
 
@@ -73,14 +101,13 @@ func AllowRoles(allowedRoles ...security.Role) gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 		// 1. pull claims from context
-		val := httpx.ReqCtx(c).Value(userKey)
-		claims, ok := val.(*security.UserClaims)
-		if !ok || claims == nil {
+		claims, ok := security.ClaimsFromContext(httpx.ReqCtx(c))
+		if !ok {
 			httpx.Fail(
 				c,
 				http.StatusUnauthorized,
-				auth.ErrUnauthorized.Code,
-				auth.ErrUnauthorized.Message,
+				security.ErrUnauthorized.Code,
+				security.ErrUnauthorized.Message,
 				nil,
 			)
 			return
@@ -97,8 +124,8 @@ func AllowRoles(allowedRoles ...security.Role) gin.HandlerFunc {
 			httpx.Fail(
 				c,
 				http.StatusForbidden,
-				auth.ErrForbidden.Code,
-				auth.ErrForbidden.Message,
+				security.ErrForbidden.Code,
+				security.ErrForbidden.Message,
 				nil,
 			)
 			return
@@ -108,11 +135,127 @@ func AllowRoles(allowedRoles ...security.Role) gin.HandlerFunc {
 	}
 }
 
-// GetUser is a helper for services to grab the current user
+// RequirePermission is a middleware factory for permission-based access control. The caller's role
+// must be granted every listed permission. It must run after RequireAuth.
+func RequirePermission(ctx *app.Context, permissions ...security.Permission) gin.HandlerFunc {
+	// validation check on startup
+	for _, p := range permissions {
+		if !security.IsValidPermission(p) {
+			panic(fmt.Sprintf("invalid permission '%s' passed to RequirePermission middleware. check your route definitions!", p))
+		}
+	}
+
+	return func(c *gin.Context) {
+		claims, ok := security.ClaimsFromContext(httpx.ReqCtx(c))
+		if !ok {
+			httpx.FailWithError(c, security.ErrUnauthorized)
+			return
+		}
+
+		for _, p := range permissions {
+			if ctx.Authorizer.Can(claims, p) {
+				continue
+			}
+			log.Ctx(httpx.ReqCtx(c)).Warn().
+				Str("user_id", claims.UserID).
+				Str("role", string(claims.Role)).
+				Str("required_permission", string(p)).
+				Msg("access denied due to missing permission")
+
+			httpx.FailWithError(c, security.ErrForbidden)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetUser is a helper for services to grab the current user. Under impersonation the claims describe
+// the impersonated user and claims.Actor the admin acting on their behalf.
 func GetUser(ctx context.Context) (*security.UserClaims, error) {
-	claims, ok := ctx.Value(userKey).(*security.UserClaims)
-	if !ok || claims == nil {
+	claims, ok := security.ClaimsFromContext(ctx)
+	if !ok {
 		return nil, errors.New("user identity not found in context")
 	}
 	return claims, nil
 }
+
+// authenticateJWT validates the Bearer access token of the request
+func authenticateJWT(c *gin.Context, ctx *app.Context) (*security.UserClaims, error) {
+	// 1. check for Authorization header
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		return nil, security.ErrUnauthorized
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	// 2. parse and validate JWT. only access tokens are accepted (no refresh or mfa challenge tokens)
+	claims, err := ctx.SecurityHandler.ValidateAccessToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. reject tokens revoked by logout, logout-all or admin actions
+	revoked, err := ctx.TokenRevocation.IsRevoked(httpx.ReqCtx(c), claims)
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to check token revocation",
+			err,
+		)
+	}
+	if revoked {
+		return nil, security.ErrRevokedToken
+	}
+
+	return claims, nil
+}
+
+// authenticateAPIKey resolves an API key and checks that its scopes allow the request
+func authenticateAPIKey(c *gin.Context, ctx *app.Context, apiKey string) (*security.UserClaims, error) {
+	claims, err := ctx.APIKeys.Authenticate(httpx.ReqCtx(c), apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if !security.ScopesAllow(claims.Scopes, c.Request.Method) {
+		log.Ctx(httpx.ReqCtx(c)).Warn().
+			Str("user_id", claims.UserID).
+			Str("api_key_id", claims.APIKeyID).
+			Interface("scopes", claims.Scopes).
+			Msg("access denied due to insufficient api key scope")
+		return nil, security.ErrInsufficientScope
+	}
+
+	return claims, nil
+}
+
+// recordImpersonatedRequest writes an audit record for a request made with an impersonation token.
+// It runs after the handler, so the request context may already be canceled.
+func recordImpersonatedRequest(c *gin.Context, ctx *app.Context, claims *security.UserClaims) {
+	reqCtx := httpx.ReqCtx(c)
+	err := ctx.Audit.Record(context.WithoutCancel(reqCtx), audit.Entry{
+		Action:     audit.ActionImpersonatedRequest,
+		TargetType: audit.TargetUser,
+		TargetID:   claims.UserID,
+		Metadata: map[string]any{
+			"method": c.Request.Method,
+			"route":  c.FullPath(),
+			"path":   c.Request.URL.Path,
+			"status": c.Writer.Status(),
+		},
+	})
+	if err != nil {
+		log.Ctx(reqCtx).Error().Err(err).Msg("failed to record impersonated request")
+	}
+}
+
+// apiKeyFromRequest returns the API key sent as "Authorization: ApiKey <key>" or in the X-API-Key header
+func apiKeyFromRequest(c *gin.Context) string {
+	if key, ok := strings.CutPrefix(c.GetHeader("Authorization"), constants.APIKeyAuthScheme); ok {
+		return strings.TrimSpace(key)
+	}
+	return strings.TrimSpace(c.GetHeader(constants.APIKeyHeaderName))
+}
@@ -15,6 +15,12 @@ func Recovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if r := recover(); r != nil {
+				// a handler cuts off a response it started already, e.g. a failing stream. the server
+				// closes the connection, so the client can tell the response is incomplete
+				if r == http.ErrAbortHandler {
+					panic(r)
+				}
+
 				// Log panic with full context
 				log.Ctx(httpx.ReqCtx(c)).
 					Error().
@@ -5,6 +5,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 
+	"github.com/mrhpn/go-rest-api/internal/audit"
 	"github.com/mrhpn/go-rest-api/internal/constants"
 	"github.com/mrhpn/go-rest-api/internal/httpx"
 )
@@ -33,8 +34,15 @@ func RequestID(env string) gin.HandlerFunc {
 			Str("env", env).
 			Str("request_id", requestID).
 			Logger()
-		// 4. inject this logger into the Stanard Library Context
-		c.Request = c.Request.WithContext(l.WithContext(httpx.ReqCtx(c)))
+		// 4. keep request details around for audit records
+		reqCtx := audit.WithRequestInfo(httpx.ReqCtx(c), audit.RequestInfo{
+			RequestID: requestID,
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+
+		// 5. inject this logger into the Stanard Library Context
+		c.Request = c.Request.WithContext(l.WithContext(reqCtx))
 
 		c.Next()
 	}
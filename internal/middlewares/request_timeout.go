@@ -20,7 +20,9 @@ import (
 //   - DON'T write to gin.Context from goroutines spawned within handlers
 //   - The timeout applies to the entire request handler chain
 //   - When timeout occurs, the context is cancelled, propagating to DB queries and external calls
-func RequestTimeout(timeoutSeconds int) gin.HandlerFunc {
+//   - The response is buffered until the handler returns, so routes streaming large responses are
+//     passed as skipPaths (route patterns, e.g. /api/v1/users/export). They run without a timeout.
+func RequestTimeout(timeoutSeconds int, skipPaths ...string) gin.HandlerFunc {
 	var timeoutDuration time.Duration
 	if timeoutSeconds <= 0 {
 		timeoutDuration = time.Duration(constants.RequestTimeoutSecond) * time.Second
@@ -28,10 +30,23 @@ func RequestTimeout(timeoutSeconds int) gin.HandlerFunc {
 		timeoutDuration = time.Duration(timeoutSeconds) * time.Second
 	}
 
-	return timeout.New(
+	handler := timeout.New(
 		timeout.WithTimeout(timeoutDuration),
 		timeout.WithResponse(createTimeoutResponseHandler()),
 	)
+
+	skip := make(map[string]struct{}, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := skip[c.FullPath()]; ok {
+			c.Next()
+			return
+		}
+		handler(c)
+	}
 }
 
 func createTimeoutResponseHandler() gin.HandlerFunc {
@@ -0,0 +1,167 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mrhpn/go-rest-api/internal/app"
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/config"
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/database"
+	"github.com/mrhpn/go-rest-api/internal/httpx"
+	"github.com/mrhpn/go-rest-api/internal/security"
+	"github.com/mrhpn/go-rest-api/internal/tenancy"
+)
+
+// ResolveTenant resolves the organization a request acts in from the organization header, the
+// subdomain or the org claim of the token (in that order) and injects it into the context, so that
+// repositories scope tenant-owned data to it. The caller must be a member of the organization unless
+// they may manage all organizations. Requests naming no organization only see the rows of no
+// organization, only those who may manage all organizations see every row. It must run after RequireAuth.
+func ResolveTenant(ctx *app.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := security.ClaimsFromContext(httpx.ReqCtx(c))
+		if !ok {
+			httpx.FailWithError(c, security.ErrUnauthorized)
+			return
+		}
+		platformAdmin := ctx.Authorizer.Can(claims, security.PermOrganizationsManage)
+
+		// 1. find out which organization the request names, if any
+		ref := tenantRef(c, ctx.Cfg.Tenancy, claims)
+		if ref == "" {
+			switch {
+			case platformAdmin:
+				c.Next()
+			case ctx.Cfg.Tenancy.Required || ctx.Cfg.Tenancy.RowLevelSecurity:
+				// row-level security shows no rows to requests without an organization
+				httpx.FailWithError(c, tenancy.ErrTenantRequired)
+			default:
+				c.Request = c.Request.WithContext(tenancy.WithNoOrganization(httpx.ReqCtx(c)))
+				c.Next()
+			}
+			return
+		}
+
+		// 2. the organization must exist and the caller must belong to it
+		tenant, err := ctx.Tenants.Resolve(httpx.ReqCtx(c), ref)
+		if err != nil {
+			httpx.FailWithError(c, err)
+			return
+		}
+		role, err := ctx.Tenants.MemberRole(httpx.ReqCtx(c), tenant.ID, claims.UserID)
+		if err != nil {
+			httpx.FailWithError(c, err)
+			return
+		}
+		if role == "" && !platformAdmin {
+			log.Ctx(httpx.ReqCtx(c)).Warn().
+				Str("user_id", claims.UserID).
+				Str("organization_id", tenant.ID).
+				Msg("access denied to organization the user is not a member of")
+
+			httpx.FailWithError(c, tenancy.ErrNotMember)
+			return
+		}
+		tenant.Role = role
+
+		// 3. tag the logger with the organization and inject the tenant into req context
+		l := log.Ctx(httpx.ReqCtx(c)).With().Str("organization_id", tenant.ID).Logger()
+		reqCtx := tenancy.WithTenant(l.WithContext(httpx.ReqCtx(c)), *tenant)
+
+		if !ctx.Cfg.Tenancy.RowLevelSecurity {
+			c.Request = c.Request.WithContext(reqCtx)
+			c.Next()
+			return
+		}
+
+		// 4. row-level security: the request runs in a transaction that knows its organization
+		err = runInTenantTransaction(c, ctx, reqCtx, tenant.ID)
+		switch {
+		case err == nil || errors.Is(err, errRequestFailed):
+		case c.Writer.Written():
+			// only reads are sent before the commit, they didn't change anything
+			log.Ctx(httpx.ReqCtx(c)).Error().Err(err).Msg("failed to commit the transaction of a sent response")
+		default:
+			httpx.FailWithError(c, apperror.Wrap(
+				apperror.Internal,
+				apperror.ErrDatabaseError.Code,
+				"failed to complete the request in the organization",
+				err,
+			))
+		}
+	}
+}
+
+// errRequestFailed rolls back the transaction of a request that responded with an error
+var errRequestFailed = errors.New("request failed")
+
+// runInTenantTransaction runs the rest of the chain in a transaction with the organization set for
+// postgres row-level security policies, and the bypass of the app's connections turned off. The
+// settings are local to the transaction, so they never leak to other requests sharing the pooled
+// connection. The transaction is rolled back if the request fails, a handler reporting an error
+// must not leave its partial changes behind.
+//
+// The response of a write is held back until the transaction is committed, the client must not be told
+// about a change that was rolled back. Reads are sent right away, so they can stream.
+func runInTenantTransaction(c *gin.Context, ctx *app.Context, reqCtx context.Context, tenantID string) error {
+	var buffered *bufferedWriter
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		buffered = newBufferedWriter(c.Writer)
+		c.Writer = buffered
+		// also on panics, so that the recovery middleware can still respond
+		defer func() { c.Writer = buffered.ResponseWriter }()
+	}
+
+	err := database.Transaction(reqCtx, ctx.DB, func(txCtx context.Context) error {
+		setErr := database.GetTx(txCtx).
+			Exec(
+				"SELECT set_config(?, ?, true), set_config(?, 'off', true)",
+				constants.TenantSettingName, tenantID, constants.TenantBypassSettingName,
+			).
+			Error
+		if setErr != nil {
+			return setErr
+		}
+
+		c.Request = c.Request.WithContext(txCtx)
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusBadRequest || len(c.Errors) > 0 {
+			return errRequestFailed
+		}
+		return nil
+	})
+
+	if buffered != nil && (err == nil || errors.Is(err, errRequestFailed)) {
+		buffered.flush()
+	}
+	return err
+}
+
+// tenantRef returns the id or slug of the organization the request names, or an empty string
+func tenantRef(c *gin.Context, cfg config.TenancyConfig, claims *security.UserClaims) string {
+	if ref := strings.TrimSpace(c.GetHeader(cfg.Header)); ref != "" {
+		return ref
+	}
+
+	if cfg.BaseDomain != "" {
+		host := strings.ToLower(c.Request.Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		// only direct subdomains, e.g. acme.example.com but not www.acme.example.com
+		if slug, ok := strings.CutSuffix(host, "."+cfg.BaseDomain); ok && slug != "" && !strings.Contains(slug, ".") {
+			return slug
+		}
+	}
+
+	return claims.OrganizationID
+}
@@ -0,0 +1,2 @@
+// Package apikeys manages personal API keys that let scripts and integrations call the API on behalf of a user.
+package apikeys
@@ -0,0 +1,67 @@
+package apikeys
+
+import (
+	"github.com/mrhpn/go-rest-api/internal/security"
+	"github.com/mrhpn/go-rest-api/internal/timex"
+)
+
+type IDParam struct {
+	ID string `uri:"id" binding:"required,ulid"`
+}
+
+// CreateAPIKeyRequest constructs api key creation request structure.
+type CreateAPIKeyRequest struct {
+	Name          string           `json:"name" binding:"required,min=1,max=100"`
+	Scopes        []security.Scope `json:"scopes" binding:"required,min=1,dive,oneof=read write"`
+	ExpiresInDays int              `json:"expires_in_days" binding:"omitempty,min=1,max=365"` // defaults to 90
+}
+
+// APIKeyResponse returns necessary data about an api key, never its secret
+type APIKeyResponse struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Prefix     string           `json:"prefix"`
+	Scopes     []security.Scope `json:"scopes"`
+	ExpiresAt  string           `json:"expires_at"`
+	LastUsedAt string           `json:"last_used_at"`
+	CreatedAt  string           `json:"created_at"`
+}
+
+// CreateAPIKeyResponse returns a newly created api key including the raw key, which is only shown once
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// ToAPIKeyResponse converts an APIKey model to APIKeyResponse DTO
+func ToAPIKeyResponse(key *APIKey) APIKeyResponse {
+	res := APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		ExpiresAt: timex.ToAPIDateTimeFormat(key.ExpiresAt),
+		CreatedAt: timex.ToAPIDateTimeFormat(key.CreatedAt),
+	}
+	if key.LastUsedAt != nil {
+		res.LastUsedAt = timex.ToAPIDateTimeFormat(*key.LastUsedAt)
+	}
+	return res
+}
+
+// ToAPIKeyResponseList converts a list of APIKey models to APIKeyResponse DTOs
+func ToAPIKeyResponseList(keys []*APIKey) []APIKeyResponse {
+	res := make([]APIKeyResponse, len(keys))
+	for i, key := range keys {
+		res[i] = ToAPIKeyResponse(key)
+	}
+	return res
+}
+
+// ToCreateAPIKeyResponse converts a newly created key to CreateAPIKeyResponse DTO
+func ToCreateAPIKeyResponse(created *CreatedKey) CreateAPIKeyResponse {
+	return CreateAPIKeyResponse{
+		APIKeyResponse: ToAPIKeyResponse(created.Key),
+		Key:            created.RawKey,
+	}
+}
@@ -0,0 +1,24 @@
+package apikeys
+
+import (
+	"fmt"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/constants"
+)
+
+var (
+	// errAPIKeyNotFound indicates that the api key does not exist or belongs to someone else.
+	errAPIKeyNotFound = apperror.New(
+		apperror.NotFound,
+		"API_KEY_NOT_FOUND",
+		"api key not found",
+	)
+
+	// errAPIKeyLimitReached indicates that the user already has the maximum number of active api keys.
+	errAPIKeyLimitReached = apperror.New(
+		apperror.Conflict,
+		"API_KEY_LIMIT_REACHED",
+		fmt.Sprintf("at most %d active api keys are allowed, revoke one first", constants.APIKeyMaxPerUser),
+	)
+)
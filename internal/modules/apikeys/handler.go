@@ -0,0 +1,136 @@
+package apikeys
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mrhpn/go-rest-api/internal/httpx"
+	"github.com/mrhpn/go-rest-api/internal/middlewares"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// Service defines the business logic for managing personal api keys.
+type Service interface {
+	// Create creates a key for the user. The raw key is only returned here and never stored.
+	Create(ctx context.Context, userID string, req CreateAPIKeyRequest) (*CreatedKey, error)
+	List(ctx context.Context, userID string) ([]*APIKey, error)
+	Revoke(ctx context.Context, userID, id string) error
+
+	security.APIKeyAuthenticator
+}
+
+// Handler handles api key related HTTP endpoints.
+type Handler struct {
+	service Service
+}
+
+// NewHandler constructs an apikeys Handler with its required service dependency.
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Create api key godoc
+//
+//	@Summary		Create api key
+//	@Description	Create a named, scoped and expiring api key for the current user. The key is only shown once.
+//	@Tags			API Key
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		apikeys.CreateAPIKeyRequest	true	"CreateAPIKeyRequest"
+//	@Success		201		{object}	apikeys.CreateAPIKeyResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		409		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/api-keys [post]
+func (h *Handler) Create(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	created, err := h.service.Create(httpx.ReqCtx(c), user.UserID, req)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusCreated, ToCreateAPIKeyResponse(created))
+}
+
+// List api keys godoc
+//
+//	@Summary		List api keys
+//	@Description	List the active api keys of the current user
+//	@Tags			API Key
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	httpx.SuccessResponse{data=[]apikeys.APIKeyResponse}
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		403	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/api-keys [get]
+func (h *Handler) List(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	keys, err := h.service.List(httpx.ReqCtx(c), user.UserID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, ToAPIKeyResponseList(keys))
+}
+
+// Revoke api key godoc
+//
+//	@Summary		Revoke api key
+//	@Description	Revoke an api key of the current user by its ULID
+//	@Tags			API Key
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path	string	true	"API Key ID"
+//	@Success		204
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		403	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/api-keys/{id} [delete]
+func (h *Handler) Revoke(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params IDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err = h.service.Revoke(httpx.ReqCtx(c), user.UserID, params.ID); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,39 @@
+package apikeys
+
+import (
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/model"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// APIKey represents the db model for a personal API key. Only the hash of the secret part is stored,
+// the public Prefix is used to look the key up.
+type APIKey struct {
+	model.Base
+
+	UserID     string           `gorm:"type:char(26);not null;index"`
+	Name       string           `gorm:"type:varchar(100);not null"`
+	Prefix     string           `gorm:"type:varchar(32);not null;uniqueIndex"`
+	KeyHash    string           `gorm:"type:char(64);not null"`
+	Scopes     []security.Scope `gorm:"type:jsonb;not null;serializer:json"`
+	ExpiresAt  time.Time        `gorm:"not null"`
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// TableName specifies the table name for the APIKey model
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive reports whether the key can still be used
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && now.Before(k.ExpiresAt)
+}
+
+// CreatedKey is a newly created API key together with its raw value, which is only available once
+type CreatedKey struct {
+	Key    *APIKey
+	RawKey string
+}
@@ -0,0 +1,136 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	repo "github.com/mrhpn/go-rest-api/internal/repository"
+)
+
+type Repository struct {
+	repo.Base
+}
+
+// NewRepository constructs an apikeys Repository backed by a GORM database.
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		Base: repo.Base{
+			DBInstance: db,
+		},
+	}
+}
+
+func (r *Repository) Create(ctx context.Context, key *APIKey) error {
+	err := r.DB(ctx).Create(key).Error
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to create api key",
+			err,
+		)
+	}
+	return nil
+}
+
+// FindByPrefix returns the key with the given lookup prefix, including revoked and expired ones.
+func (r *Repository) FindByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
+	var key APIKey
+	err := r.DB(ctx).First(&key, "prefix = ?", prefix).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errAPIKeyNotFound
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find api key",
+			err,
+		)
+	}
+
+	return &key, nil
+}
+
+// ListActiveByUserID returns the user's keys that are neither revoked nor expired, newest first.
+func (r *Repository) ListActiveByUserID(ctx context.Context, userID string) ([]*APIKey, error) {
+	var keys []*APIKey
+	err := r.DB(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&keys).Error
+
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to list api keys",
+			err,
+		)
+	}
+
+	return keys, nil
+}
+
+// CountActiveByUserID counts the user's keys that are neither revoked nor expired.
+func (r *Repository) CountActiveByUserID(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.DB(ctx).
+		Model(&APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Count(&count).Error
+
+	if err != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to count api keys",
+			err,
+		)
+	}
+
+	return count, nil
+}
+
+// Revoke revokes a key of the given user. Zero affected rows means no such active key exists.
+func (r *Repository) Revoke(ctx context.Context, id, userID string) (int64, error) {
+	result := r.DB(ctx).
+		Model(&APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to revoke api key",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// Touch records that the key was used, at most once per resolution to avoid a write per request.
+func (r *Repository) Touch(ctx context.Context, id string, resolution time.Duration) error {
+	now := time.Now()
+	err := r.DB(ctx).
+		Model(&APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-resolution)).
+		UpdateColumn("last_used_at", now).Error
+
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to update api key usage",
+			err,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/modules/users"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// apiKeyRepository interface defines the methods required for api key persistence.
+type apiKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	FindByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	ListActiveByUserID(ctx context.Context, userID string) ([]*APIKey, error)
+	CountActiveByUserID(ctx context.Context, userID string) (int64, error)
+	Revoke(ctx context.Context, id, userID string) (int64, error)
+	Touch(ctx context.Context, id string, resolution time.Duration) error
+}
+
+// userFinder loads the owner of a key, so that role changes and blocks apply to api keys immediately.
+type userFinder interface {
+	FindByID(ctx context.Context, id string) (*users.User, error)
+}
+
+type service struct {
+	repo  apiKeyRepository
+	users userFinder
+}
+
+// NewService constructs an apikeys Service with the provided repository.
+func NewService(repo apiKeyRepository, users userFinder) Service {
+	return &service{
+		repo:  repo,
+		users: users,
+	}
+}
+
+func (s *service) Create(ctx context.Context, userID string, req CreateAPIKeyRequest) (*CreatedKey, error) {
+	// 1. keep the number of live credentials per user bounded
+	count, err := s.repo.CountActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= constants.APIKeyMaxPerUser {
+		return nil, errAPIKeyLimitReached
+	}
+
+	// 2. generate the key: gra_<lookup>_<secret>
+	prefix, secret, err := generateKey()
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to generate api key",
+			err,
+		)
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = constants.APIKeyDefaultExpirationDay
+	}
+
+	// 3. persist only the hash of the secret
+	key := &APIKey{
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   security.HashOpaqueToken(secret),
+		Scopes:    req.Scopes,
+		ExpiresAt: time.Now().AddDate(0, 0, days),
+	}
+	if err = s.repo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", userID).Str("api_key_id", key.ID).Msg("api key created")
+
+	return &CreatedKey{Key: key, RawKey: prefix + "_" + secret}, nil
+}
+
+func (s *service) List(ctx context.Context, userID string) ([]*APIKey, error) {
+	return s.repo.ListActiveByUserID(ctx, userID)
+}
+
+func (s *service) Revoke(ctx context.Context, userID, id string) error {
+	affected, err := s.repo.Revoke(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errAPIKeyNotFound
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", userID).Str("api_key_id", id).Msg("api key revoked")
+
+	return nil
+}
+
+func (s *service) Authenticate(ctx context.Context, rawKey string) (*security.UserClaims, error) {
+	// 1. split the raw key into its public lookup part and the secret
+	prefix, secret, ok := parseKey(rawKey)
+	if !ok {
+		return nil, security.ErrInvalidAPIKey
+	}
+
+	// 2. find the key and compare the secret
+	key, err := s.repo.FindByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, errAPIKeyNotFound) {
+			return nil, security.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(security.HashOpaqueToken(secret))) != 1 ||
+		!key.IsActive(time.Now()) {
+		return nil, security.ErrInvalidAPIKey
+	}
+
+	// 3. the key acts with the owner's current role, and stops working once the owner is blocked or deleted
+	user, err := s.users.FindByID(ctx, key.UserID)
+	if err != nil {
+		return nil, security.ErrInvalidAPIKey
+	}
+	if user.Status == security.UserStatusBlocked {
+		return nil, security.ErrBlockedUser
+	}
+
+	// 4. usage tracking must not fail the request
+	if err = s.repo.Touch(ctx, key.ID, constants.APIKeyLastUsedResolutionSecond*time.Second); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("api_key_id", key.ID).Msg("failed to record api key usage")
+	}
+
+	return &security.UserClaims{
+		UserID:   user.ID,
+		Role:     user.Role,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+// generateKey returns the public lookup prefix (including the gra_ marker) and the secret of a new key
+func generateKey() (string, string, error) {
+	lookup := make([]byte, constants.APIKeyLookupByteLength)
+	if _, err := rand.Read(lookup); err != nil {
+		return "", "", err
+	}
+
+	secret, err := security.GenerateOpaqueToken(constants.APIKeySecretByteLength)
+	if err != nil {
+		return "", "", err
+	}
+
+	return constants.APIKeyTokenPrefix + hex.EncodeToString(lookup), secret, nil
+}
+
+// parseKey splits "gra_<lookup>_<secret>". The lookup part is hex, so the first "_" after the marker
+// separates it from the (base64url) secret, which may contain "_" itself.
+func parseKey(rawKey string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(rawKey, constants.APIKeyTokenPrefix)
+	if !ok {
+		return "", "", false
+	}
+	lookup, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(lookup) != hex.EncodedLen(constants.APIKeyLookupByteLength) || secret == "" {
+		return "", "", false
+	}
+	return constants.APIKeyTokenPrefix + lookup, secret, true
+}
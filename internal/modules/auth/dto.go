@@ -1,8 +1,12 @@
 package auth
 
 import (
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/modules/mfa"
 	"github.com/mrhpn/go-rest-api/internal/modules/users"
 	"github.com/mrhpn/go-rest-api/internal/security"
+	"github.com/mrhpn/go-rest-api/internal/timex"
 )
 
 // LoginRequest constructs login request structure.
@@ -11,10 +15,72 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// LoginMFARequest constructs the second step of a login for users with two-factor authentication.
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"` // totp code or recovery code
+}
+
+// SetupMFARequest constructs the request to start a mandatory mfa enrollment during login.
+type SetupMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+// MFACodeRequest constructs a request that must be confirmed with a totp or recovery code.
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// OIDCProviderParam binds the identity provider name from the url.
+type OIDCProviderParam struct {
+	Provider string `uri:"provider" binding:"required,max=50"`
+}
+
+// ImpersonateRequest constructs the request to act as another user.
+type ImpersonateRequest struct {
+	UserID string `json:"user_id" binding:"required,ulid"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// ImpersonationResult is an impersonation access token for User. It cannot be refreshed.
+type ImpersonationResult struct {
+	AccessToken string
+	ExpiresAt   time.Time
+	User        *users.User
+}
+
+// LoginResult is the outcome of a login. Tokens is nil while a second factor is still required (MFAToken is set instead).
+type LoginResult struct {
+	Tokens                *security.TokenPair
+	User                  *users.User
+	MFAToken              string
+	MFAEnrollmentRequired bool
+	RecoveryCodes         []string // only set when the login completed a mandatory mfa enrollment
+}
+
 // LoginResponse constructs login response structure. This will not include RefreshToken since it is sent via http-only cookie for security.
 type LoginResponse struct {
-	AccessToken string            `json:"access_token"`
-	User        LoginUserResponse `json:"user"`
+	AccessToken   string            `json:"access_token"`
+	User          LoginUserResponse `json:"user"`
+	RecoveryCodes []string          `json:"recovery_codes,omitempty"`
+}
+
+// MFAChallengeResponse is returned by login instead of LoginResponse when a second factor is required.
+type MFAChallengeResponse struct {
+	MFARequired           bool   `json:"mfa_required"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required"`
+	MFAToken              string `json:"mfa_token"`
+}
+
+// MFAEnrollmentResponse carries the secret to register in an authenticator app.
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse carries one-time recovery codes. They are only shown once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
 }
 
 type LoginUserResponse struct {
@@ -24,11 +90,67 @@ type LoginUserResponse struct {
 	Status security.UserStatus `json:"status"`
 }
 
+// ImpersonationResponse constructs impersonation response structure.
+type ImpersonationResponse struct {
+	AccessToken string            `json:"access_token"`
+	ExpiresAt   string            `json:"expires_at"`
+	User        LoginUserResponse `json:"user"`
+}
+
 // RefreshTokenResponse constructs refresh token response structure.
 type RefreshTokenResponse struct {
 	AccessToken string `json:"access_token"`
 }
 
+// ForgotPasswordRequest constructs forgot password request structure.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest constructs reset password request structure.
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,password"`
+}
+
+// ChangePasswordRequest constructs the request to change the password of the current user.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	Password        string `json:"password" binding:"required,password"`
+}
+
+// ChangeEmailRequest constructs the request to change the email of the current user.
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"` // current password
+}
+
+// ConfirmEmailChangeRequest constructs email change confirmation request structure.
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// RegisterRequest constructs public sign-up request structure.
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,password"`
+}
+
+// VerifyEmailRequest constructs email verification request structure.
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ResendVerificationRequest constructs resend verification email request structure.
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// MessageResponse constructs a response carrying only a human-readable message.
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
 // ToLoginUserResponse converts a User model to LoginUserResponse DTO
 func ToLoginUserResponse(user *users.User) LoginUserResponse {
 	return LoginUserResponse{
@@ -47,6 +169,32 @@ func ToLoginResponse(accessToken string, user *users.User) LoginResponse {
 	}
 }
 
+// ToMFAChallengeResponse converts a login result waiting for a second factor to MFAChallengeResponse DTO
+func ToMFAChallengeResponse(result *LoginResult) MFAChallengeResponse {
+	return MFAChallengeResponse{
+		MFARequired:           true,
+		MFAEnrollmentRequired: result.MFAEnrollmentRequired,
+		MFAToken:              result.MFAToken,
+	}
+}
+
+// ToMFAEnrollmentResponse converts an mfa enrollment to MFAEnrollmentResponse DTO
+func ToMFAEnrollmentResponse(enrollment *mfa.Enrollment) MFAEnrollmentResponse {
+	return MFAEnrollmentResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.URI,
+	}
+}
+
+// ToImpersonationResponse converts an impersonation result to ImpersonationResponse DTO
+func ToImpersonationResponse(result *ImpersonationResult) ImpersonationResponse {
+	return ImpersonationResponse{
+		AccessToken: result.AccessToken,
+		ExpiresAt:   timex.ToAPIDateTimeFormat(result.ExpiresAt),
+		User:        ToLoginUserResponse(result.User),
+	}
+}
+
 // ToRefreshTokenResponse converts access token to RefreshTokenResponse DTO
 func ToRefreshTokenResponse(newAccessToken string) RefreshTokenResponse {
 	return RefreshTokenResponse{
@@ -3,21 +3,6 @@ package auth
 import "github.com/mrhpn/go-rest-api/internal/apperror"
 
 var (
-	// ErrUnauthorized indicates that the request lacks valid authentication credentials, such as a missing access token.
-	ErrUnauthorized = apperror.New(
-		apperror.Unauthorized,
-		"UNAUTHORIZED",
-		"unauthorized: missing token",
-	)
-
-	// ErrForbidden indicates that the authenticated identity does not
-	// have sufficient permissions to access the requested resource.
-	ErrForbidden = apperror.New(
-		apperror.Forbidden,
-		"FORBIDDEN",
-		"forbidden: insufficient permissions",
-	)
-
 	// ErrRefreshTokenMissing indicates that a refresh token was expected but not provided in the request.
 	errRefreshTokenMissing = apperror.New(
 		apperror.Unauthorized,
@@ -38,4 +23,102 @@ var (
 		"ERR_TOKEN_GENERATION",
 		"cannot generate token",
 	)
+
+	// errTokenRevocation indicates a failure while revoking access tokens.
+	errTokenRevocation = apperror.New(
+		apperror.Internal,
+		"ERR_TOKEN_REVOCATION",
+		"cannot revoke token",
+	)
+
+	// errMailDelivery indicates a failure while handing an email over to the mailer.
+	errMailDelivery = apperror.New(
+		apperror.Internal,
+		"ERR_MAIL_DELIVERY",
+		"cannot send email",
+	)
+
+	// errWrongPassword indicates that the current password given to confirm an account change is wrong.
+	errWrongPassword = apperror.New(
+		apperror.BadRequest,
+		"WRONG_PASSWORD",
+		"current password is incorrect",
+	)
+
+	// errEmailNotVerified indicates that a self-registered user tried to log in before verifying their email.
+	errEmailNotVerified = apperror.New(
+		apperror.Forbidden,
+		"EMAIL_NOT_VERIFIED",
+		"email address has not been verified",
+	)
+
+	// errRegistrationDisabled indicates that public sign-up is turned off for this deployment.
+	errRegistrationDisabled = apperror.New(
+		apperror.Forbidden,
+		"REGISTRATION_DISABLED",
+		"registration is disabled",
+	)
+
+	// errMFARequired indicates that the user's role requires two-factor authentication, so it cannot be turned off.
+	errMFARequired = apperror.New(
+		apperror.Forbidden,
+		"MFA_REQUIRED",
+		"two-factor authentication is required for your role",
+	)
+
+	// errOIDCProviderNotFound indicates that no identity provider with the requested name is configured.
+	errOIDCProviderNotFound = apperror.New(
+		apperror.NotFound,
+		"OIDC_PROVIDER_NOT_FOUND",
+		"identity provider not found",
+	)
+
+	// errOIDCProviderUnavailable indicates that the identity provider's discovery document could not be loaded.
+	errOIDCProviderUnavailable = apperror.New(
+		apperror.Internal,
+		"OIDC_PROVIDER_UNAVAILABLE",
+		"identity provider is unavailable",
+	)
+
+	// errOIDCInvalidState indicates that the callback doesn't belong to a login started by this browser.
+	errOIDCInvalidState = apperror.New(
+		apperror.Unauthorized,
+		"OIDC_INVALID_STATE",
+		"login session is invalid or expired, please try again",
+	)
+
+	// errOIDCLoginFailed indicates that the authorization code or the id token was rejected.
+	errOIDCLoginFailed = apperror.New(
+		apperror.Unauthorized,
+		"OIDC_LOGIN_FAILED",
+		"login with the identity provider failed",
+	)
+
+	// errOIDCEmailNotVerified indicates that the provider did not vouch for the email of an unlinked identity.
+	errOIDCEmailNotVerified = apperror.New(
+		apperror.Forbidden,
+		"OIDC_EMAIL_NOT_VERIFIED",
+		"the identity provider did not confirm your email address",
+	)
+
+	// errOIDCAccountNotLinked indicates that no account exists for the identity and auto registration is disabled.
+	errOIDCAccountNotLinked = apperror.New(
+		apperror.Forbidden,
+		"OIDC_ACCOUNT_NOT_LINKED",
+		"no account exists for this identity",
+	)
+
+	// errImpersonateSuperAdmin indicates an attempt to impersonate a superadmin.
+	errImpersonateSuperAdmin = apperror.New(
+		apperror.Forbidden,
+		"IMPERSONATE_SUPERADMIN",
+		"superadmins cannot be impersonated",
+	)
+
+	// errImpersonateSelf indicates an attempt to impersonate the authenticated user itself.
+	errImpersonateSelf = apperror.New(
+		apperror.BadRequest,
+		"IMPERSONATE_SELF",
+		"you cannot impersonate yourself",
+	)
 )
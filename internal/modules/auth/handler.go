@@ -2,21 +2,51 @@ package auth
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/mrhpn/go-rest-api/internal/app"
 	"github.com/mrhpn/go-rest-api/internal/constants"
 	"github.com/mrhpn/go-rest-api/internal/httpx"
+	"github.com/mrhpn/go-rest-api/internal/middlewares"
+	"github.com/mrhpn/go-rest-api/internal/modules/mfa"
+	"github.com/mrhpn/go-rest-api/internal/modules/sessions"
 	"github.com/mrhpn/go-rest-api/internal/modules/users"
+	"github.com/mrhpn/go-rest-api/internal/oidc"
 	"github.com/mrhpn/go-rest-api/internal/security"
 )
 
 // Service defines the business logic for authentication operations.
 type Service interface {
-	Login(ctx context.Context, email, password string) (*security.TokenPair, *users.User, error)
-	RefreshToken(ctx context.Context, refreshToken string) (string, error)
+	Login(ctx context.Context, email, password string, device sessions.Device) (*LoginResult, error)
+	StartOIDC(ctx context.Context, provider string) (string, *oidc.Flow, error)
+	CompleteOIDC(ctx context.Context, provider, code, state string, flow *oidc.Flow, device sessions.Device) (*LoginResult, error)
+	LoginMFA(ctx context.Context, mfaToken, code string, device sessions.Device) (*LoginResult, error)
+	SetupMFA(ctx context.Context, mfaToken string) (*mfa.Enrollment, error)
+	RefreshToken(ctx context.Context, refreshToken string, device sessions.Device) (*security.TokenPair, error)
+	Logout(ctx context.Context, claims *security.UserClaims, refreshToken string) error
+	LogoutAll(ctx context.Context, userID string) error
+	Impersonate(ctx context.Context, actor *security.UserClaims, userID, reason string) (*ImpersonationResult, error)
+	ListSessions(ctx context.Context, userID string) ([]*sessions.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	ChangePassword(ctx context.Context, claims *security.UserClaims, currentPassword, newPassword string) error
+	RequestEmailChange(ctx context.Context, claims *security.UserClaims, password, newEmail string) error
+	ConfirmEmailChange(ctx context.Context, token string) error
+	// Register signs up a new user. A taken email is not reported, its owner is notified by email instead.
+	Register(ctx context.Context, email, password string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	EnrollMFA(ctx context.Context, userID string) (*mfa.Enrollment, error)
+	ConfirmMFA(ctx context.Context, userID, code string) ([]string, error)
+	DisableMFA(ctx context.Context, claims *security.UserClaims, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+	// Reauthenticate confirms the password of a logged-in user, counting failures towards the login lockout.
+	Reauthenticate(ctx context.Context, userID, password string) (*users.User, error)
 }
 
 // Handler handles authentication-related HTTP endpoints such as login, token refresh, and access control–protected actions.
@@ -36,7 +66,8 @@ func NewHandler(authService Service, appCtx *app.Context) *Handler {
 // Login godoc
 //
 //	@Summary		User Login
-//	@Description	Authenticates a user and returns access token in response and set refresh token in httpOnly cookie
+//	@Description	Authenticates a user and returns access token in response and set refresh token in httpOnly cookie.
+//	@Description	When a second factor is required, an auth.MFAChallengeResponse is returned instead and the login must be completed at /auth/login/mfa
 //	@Tags			Auth
 //	@Accept			json
 //	@Produce		json
@@ -53,32 +84,176 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
-	tokenPair, user, err := h.service.Login(httpx.ReqCtx(c), req.Email, req.Password)
+	result, err := h.service.Login(httpx.ReqCtx(c), req.Email, req.Password, clientDevice(c))
 	if err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
 
-	// set refresh token in cookie
-	cookieMaxAge := h.appCtx.Cfg.JWT.RefreshTokenExpirationSecond
-	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie(
-		constants.RefreshTokenCookieName,        // name
-		tokenPair.RefreshToken,                  // value
-		cookieMaxAge,                            // max age
-		"/",                                     // path
-		"",                                      // domain (empty for localhost)
-		h.appCtx.Cfg.AppEnv != constants.EnvDev, // secure (set to true in production with HTTPS)
-		true,                                    // httpOnly
+	// second factor required: no tokens yet
+	if result.Tokens == nil {
+		httpx.OK(c, http.StatusOK, ToMFAChallengeResponse(result))
+		return
+	}
+
+	h.respondWithLogin(c, result)
+}
+
+// LoginMFA godoc
+//
+//	@Summary		Complete login with a second factor
+//	@Description	Completes a login that returned an mfa challenge using a TOTP or recovery code.
+//	@Description	When the challenge required enrollment, the code confirms the new factor and recovery codes are returned once
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		auth.LoginMFARequest	true	"MFA challenge token and code"
+//	@Success		200		{object}	auth.LoginResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Router			/auth/login/mfa [post]
+func (h *Handler) LoginMFA(c *gin.Context) {
+	var req LoginMFARequest
+	if err := httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	result, err := h.service.LoginMFA(httpx.ReqCtx(c), req.MFAToken, req.Code, clientDevice(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	h.respondWithLogin(c, result)
+}
+
+// OIDCLogin godoc
+//
+//	@Summary		Start login with an identity provider
+//	@Description	Redirects the browser to the OpenID Connect provider's login page (authorization code flow with PKCE).
+//	@Description	State, nonce and code verifier are kept in a short-lived http-only cookie until the provider redirects back to the callback
+//	@Tags			Auth
+//	@Param			provider	path	string	true	"Provider name as configured in OIDC_PROVIDERS"
+//	@Success		302
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Router			/auth/oidc/{provider}/login [get]
+func (h *Handler) OIDCLogin(c *gin.Context) {
+	var params OIDCProviderParam
+	if err := httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	authURL, flow, err := h.service.StartOIDC(httpx.ReqCtx(c), params.Provider)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	h.setOIDCFlowCookie(c, flow.Encode(), constants.OIDCFlowExpirationSecond)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback godoc
+//
+//	@Summary		Complete login with an identity provider
+//	@Description	Redirect target of the OpenID Connect provider. Verifies the login, sets the refresh token cookie and redirects to OIDC_SUCCESS_REDIRECT_URL,
+//	@Description	where the frontend obtains an access token from /auth/refresh. When a second factor is required the redirect carries
+//	@Description	#mfa_token=...&mfa_enrollment_required=... to complete at /auth/login/mfa instead. Failures redirect with ?error=<code>
+//	@Tags			Auth
+//	@Param			provider	path	string	true	"Provider name"
+//	@Param			code		query	string	false	"Authorization code"
+//	@Param			state		query	string	false	"State of the login"
+//	@Success		302
+//	@Router			/auth/oidc/{provider}/callback [get]
+func (h *Handler) OIDCCallback(c *gin.Context) {
+	successURL := h.appCtx.Cfg.OIDC.SuccessRedirectURL
+
+	// the flow cookie is single-use
+	rawFlow, _ := c.Cookie(constants.OIDCFlowCookieName)
+	h.setOIDCFlowCookie(c, "", -1)
+
+	var params OIDCProviderParam
+	if err := httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.RedirectWithError(c, successURL, err)
+		return
+	}
+
+	// e.g. the user declined the consent screen
+	if providerErr := c.Query("error"); providerErr != "" {
+		httpx.RedirectWithError(c, successURL, fmt.Errorf("%w: provider returned %s", errOIDCLoginFailed, providerErr))
+		return
+	}
+
+	flow, err := oidc.DecodeFlow(rawFlow)
+	if err != nil {
+		flow = nil // reported as an invalid state by the service
+	}
+
+	result, err := h.service.CompleteOIDC(
+		httpx.ReqCtx(c),
+		params.Provider,
+		c.Query("code"),
+		c.Query("state"),
+		flow,
+		clientDevice(c),
 	)
+	if err != nil {
+		httpx.RedirectWithError(c, successURL, err)
+		return
+	}
+
+	// second factor required: hand the challenge over in the fragment, it never reaches a server log
+	if result.Tokens == nil {
+		fragment := url.Values{}
+		fragment.Set("mfa_token", result.MFAToken)
+		fragment.Set("mfa_enrollment_required", fmt.Sprint(result.MFAEnrollmentRequired))
+		c.Redirect(http.StatusFound, successURL+"#"+fragment.Encode())
+		return
+	}
+
+	// tokens never travel in the url, the frontend exchanges the refresh cookie for an access token
+	h.setRefreshTokenCookie(c, result.Tokens.RefreshToken)
+	c.Redirect(http.StatusFound, successURL)
+}
+
+// SetupMFA godoc
+//
+//	@Summary		Start mandatory MFA enrollment during login
+//	@Description	For users whose role requires a second factor but who have none yet. Returns a TOTP secret to confirm at /auth/login/mfa
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		auth.SetupMFARequest	true	"MFA challenge token"
+//	@Success		200		{object}	auth.MFAEnrollmentResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		409		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Router			/auth/login/mfa/setup [post]
+func (h *Handler) SetupMFA(c *gin.Context) {
+	var req SetupMFARequest
+	if err := httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	enrollment, err := h.service.SetupMFA(httpx.ReqCtx(c), req.MFAToken)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
 
-	httpx.OK(c, http.StatusOK, ToLoginResponse(tokenPair.AccessToken, user))
+	httpx.OK(c, http.StatusOK, ToMFAEnrollmentResponse(enrollment))
 }
 
 // Refresh token godoc
 //
 //	@Summary		Refresh token
-//	@Description	Generate a new access token, request must have valid refresh token in cookie
+//	@Description	Generate a new access token and rotate the refresh token cookie, request must have valid refresh token in cookie
 //	@Tags			Auth
 //	@Accept			json
 //	@Produce		json
@@ -101,11 +276,645 @@ func (h *Handler) Refresh(c *gin.Context) {
 		return
 	}
 
-	newAccessToken, err := h.service.RefreshToken(httpx.ReqCtx(c), refreshToken)
+	tokenPair, err := h.service.RefreshToken(httpx.ReqCtx(c), refreshToken, clientDevice(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	// rotate refresh token in cookie, the old one is no longer usable
+	h.setRefreshTokenCookie(c, tokenPair.RefreshToken)
+
+	httpx.OK(c, http.StatusOK, ToRefreshTokenResponse(tokenPair.AccessToken))
+}
+
+// Logout godoc
+//
+//	@Summary		Logout
+//	@Description	Revoke the current access token and the refresh token session of this device, and clear the refresh token cookie
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Success		204
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/auth/logout [post]
+func (h *Handler) Logout(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	// missing cookie is fine: the access token still gets revoked
+	refreshToken, _ := c.Cookie(constants.RefreshTokenCookieName)
+
+	if err = h.service.Logout(httpx.ReqCtx(c), user, refreshToken); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	// ending an impersonation keeps the admin's own session
+	if !user.IsImpersonated() {
+		h.clearRefreshTokenCookie(c)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll godoc
+//
+//	@Summary		Logout from all devices
+//	@Description	Revoke every refresh token session and every access token issued to the current user
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Success		204
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/auth/logout-all [post]
+func (h *Handler) LogoutAll(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err = h.service.LogoutAll(httpx.ReqCtx(c), user.UserID); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	h.clearRefreshTokenCookie(c)
+	c.Status(http.StatusNoContent)
+}
+
+// Impersonate godoc
+//
+//	@Summary		Impersonate a user
+//	@Description	Issue a short-lived access token to act as another user, e.g. to debug an issue they reported. Only superadmins can impersonate, superadmins cannot be impersonated. The token cannot be refreshed, end it early with /auth/logout. Every request made with it is audited
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		auth.ImpersonateRequest	true	"User to impersonate and why"
+//	@Success		200		{object}	httpx.SuccessResponse{data=auth.ImpersonationResponse}
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		404		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/auth/impersonate [post]
+func (h *Handler) Impersonate(c *gin.Context) {
+	var req ImpersonateRequest
+	if err := httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
 	if err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
 
-	httpx.OK(c, http.StatusOK, ToRefreshTokenResponse(newAccessToken))
+	result, err := h.service.Impersonate(httpx.ReqCtx(c), user, req.UserID, req.Reason)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, ToImpersonationResponse(result))
+}
+
+// ListSessions godoc
+//
+//	@Summary		List my sessions
+//	@Description	List the devices the current user is logged in on, most recently used first. The session of this request is marked as current
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	httpx.SuccessResponse{data=[]sessions.SessionResponse}
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/auth/sessions [get]
+func (h *Handler) ListSessions(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	list, err := h.service.ListSessions(httpx.ReqCtx(c), user.UserID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, sessions.ToSessionResponseList(list, user.SessionID))
+}
+
+// RevokeSession godoc
+//
+//	@Summary		Revoke one of my sessions
+//	@Description	Log the current user out on one device. Its refresh token stops working and its access tokens are rejected immediately
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			session_id	path	string	true	"Session ID"
+//	@Success		204
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/auth/sessions/{session_id} [delete]
+func (h *Handler) RevokeSession(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params sessions.SessionIDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err = h.service.RevokeSession(httpx.ReqCtx(c), user.UserID, params.SessionID); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	// revoking the session of this request is a logout
+	if params.SessionID == user.SessionID {
+		h.clearRefreshTokenCookie(c)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ForgotPassword godoc
+//
+//	@Summary		Forgot password
+//	@Description	Send a single-use password reset link to the given email. The response is the same whether or not the email is registered
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		auth.ForgotPasswordRequest	true	"Account email"
+//	@Success		202		{object}	auth.MessageResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Router			/auth/password/forgot [post]
+func (h *Handler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err := h.service.ForgotPassword(httpx.ReqCtx(c), req.Email); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusAccepted, MessageResponse{
+		Message: "if the email is registered, a password reset link has been sent",
+	})
+}
+
+// ResetPassword godoc
+//
+//	@Summary		Reset password
+//	@Description	Set a new password using a reset token. All existing sessions of the user are revoked
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	auth.ResetPasswordRequest	true	"Reset token and new password"
+//	@Success		204
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Router			/auth/password/reset [post]
+func (h *Handler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err := h.service.ResetPassword(httpx.ReqCtx(c), req.Token, req.Password); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	h.clearRefreshTokenCookie(c)
+	c.Status(http.StatusNoContent)
+}
+
+// ChangePassword godoc
+//
+//	@Summary		Change my password
+//	@Description	Change the password of the current user. The current password must be given, the new one must not contain the user's email, and all other sessions of the user are revoked
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	auth.ChangePasswordRequest	true	"Current and new password"
+//	@Success		204
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		403	{object}	httpx.ErrorResponse
+//	@Failure		429	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me/password [put]
+func (h *Handler) ChangePassword(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err = h.service.ChangePassword(httpx.ReqCtx(c), user, req.CurrentPassword, req.Password); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RequestEmailChange godoc
+//
+//	@Summary		Change my email
+//	@Description	Request to change the email of the current user. The current password must be given. A confirmation link is sent to the new email, which only replaces the current one once confirmed
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		auth.ChangeEmailRequest	true	"New email and current password"
+//	@Success		202		{object}	auth.MessageResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		409		{object}	httpx.ErrorResponse
+//	@Failure		429		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me/email [post]
+func (h *Handler) RequestEmailChange(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req ChangeEmailRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err = h.service.RequestEmailChange(httpx.ReqCtx(c), user, req.Password, req.Email); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusAccepted, MessageResponse{
+		Message: "a confirmation link has been sent to the new email",
+	})
+}
+
+// ConfirmEmailChange godoc
+//
+//	@Summary		Confirm email change
+//	@Description	Replace the email of a user with the new one they requested, using the token sent to the new email
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	auth.ConfirmEmailChangeRequest	true	"Email change token"
+//	@Success		204
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		409	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Router			/auth/email/confirm [post]
+func (h *Handler) ConfirmEmailChange(c *gin.Context) {
+	var req ConfirmEmailChangeRequest
+	if err := httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err := h.service.ConfirmEmailChange(httpx.ReqCtx(c), req.Token); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Register godoc
+//
+//	@Summary		Register
+//	@Description	Create a user account pending email verification and send a verification link. Only available when public registration is enabled
+//	@Description	The response is the same whether or not the email is registered, the owner of a registered email is notified instead
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		auth.RegisterRequest	true	"Sign-up details"
+//	@Success		202		{object}	auth.MessageResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Router			/auth/register [post]
+func (h *Handler) Register(c *gin.Context) {
+	var req RegisterRequest
+	if err := httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err := h.service.Register(httpx.ReqCtx(c), req.Email, req.Password); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusAccepted, MessageResponse{
+		Message: "check your email to complete the sign-up",
+	})
+}
+
+// VerifyEmail godoc
+//
+//	@Summary		Verify email
+//	@Description	Confirm the email address of a self-registered user so they can log in
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	auth.VerifyEmailRequest	true	"Verification token"
+//	@Success		204
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		409	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Router			/auth/verify-email [post]
+func (h *Handler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err := h.service.VerifyEmail(httpx.ReqCtx(c), req.Token); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResendVerification godoc
+//
+//	@Summary		Resend verification email
+//	@Description	Send a new verification link to a user pending email verification. The response is the same whether or not the email is registered
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		auth.ResendVerificationRequest	true	"Account email"
+//	@Success		202		{object}	auth.MessageResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Router			/auth/verify-email/resend [post]
+func (h *Handler) ResendVerification(c *gin.Context) {
+	var req ResendVerificationRequest
+	if err := httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err := h.service.ResendVerification(httpx.ReqCtx(c), req.Email); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusAccepted, MessageResponse{
+		Message: "if the email is pending verification, a new verification link has been sent",
+	})
+}
+
+// EnrollMFA godoc
+//
+//	@Summary		Start MFA enrollment
+//	@Description	Generate a new TOTP secret for the current user. It has to be confirmed with a code before it is enabled
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	auth.MFAEnrollmentResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		409	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/auth/mfa/enroll [post]
+func (h *Handler) EnrollMFA(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	enrollment, err := h.service.EnrollMFA(httpx.ReqCtx(c), user.UserID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, ToMFAEnrollmentResponse(enrollment))
+}
+
+// ConfirmMFA godoc
+//
+//	@Summary		Confirm MFA enrollment
+//	@Description	Enable the pending TOTP factor with a code from the authenticator app. Recovery codes are returned once
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		auth.MFACodeRequest	true	"TOTP code"
+//	@Success		200		{object}	auth.RecoveryCodesResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		409		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/auth/mfa/confirm [post]
+func (h *Handler) ConfirmMFA(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req MFACodeRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	codes, err := h.service.ConfirmMFA(httpx.ReqCtx(c), user.UserID, req.Code)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableMFA godoc
+//
+//	@Summary		Disable MFA
+//	@Description	Remove the TOTP factor and recovery codes of the current user. Not allowed for roles that require MFA
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	auth.MFACodeRequest	true	"TOTP or recovery code"
+//	@Success		204
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		403	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/auth/mfa/disable [post]
+func (h *Handler) DisableMFA(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req MFACodeRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err = h.service.DisableMFA(httpx.ReqCtx(c), user, req.Code); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes godoc
+//
+//	@Summary		Regenerate MFA recovery codes
+//	@Description	Replace all recovery codes of the current user. The new codes are returned once
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		auth.MFACodeRequest	true	"TOTP or recovery code"
+//	@Success		200		{object}	auth.RecoveryCodesResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/auth/mfa/recovery-codes [post]
+func (h *Handler) RegenerateRecoveryCodes(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req MFACodeRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(httpx.ReqCtx(c), user.UserID, req.Code)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// JWKS godoc
+//
+//	@Summary		JSON Web Key Set
+//	@Description	Public keys to verify access tokens issued by this service (RFC 7517). Shared HMAC secrets are never published
+//	@Tags			Auth
+//	@Produce		json
+//	@Success		200	{object}	security.JWKSet
+//	@Router			/.well-known/jwks.json [get]
+func (h *Handler) JWKS(c *gin.Context) {
+	// served as a bare document (no response envelope) since that is what jwt libraries expect
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", constants.JWKSCacheMaxAgeSecond))
+	c.JSON(http.StatusOK, h.appCtx.SecurityHandler.JWKS())
+}
+
+// respondWithLogin sets the refresh token cookie and writes the login response
+func (h *Handler) respondWithLogin(c *gin.Context, result *LoginResult) {
+	h.setRefreshTokenCookie(c, result.Tokens.RefreshToken)
+
+	res := ToLoginResponse(result.Tokens.AccessToken, result.User)
+	res.RecoveryCodes = result.RecoveryCodes
+	httpx.OK(c, http.StatusOK, res)
+}
+
+// setRefreshTokenCookie writes the refresh token into an http-only cookie
+func (h *Handler) setRefreshTokenCookie(c *gin.Context, refreshToken string) {
+	cookieMaxAge := h.appCtx.Cfg.JWT.RefreshTokenExpirationSecond
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(
+		constants.RefreshTokenCookieName,        // name
+		refreshToken,                            // value
+		cookieMaxAge,                            // max age
+		"/",                                     // path
+		"",                                      // domain (empty for localhost)
+		h.appCtx.Cfg.AppEnv != constants.EnvDev, // secure (set to true in production with HTTPS)
+		true,                                    // httpOnly
+	)
+}
+
+// clearRefreshTokenCookie expires the refresh token cookie on the client
+func (h *Handler) clearRefreshTokenCookie(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(
+		constants.RefreshTokenCookieName,
+		"",
+		-1, // max age < 0 deletes the cookie
+		"/",
+		"",
+		h.appCtx.Cfg.AppEnv != constants.EnvDev,
+		true,
+	)
+}
+
+// setOIDCFlowCookie writes the oidc flow into an http-only cookie only sent to the oidc endpoints.
+// SameSite=Lax lets it survive the top-level redirect back from the provider. maxAge < 0 deletes it.
+func (h *Handler) setOIDCFlowCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(
+		constants.OIDCFlowCookieName,
+		value,
+		maxAge,
+		constants.APIAuthPath+"/oidc",
+		"",
+		h.appCtx.Cfg.AppEnv != constants.EnvDev,
+		true,
+	)
+}
+
+// clientDevice extracts the client details a refresh token is bound to
+func clientDevice(c *gin.Context) sessions.Device {
+	return sessions.Device{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
 }
@@ -2,86 +2,990 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
 
-	"golang.org/x/crypto/bcrypt"
+	"github.com/rs/zerolog/log"
 
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/audit"
+	"github.com/mrhpn/go-rest-api/internal/config"
+	"github.com/mrhpn/go-rest-api/internal/mailer"
+	"github.com/mrhpn/go-rest-api/internal/modules/identities"
+	"github.com/mrhpn/go-rest-api/internal/modules/mfa"
+	"github.com/mrhpn/go-rest-api/internal/modules/sessions"
 	"github.com/mrhpn/go-rest-api/internal/modules/users"
+	"github.com/mrhpn/go-rest-api/internal/modules/usertokens"
+	"github.com/mrhpn/go-rest-api/internal/oidc"
 	"github.com/mrhpn/go-rest-api/internal/security"
 )
 
 type userProvider interface {
 	GetByID(ctx context.Context, id string) (*users.User, error)
 	GetByEmail(ctx context.Context, email string) (*users.User, error)
-	Activate(ctx context.Context, id string) (*users.User, error)
+	RecordLogin(ctx context.Context, id, ip string) (*users.User, error)
+	RecordFailedLogin(ctx context.Context, id string) error
+	Register(ctx context.Context, email, password string) (*users.User, error)
+	RegisterExternal(ctx context.Context, email string) (*users.User, error)
+	VerifyEmail(ctx context.Context, id string) error
+	SetPassword(ctx context.Context, id, password string) error
+	DiscardPassword(ctx context.Context, id string) error
+	VerifyPassword(ctx context.Context, user *users.User, password string) (bool, error)
+	RequestEmailChange(ctx context.Context, id, email string) error
+	ConfirmEmailChange(ctx context.Context, id string) (*users.User, error)
+}
+
+type userTokenStore interface {
+	Issue(ctx context.Context, userID string, purpose usertokens.Purpose, ttl time.Duration) (string, error)
+	Consume(ctx context.Context, rawToken string, purpose usertokens.Purpose) (*usertokens.Token, error)
+}
+
+// transactor runs fn in a database transaction shared by the stores called with its ctx.
+type transactor interface {
+	Transaction(ctx context.Context, fn func(context.Context) error) error
+}
+
+type sessionStore interface {
+	Start(ctx context.Context, userID string, token sessions.IssuedToken, device sessions.Device) (string, error)
+	Rotate(ctx context.Context, tokenID, userID string, device sessions.Device, issue sessions.IssueFunc) error
+	Revoke(ctx context.Context, tokenID, userID string) error
+	RevokeAll(ctx context.Context, userID string) error
+	ListActive(ctx context.Context, userID string) ([]*sessions.Session, error)
+	RevokeSession(ctx context.Context, sessionID, userID string) error
+}
+
+type tokenRevoker interface {
+	RevokeToken(ctx context.Context, claims *security.UserClaims) error
+	RevokeUser(ctx context.Context, userID string) error
+	RevokeSession(ctx context.Context, sessionID string) error
+	IsRevoked(ctx context.Context, claims *security.UserClaims) (bool, error)
+}
+
+type accountLockout interface {
+	Check(ctx context.Context, account string) (security.LockoutStatus, error)
+	RegisterFailure(ctx context.Context, account string) (security.LockoutStatus, error)
+	Reset(ctx context.Context, account string) error
+}
+
+type mfaProvider interface {
+	IsEnabled(ctx context.Context, userID string) (bool, error)
+	Enroll(ctx context.Context, userID, account string) (*mfa.Enrollment, error)
+	Confirm(ctx context.Context, userID, code string) ([]string, error)
+	Verify(ctx context.Context, userID, code string) error
+	Disable(ctx context.Context, userID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+}
+
+type oidcProviders interface {
+	Get(name string) (*oidc.Provider, bool)
+}
+
+type identityStore interface {
+	Find(ctx context.Context, provider, subject string) (*identities.Identity, error)
+	Link(ctx context.Context, userID string, identity *oidc.Identity) (*identities.Identity, error)
 }
 
 type service struct {
 	userProvider    userProvider
+	sessionStore    sessionStore
+	tokenRevoker    tokenRevoker
+	userTokens      userTokenStore
+	transactor      transactor
+	mfa             mfaProvider
+	lockout         accountLockout
+	auditor         audit.Recorder
+	mailer          mailer.Mailer
+	oidcProviders   oidcProviders
+	identities      identityStore
 	securityHandler *security.JWTHandler
+	cfg             config.AuthConfig
+	oidcCfg         config.OIDCConfig
 }
 
 // NewService - constructs Auth Service
-func NewService(userProvider userProvider, jwtHandler *security.JWTHandler) Service {
+func NewService(
+	userProvider userProvider,
+	sessionStore sessionStore,
+	tokenRevoker tokenRevoker,
+	userTokens userTokenStore,
+	transactor transactor,
+	mfaProvider mfaProvider,
+	lockout accountLockout,
+	auditor audit.Recorder,
+	mail mailer.Mailer,
+	oidcProviders oidcProviders,
+	identities identityStore,
+	jwtHandler *security.JWTHandler,
+	cfg config.AuthConfig,
+	oidcCfg config.OIDCConfig,
+) Service {
 	return &service{
 		userProvider:    userProvider,
+		sessionStore:    sessionStore,
+		tokenRevoker:    tokenRevoker,
+		userTokens:      userTokens,
+		transactor:      transactor,
+		mfa:             mfaProvider,
+		lockout:         lockout,
+		auditor:         auditor,
+		mailer:          mail,
+		oidcProviders:   oidcProviders,
+		identities:      identities,
 		securityHandler: jwtHandler,
+		cfg:             cfg,
+		oidcCfg:         oidcCfg,
 	}
 }
 
-func (s *service) Login(ctx context.Context, email, password string) (*security.TokenPair, *users.User, error) {
-	// 1. get user from User module
+func (s *service) Login(ctx context.Context, email, password string, device sessions.Device) (*LoginResult, error) {
+	// 0. refuse attempts while the account is locked or has to wait after a failure
+	if err := s.checkLockout(ctx, email); err != nil {
+		return nil, err
+	}
+
+	// 1. get user from User module. unknown emails count as failures too, so they behave like existing ones
 	user, err := s.userProvider.GetByEmail(ctx, email)
 	if err != nil {
-		return nil, nil, errInvalidCrendentials
+		return nil, s.loginFailed(ctx, email, "", errInvalidCrendentials)
 	}
 
-	// 2. verify password
-	if err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return nil, nil, errInvalidCrendentials
+	// 2. verify password (hashes created with outdated settings are upgraded transparently)
+	validPassword, err := s.userProvider.VerifyPassword(ctx, user, password)
+	if err != nil {
+		return nil, err
+	}
+	if !validPassword {
+		return nil, s.loginFailed(ctx, email, user.ID, errInvalidCrendentials)
 	}
 
-	// 3. check if user is blocked
+	// 3. check if user is blocked or hasn't verified their email yet
 	if user.Status == security.UserStatusBlocked {
-		return nil, nil, security.ErrBlockedUser
+		return nil, security.ErrBlockedUser
+	}
+	if user.Status == security.UserStatusPendingVerification {
+		return nil, errEmailNotVerified
 	}
 
-	// 4. update user status to active on successful login
-	if user, err = s.userProvider.Activate(ctx, user.ID); err != nil {
-		return nil, nil, err
+	// 4. ask for a second factor or issue the tokens
+	return s.beginLogin(ctx, user, device)
+}
+
+func (s *service) StartOIDC(ctx context.Context, providerName string) (string, *oidc.Flow, error) {
+	provider, ok := s.oidcProviders.Get(providerName)
+	if !ok {
+		return "", nil, errOIDCProviderNotFound
 	}
 
-	// 6. create token pair
-	tokens, err := s.securityHandler.GenerateTokenPair(user.ID, user.Role)
+	flow, err := oidc.NewFlow(providerName)
 	if err != nil {
-		return nil, nil, errTokenGeneration
+		return "", nil, apperror.Wrap(apperror.Internal, apperror.ErrInternal.Code, "failed to start oidc login", err)
 	}
 
-	return tokens, user, nil
+	authURL, err := provider.AuthCodeURL(ctx, flow)
+	if err != nil {
+		return "", nil, apperror.Wrap(apperror.Internal, errOIDCProviderUnavailable.Code, errOIDCProviderUnavailable.Message, err)
+	}
+
+	return authURL, flow, nil
+}
+
+func (s *service) CompleteOIDC(
+	ctx context.Context,
+	providerName, code, state string,
+	flow *oidc.Flow,
+	device sessions.Device,
+) (*LoginResult, error) {
+	provider, ok := s.oidcProviders.Get(providerName)
+	if !ok {
+		return nil, errOIDCProviderNotFound
+	}
+
+	// 1. the callback must belong to a login started by this browser for this provider (CSRF, mix-up)
+	if flow == nil || flow.Provider != providerName || subtle.ConstantTimeCompare([]byte(flow.State), []byte(state)) != 1 {
+		return nil, errOIDCInvalidState
+	}
+
+	// 2. redeem the code and verify the id token (signature, issuer, audience, expiry, nonce)
+	identity, err := provider.Exchange(ctx, code, flow)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("provider", providerName).Msg("oidc code exchange failed")
+		return nil, errOIDCLoginFailed
+	}
+
+	// 3. map the external identity to a user
+	user, err := s.resolveIdentity(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. ask for a second factor or issue the tokens, like a password login
+	return s.beginLogin(ctx, user, device)
+}
+
+func (s *service) LoginMFA(ctx context.Context, mfaToken, code string, device sessions.Device) (*LoginResult, error) {
+	// 1. the challenge proves the password step was passed
+	claims, user, err := s.validateMFAChallenge(ctx, mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. second factor guessing counts towards the same lockout as password guessing
+	if err = s.checkLockout(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
+	// 3. verify the second factor. a user completing a mandatory enrollment confirms the pending factor instead
+	var recoveryCodes []string
+	mfaEnabled, err := s.mfa.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !mfaEnabled && s.mfaRequired(user.Role) {
+		recoveryCodes, err = s.mfa.Confirm(ctx, user.ID, code)
+	} else {
+		err = s.mfa.Verify(ctx, user.ID, code)
+	}
+	if hasKind(err, apperror.Unauthorized) {
+		return nil, s.loginFailed(ctx, user.Email, user.ID, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. a challenge can only be completed once
+	if err = s.tokenRevoker.RevokeToken(ctx, claims); err != nil {
+		return nil, apperror.Wrap(apperror.Internal, errTokenRevocation.Code, errTokenRevocation.Message, err)
+	}
+
+	result, err := s.completeLogin(ctx, user, device)
+	if err != nil {
+		return nil, err
+	}
+	result.RecoveryCodes = recoveryCodes
+	return result, nil
+}
+
+func (s *service) SetupMFA(ctx context.Context, mfaToken string) (*mfa.Enrollment, error) {
+	_, user, err := s.validateMFAChallenge(ctx, mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mfa.Enroll(ctx, user.ID, user.Email)
+}
+
+func (s *service) EnrollMFA(ctx context.Context, userID string) (*mfa.Enrollment, error) {
+	user, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mfa.Enroll(ctx, user.ID, user.Email)
+}
+
+func (s *service) ConfirmMFA(ctx context.Context, userID, code string) ([]string, error) {
+	return s.mfa.Confirm(ctx, userID, code)
+}
+
+func (s *service) DisableMFA(ctx context.Context, claims *security.UserClaims, code string) error {
+	if s.mfaRequired(claims.Role) {
+		return errMFARequired
+	}
+
+	return s.mfa.Disable(ctx, claims.UserID, code)
+}
+
+func (s *service) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	return s.mfa.RegenerateRecoveryCodes(ctx, userID, code)
 }
 
-func (s *service) RefreshToken(ctx context.Context, refreshToken string) (string, error) {
+func (s *service) RefreshToken(ctx context.Context, refreshToken string, device sessions.Device) (*security.TokenPair, error) {
 	// 1. validate the signature and expiry of the refresh token
-	claims, err := s.securityHandler.ValidateToken(refreshToken)
+	claims, err := s.securityHandler.ValidateRefreshToken(refreshToken)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// 2. hit the db: ensure the user still exists and isn't blocked
-	user, err := s.userProvider.GetByID(ctx, claims.UserID)
+	// 2. rotate server-side: the token is only used up once its successor is stored.
+	// a replayed token revokes its whole family
+	var tokens *security.TokenPair
+	err = s.sessionStore.Rotate(ctx, claims.ID, claims.UserID, device,
+		func(txCtx context.Context, current *sessions.RefreshToken) (sessions.IssuedToken, error) {
+			// 3. hit the db: ensure the user still exists and isn't blocked
+			user, userErr := s.userProvider.GetByID(txCtx, claims.UserID)
+			if userErr != nil {
+				return sessions.IssuedToken{}, security.ErrInvalidToken
+			}
+
+			// 4. check user status. if user is blocked, can't get new token
+			if user.Status == security.UserStatusBlocked {
+				return sessions.IssuedToken{}, security.ErrBlockedUser
+			}
+
+			// 5. issue a brand new pair within the same family (session)
+			pair, genErr := s.securityHandler.GenerateTokenPair(user.ID, user.Role, current.FamilyID)
+			if genErr != nil {
+				return sessions.IssuedToken{}, errTokenGeneration
+			}
+			tokens = pair
+			return issuedRefreshToken(pair), nil
+		})
 	if err != nil {
-		return "", security.ErrInvalidToken
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (s *service) Logout(ctx context.Context, claims *security.UserClaims, refreshToken string) error {
+	// 1. revoke the refresh token family of this device (if the cookie is still around).
+	// an impersonation token has no session of its own, the cookie belongs to the admin then
+	if refreshToken != "" && !claims.IsImpersonated() {
+		refreshClaims, err := s.securityHandler.ValidateRefreshToken(refreshToken)
+		switch {
+		case err != nil:
+			// an invalid/expired refresh token cannot be used anymore anyway
+			log.Ctx(ctx).Debug().Err(err).Msg("ignoring invalid refresh token on logout")
+		case refreshClaims.UserID != claims.UserID:
+			return security.ErrInvalidToken
+		default:
+			if err = s.sessionStore.Revoke(ctx, refreshClaims.ID, claims.UserID); err != nil &&
+				!errors.Is(err, security.ErrInvalidToken) {
+				return err
+			}
+		}
+	}
+
+	// 2. denylist the access token used for this request
+	if err := s.tokenRevoker.RevokeToken(ctx, claims); err != nil {
+		return apperror.Wrap(apperror.Internal, errTokenRevocation.Code, errTokenRevocation.Message, err)
 	}
 
-	// 3. check user status. if user is blocked, can't get new token
+	log.Ctx(ctx).Info().Str("user_id", claims.UserID).Msg("user logged out")
+	return nil
+}
+
+func (s *service) LogoutAll(ctx context.Context, userID string) error {
+	// 1. revoke all refresh token families (every device)
+	if err := s.sessionStore.RevokeAll(ctx, userID); err != nil {
+		return err
+	}
+
+	// 2. invalidate every access token issued so far
+	if err := s.tokenRevoker.RevokeUser(ctx, userID); err != nil {
+		return apperror.Wrap(apperror.Internal, errTokenRevocation.Code, errTokenRevocation.Message, err)
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", userID).Msg("user logged out from all devices")
+	return nil
+}
+
+func (s *service) Impersonate(ctx context.Context, actor *security.UserClaims, userID, reason string) (*ImpersonationResult, error) {
+	// 1. superadmins can't be impersonated, that would hand out the highest privileges without their credentials
+	if actor.UserID == userID {
+		return nil, errImpersonateSelf
+	}
+	user, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == security.RoleSuperAdmin {
+		return nil, errImpersonateSuperAdmin
+	}
 	if user.Status == security.UserStatusBlocked {
-		return "", security.ErrBlockedUser
+		return nil, security.ErrBlockedUser
+	}
+
+	// 2. short-lived access token for the user, naming the admin in the act claim. no refresh token
+	token, expiresAt, err := s.securityHandler.GenerateImpersonationToken(
+		user.ID,
+		user.Role,
+		security.Actor{UserID: actor.UserID, Role: actor.Role},
+	)
+	if err != nil {
+		return nil, apperror.Wrap(apperror.Internal, errTokenGeneration.Code, errTokenGeneration.Message, err)
+	}
+
+	// 3. every impersonation has to be traceable, so a failed audit record fails the request
+	err = s.auditor.Record(ctx, audit.Entry{
+		Action:     audit.ActionImpersonationStarted,
+		TargetType: audit.TargetUser,
+		TargetID:   user.ID,
+		Metadata: map[string]any{
+			"reason":     reason,
+			"expires_at": expiresAt,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Warn().
+		Str("actor_id", actor.UserID).
+		Str("user_id", user.ID).
+		Msg("impersonation started")
+	return &ImpersonationResult{AccessToken: token, ExpiresAt: expiresAt, User: user}, nil
+}
+
+func (s *service) ListSessions(ctx context.Context, userID string) ([]*sessions.Session, error) {
+	return s.sessionStore.ListActive(ctx, userID)
+}
+
+func (s *service) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	// 1. end the refresh token family, so the session can't be refreshed anymore
+	if err := s.sessionStore.RevokeSession(ctx, sessionID, userID); err != nil {
+		return err
+	}
+
+	// 2. reject the access tokens already issued for it
+	if err := s.tokenRevoker.RevokeSession(ctx, sessionID); err != nil {
+		return apperror.Wrap(apperror.Internal, errTokenRevocation.Code, errTokenRevocation.Message, err)
+	}
+
+	return nil
+}
+
+func (s *service) ForgotPassword(ctx context.Context, email string) error {
+	// 1. find the user. unknown emails are not reported to avoid account enumeration
+	user, err := s.userProvider.GetByEmail(ctx, email)
+	if err != nil {
+		if hasKind(err, apperror.NotFound) {
+			log.Ctx(ctx).Info().Msg("password reset requested for unknown email")
+			return nil
+		}
+		return err
+	}
+
+	// 2. blocked users can't regain access by resetting their password
+	if user.Status == security.UserStatusBlocked {
+		log.Ctx(ctx).Info().Str("user_id", user.ID).Msg("password reset requested for blocked user")
+		return nil
+	}
+
+	// 3. issue a single-use reset token (invalidates any previous one)
+	ttl := time.Duration(s.cfg.PasswordResetTokenExpirationSecond) * time.Second
+	token, err := s.userTokens.Issue(ctx, user.ID, usertokens.PurposePasswordReset, ttl)
+	if err != nil {
+		return err
+	}
+
+	// 4. deliver the link
+	body := "We received a request to reset your password.\n\n" +
+		"Use the link below to choose a new one. It expires in %d minutes and can only be used once.\n\n" +
+		"%s\n\n" +
+		"If you did not request this, you can ignore this email.\n"
+	if err = s.sendTokenLink(ctx, user.Email, "Reset your password", body, s.cfg.PasswordResetURL, token, ttl); err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", user.ID).Msg("password reset requested")
+	return nil
+}
+
+func (s *service) ResetPassword(ctx context.Context, rawToken, newPassword string) error {
+	// the token is only used up if the password is actually reset
+	var userID string
+	err := s.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		// 1. consume the token, it can only be used once
+		token, err := s.userTokens.Consume(txCtx, rawToken, usertokens.PurposePasswordReset)
+		if err != nil {
+			return err
+		}
+		userID = token.UserID
+
+		// 2. set the new password
+		if err = s.userProvider.SetPassword(txCtx, token.UserID, newPassword); err != nil {
+			return err
+		}
+
+		// 3. whoever knew the old password must lose access
+		return s.LogoutAll(txCtx, token.UserID)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", userID).Msg("password reset completed")
+	return nil
+}
+
+func (s *service) ChangePassword(ctx context.Context, claims *security.UserClaims, currentPassword, newPassword string) error {
+	// 1. a stolen access token alone must not be enough to take over the account
+	user, err := s.Reauthenticate(ctx, claims.UserID, currentPassword)
+	if err != nil {
+		return err
+	}
+
+	// 2. set the new password. the request has no email, SetPassword checks it against the stored one
+	if err = s.userProvider.SetPassword(ctx, user.ID, newPassword); err != nil {
+		return err
+	}
+
+	// 3. the other devices may be in the hands of whoever knew the old password
+	if err = s.revokeOtherSessions(ctx, claims); err != nil {
+		return err
+	}
+
+	if err = s.auditor.Record(ctx, audit.Entry{
+		Action:     audit.ActionPasswordChanged,
+		TargetType: audit.TargetUser,
+		TargetID:   user.ID,
+	}); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID).Msg("failed to record password change")
+	}
+
+	return nil
+}
+
+func (s *service) RequestEmailChange(ctx context.Context, claims *security.UserClaims, password, newEmail string) error {
+	// 1. re-authenticate, the email is what password resets are sent to
+	user, err := s.Reauthenticate(ctx, claims.UserID, password)
+	if err != nil {
+		return err
+	}
+
+	// 2. remember the new address, the current one stays in use until it's confirmed
+	if err = s.userProvider.RequestEmailChange(ctx, user.ID, newEmail); err != nil {
+		return err
 	}
 
-	// 4. generate ONLY a new access token
-	accessToken, err := s.securityHandler.GenerateAccessToken(user.ID, user.Role)
+	// 3. prove ownership of the new address with a single-use link (invalidates any previous one)
+	ttl := time.Duration(s.cfg.EmailChangeTokenExpirationSecond) * time.Second
+	token, err := s.userTokens.Issue(ctx, user.ID, usertokens.PurposeEmailChange, ttl)
 	if err != nil {
-		return "", errTokenGeneration
+		return err
 	}
 
-	return accessToken, nil
+	body := "We received a request to use this address for your account.\n\n" +
+		"Use the link below to confirm it. It expires in %d minutes and can only be used once.\n\n" +
+		"%s\n\n" +
+		"If you did not request this, you can ignore this email.\n"
+	if err = s.sendTokenLink(ctx, newEmail, "Confirm your new email", body, s.cfg.EmailChangeURL, token, ttl); err != nil {
+		return err
+	}
+
+	// 4. tell the current address, so an unexpected change doesn't go unnoticed
+	notice := mailer.Message{
+		To:      user.Email,
+		Subject: "Your email is about to change",
+		Body: "We received a request to change the email of your account.\n\n" +
+			"The change only takes effect once the new address is confirmed. " +
+			"If you did not request this, change your password right away.\n",
+	}
+	if err = s.mailer.Send(ctx, notice); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID).Msg("failed to send email change notice")
+	}
+
+	return nil
+}
+
+func (s *service) ConfirmEmailChange(ctx context.Context, rawToken string) error {
+	// the token is only used up if the email is actually swapped, e.g. not when the new one was taken meanwhile
+	return s.transactor.Transaction(ctx, func(txCtx context.Context) error {
+		token, err := s.userTokens.Consume(txCtx, rawToken, usertokens.PurposeEmailChange)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.userProvider.ConfirmEmailChange(txCtx, token.UserID)
+		return err
+	})
+}
+
+func (s *service) Register(ctx context.Context, email, password string) error {
+	if !s.cfg.RegistrationEnabled {
+		return errRegistrationDisabled
+	}
+
+	// 1. create the account in pending_verification status
+	user, err := s.userProvider.Register(ctx, email, password)
+	if hasKind(err, apperror.Conflict) {
+		// the email is taken. the response must not tell, so only its owner learns about the attempt
+		s.sendExistingAccountEmail(ctx, email)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// 2. send the verification link. the account exists already, so a delivery failure
+	// is only logged and the user can ask for a new link
+	if err = s.sendVerificationEmail(ctx, user); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID).Msg("failed to send verification email")
+	}
+
+	return nil
+}
+
+// sendExistingAccountEmail tells the owner of an email that someone tried to sign up with it.
+// A delivery failure is only logged, like the verification email of a new account.
+func (s *service) sendExistingAccountEmail(ctx context.Context, email string) {
+	msg := mailer.Message{
+		To:      email,
+		Subject: "You already have an account",
+		Body: "Someone tried to sign up with this email address, but an account already exists for it.\n\n" +
+			"If it was you, log in instead or choose a new password at:\n\n" +
+			s.cfg.PasswordResetURL + "\n\n" +
+			"If you did not try to sign up, you can ignore this email.\n",
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to send existing account email")
+		return
+	}
+
+	log.Ctx(ctx).Info().Msg("sign-up attempted with a registered email")
+}
+
+func (s *service) VerifyEmail(ctx context.Context, rawToken string) error {
+	token, err := s.userTokens.Consume(ctx, rawToken, usertokens.PurposeEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	return s.userProvider.VerifyEmail(ctx, token.UserID)
+}
+
+func (s *service) ResendVerification(ctx context.Context, email string) error {
+	// unknown or already verified emails are not reported to avoid account enumeration
+	user, err := s.userProvider.GetByEmail(ctx, email)
+	if err != nil {
+		if hasKind(err, apperror.NotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.Status != security.UserStatusPendingVerification {
+		return nil
+	}
+
+	return s.sendVerificationEmail(ctx, user)
+}
+
+func (s *service) sendVerificationEmail(ctx context.Context, user *users.User) error {
+	ttl := time.Duration(s.cfg.EmailVerificationTokenExpirationSecond) * time.Second
+	token, err := s.userTokens.Issue(ctx, user.ID, usertokens.PurposeEmailVerification, ttl)
+	if err != nil {
+		return err
+	}
+
+	body := "Welcome! Please confirm your email address to activate your account.\n\n" +
+		"The link below expires in %d minutes and can only be used once.\n\n" +
+		"%s\n\n" +
+		"If you did not sign up, you can ignore this email.\n"
+	return s.sendTokenLink(ctx, user.Email, "Verify your email", body, s.cfg.EmailVerificationURL, token, ttl)
+}
+
+// sendTokenLink mails a link carrying a single-use token. body must contain a %d verb for the
+// validity in minutes followed by a %s verb for the link.
+func (s *service) sendTokenLink(ctx context.Context, to, subject, body, baseURL, token string, ttl time.Duration) error {
+	msg := mailer.Message{
+		To:      to,
+		Subject: subject,
+		Body:    fmt.Sprintf(body, int(ttl.Minutes()), withToken(baseURL, token)),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return apperror.Wrap(apperror.Internal, errMailDelivery.Code, errMailDelivery.Message, err)
+	}
+	return nil
+}
+
+// beginLogin continues a login after the first factor: users with a second factor (or whose role
+// requires one) only get a challenge, everyone else gets the tokens right away
+func (s *service) beginLogin(ctx context.Context, user *users.User, device sessions.Device) (*LoginResult, error) {
+	mfaEnabled, err := s.mfa.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfaEnabled || s.mfaRequired(user.Role) {
+		challenge, challengeErr := s.securityHandler.GenerateMFAChallengeToken(user.ID, user.Role)
+		if challengeErr != nil {
+			return nil, errTokenGeneration
+		}
+		return &LoginResult{
+			User:                  user,
+			MFAToken:              challenge,
+			MFAEnrollmentRequired: !mfaEnabled,
+		}, nil
+	}
+
+	return s.completeLogin(ctx, user, device)
+}
+
+// resolveIdentity returns the user an external identity is linked to. An identity that is not
+// linked yet is linked to the user with the same (provider verified) email, or to a new account
+// when auto registration is enabled.
+func (s *service) resolveIdentity(ctx context.Context, identity *oidc.Identity) (*users.User, error) {
+	// 1. already linked
+	link, err := s.identities.Find(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		user, userErr := s.userProvider.GetByID(ctx, link.UserID)
+		if userErr != nil {
+			return nil, userErr
+		}
+		if user.Status == security.UserStatusBlocked {
+			return nil, security.ErrBlockedUser
+		}
+		return user, nil
+	}
+	if !hasKind(err, apperror.NotFound) {
+		return nil, err
+	}
+
+	// 2. an unverified email could belong to anyone, it must never grant access to an account
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, errOIDCEmailNotVerified
+	}
+
+	user, err := s.userProvider.GetByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+	case hasKind(err, apperror.NotFound) && s.oidcCfg.AutoRegister:
+		if user, err = s.userProvider.RegisterExternal(ctx, identity.Email); err != nil {
+			return nil, err
+		}
+	case hasKind(err, apperror.NotFound):
+		return nil, errOIDCAccountNotLinked
+	default:
+		return nil, err
+	}
+
+	if user.Status == security.UserStatusBlocked {
+		return nil, security.ErrBlockedUser
+	}
+	// the provider proved ownership of the email, so a pending sign-up counts as verified. Whoever
+	// signed up never proved it though, it may be someone who claimed the email first. Their
+	// password is replaced like for provider registrations and anything they were issued is revoked
+	if user.Status == security.UserStatusPendingVerification {
+		if err = s.discardUnverifiedCredentials(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		if err = s.userProvider.VerifyEmail(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		user.Status = security.UserStatusInactive
+	}
+
+	// 3. link, so later logins don't depend on the email anymore
+	if _, err = s.identities.Link(ctx, user.ID, identity); err != nil {
+		return nil, err
+	}
+	if err = s.auditor.Record(ctx, audit.Entry{
+		Action:     audit.ActionIdentityLinked,
+		TargetType: audit.TargetUser,
+		TargetID:   user.ID,
+		Metadata: map[string]any{
+			"provider": identity.Provider,
+			"subject":  identity.Subject,
+		},
+	}); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID).Msg("failed to record identity link")
+	}
+
+	return user, nil
+}
+
+// discardUnverifiedCredentials replaces the password of an account whose email was never verified
+// with a random one, which the owner can replace with the password reset flow, and logs it out
+func (s *service) discardUnverifiedCredentials(ctx context.Context, userID string) error {
+	if err := s.userProvider.DiscardPassword(ctx, userID); err != nil {
+		return err
+	}
+	return s.LogoutAll(ctx, userID)
+}
+
+// completeLogin marks the user active and starts a new refresh token family for the device
+func (s *service) completeLogin(ctx context.Context, user *users.User, device sessions.Device) (*LoginResult, error) {
+	// 1. update user status to active and record the login
+	user, err := s.userProvider.RecordLogin(ctx, user.ID, device.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. create token pair for a new session
+	tokens, err := s.securityHandler.GenerateTokenPair(user.ID, user.Role, "")
+	if err != nil {
+		return nil, errTokenGeneration
+	}
+
+	// 3. persist the refresh token as the head of a new family
+	if _, err = s.sessionStore.Start(ctx, user.ID, issuedRefreshToken(tokens), device); err != nil {
+		return nil, err
+	}
+
+	// 4. forget earlier failed attempts
+	if err = s.lockout.Reset(ctx, user.Email); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID).Msg("failed to reset login failures")
+	}
+
+	return &LoginResult{Tokens: tokens, User: user}, nil
+}
+
+// checkLockout rejects a login attempt for a locked or throttled account. Lockout state errors
+// are logged but don't block logins, the ip based rate limit still applies then.
+func (s *service) checkLockout(ctx context.Context, account string) error {
+	status, err := s.lockout.Check(ctx, account)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to check account lockout")
+		return nil
+	}
+
+	if status.Locked {
+		return security.ErrAccountLocked
+	}
+	if status.RetryAfter > 0 {
+		return security.ErrLoginThrottled
+	}
+	return nil
+}
+
+// loginFailed counts a failed attempt and returns the error for the client: ErrAccountLocked
+// when this attempt locked the account, otherwise failure
+func (s *service) loginFailed(ctx context.Context, account, userID string, failure error) error {
+	if userID != "" {
+		if err := s.userProvider.RecordFailedLogin(ctx, userID); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("user_id", userID).Msg("failed to record failed login")
+		}
+	}
+
+	status, err := s.lockout.RegisterFailure(ctx, account)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to register failed login attempt")
+		return failure
+	}
+	if !status.Locked {
+		return failure
+	}
+
+	log.Ctx(ctx).Warn().
+		Str("user_id", userID).
+		Int64("attempts", status.Attempts).
+		Dur("locked_for", status.RetryAfter).
+		Msg("account locked after too many failed login attempts")
+
+	if err = s.auditor.Record(ctx, audit.Entry{
+		Action:     audit.ActionAccountLocked,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Metadata: map[string]any{
+			"account":           account,
+			"attempts":          status.Attempts,
+			"locked_for_second": int(status.RetryAfter.Seconds()),
+		},
+	}); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to record account lockout")
+	}
+
+	return security.ErrAccountLocked
+}
+
+// Reauthenticate confirms the password of a logged-in user before a sensitive account change.
+// Wrong passwords count towards the login lockout, so the check can't be used to guess it.
+func (s *service) Reauthenticate(ctx context.Context, userID, password string) (*users.User, error) {
+	user, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.checkLockout(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
+	validPassword, err := s.userProvider.VerifyPassword(ctx, user, password)
+	if err != nil {
+		return nil, err
+	}
+	if !validPassword {
+		return nil, s.loginFailed(ctx, user.Email, user.ID, errWrongPassword)
+	}
+
+	if err = s.lockout.Reset(ctx, user.Email); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID).Msg("failed to reset login failures")
+	}
+
+	return user, nil
+}
+
+// revokeOtherSessions ends every session of the user except the one of the current request
+func (s *service) revokeOtherSessions(ctx context.Context, claims *security.UserClaims) error {
+	// tokens issued before sessions were tracked can't tell which session is theirs
+	if claims.SessionID == "" {
+		return s.LogoutAll(ctx, claims.UserID)
+	}
+
+	active, err := s.sessionStore.ListActive(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+	for _, session := range active {
+		if session.ID == claims.SessionID {
+			continue
+		}
+		if err = s.RevokeSession(ctx, claims.UserID, session.ID); err != nil {
+			return err
+		}
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", claims.UserID).Msg("other sessions of user revoked")
+	return nil
+}
+
+// validateMFAChallenge checks an mfa challenge token and returns its claims and the (still allowed) user
+func (s *service) validateMFAChallenge(ctx context.Context, mfaToken string) (*security.UserClaims, *users.User, error) {
+	claims, err := s.securityHandler.ValidateMFAChallengeToken(mfaToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	revoked, err := s.tokenRevoker.IsRevoked(ctx, claims)
+	if err != nil {
+		return nil, nil, apperror.Wrap(apperror.Internal, errTokenRevocation.Code, errTokenRevocation.Message, err)
+	}
+	if revoked {
+		return nil, nil, security.ErrRevokedToken
+	}
+
+	user, err := s.userProvider.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, nil, security.ErrInvalidToken
+	}
+	if user.Status == security.UserStatusBlocked {
+		return nil, nil, security.ErrBlockedUser
+	}
+
+	return claims, user, nil
+}
+
+// mfaRequired reports whether the role must use a second factor
+func (s *service) mfaRequired(role security.Role) bool {
+	return slices.Contains(s.cfg.MFARequiredRoles, role)
+}
+
+func issuedRefreshToken(tokens *security.TokenPair) sessions.IssuedToken {
+	return sessions.IssuedToken{
+		ID:        tokens.RefreshTokenID,
+		ExpiresAt: tokens.RefreshTokenExpiresAt,
+	}
+}
+
+// hasKind reports whether err is an AppError of the given kind
+func hasKind(err error, kind apperror.Kind) bool {
+	var appErr *apperror.AppError
+	return errors.As(err, &appErr) && appErr.Kind == kind
+}
+
+// withToken appends the raw token as ?token= to the given frontend URL
+func withToken(rawURL, token string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL + "?token=" + url.QueryEscape(token)
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String()
 }
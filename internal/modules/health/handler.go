@@ -3,6 +3,7 @@ package health
 
 import (
 	"context"
+	"expvar"
 	"net/http"
 	"strconv"
 	"time"
@@ -18,6 +19,10 @@ import (
 
 const healthCheckTimeout = 5 * time.Second
 
+// publicMetrics are the expvar variables served by Metrics. The defaults published by expvar
+// (cmdline, memstats) describe the process and stay private.
+var publicMetrics = []string{"retention"}
+
 // Handler handles application health and readiness check HTTP endpoints.
 type Handler struct {
 	appCtx *app.Context
@@ -40,6 +45,35 @@ func (h *Handler) Check(c *gin.Context) {
 	httpx.OK(c, http.StatusOK, ToResponse("healthy"))
 }
 
+// Metrics godoc
+//
+//	@Summary		Get metrics
+//	@Description	Get the counters of background jobs since the process started, e.g. purged records under "retention"
+//	@Tags			Health
+//	@Produce		json
+//	@Success		200	{object}	map[string]any
+//	@Router			/health/metrics [get]
+func (h *Handler) Metrics(c *gin.Context) {
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	// expvar values render themselves as JSON
+	_, _ = c.Writer.WriteString("{")
+	first := true
+	for _, name := range publicMetrics {
+		v := expvar.Get(name)
+		if v == nil {
+			continue
+		}
+		if !first {
+			_, _ = c.Writer.WriteString(",")
+		}
+		first = false
+		_, _ = c.Writer.WriteString(strconv.Quote(name) + ":" + v.String())
+	}
+	_, _ = c.Writer.WriteString("}")
+}
+
 // Readiness checks if the service is ready to accept traffic
 //
 //	@Summary		Check readiness
@@ -0,0 +1,2 @@
+// Package identities links accounts at external OpenID Connect providers to users.
+package identities
@@ -0,0 +1,19 @@
+package identities
+
+import "github.com/mrhpn/go-rest-api/internal/apperror"
+
+var (
+	// errIdentityNotFound indicates that no user is linked to the external identity.
+	errIdentityNotFound = apperror.New(
+		apperror.NotFound,
+		"IDENTITY_NOT_FOUND",
+		"external identity is not linked to any user",
+	)
+
+	// errIdentityLinked indicates that the external identity already belongs to a user.
+	errIdentityLinked = apperror.New(
+		apperror.Conflict,
+		"IDENTITY_ALREADY_LINKED",
+		"external identity is already linked to a user",
+	)
+)
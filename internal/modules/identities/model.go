@@ -0,0 +1,21 @@
+package identities
+
+import (
+	"github.com/mrhpn/go-rest-api/internal/model"
+)
+
+// Identity represents the db model for an account at an external identity provider linked to a user.
+// Subject is the provider's stable user id, the email is only kept for reference since it may change.
+type Identity struct {
+	model.Base
+
+	UserID   string `gorm:"type:char(26);not null;index"`
+	Provider string `gorm:"type:varchar(50);not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject  string `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email    string `gorm:"type:varchar(255)"`
+}
+
+// TableName specifies the table name for the Identity model
+func (Identity) TableName() string {
+	return "user_identities"
+}
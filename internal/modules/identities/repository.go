@@ -0,0 +1,57 @@
+package identities
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	repo "github.com/mrhpn/go-rest-api/internal/repository"
+)
+
+type Repository struct {
+	repo.Base
+}
+
+// NewRepository constructs an identities Repository backed by a GORM database.
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		Base: repo.Base{
+			DBInstance: db,
+		},
+	}
+}
+
+func (r *Repository) Create(ctx context.Context, identity *Identity) error {
+	err := r.DB(ctx).Create(identity).Error
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to create user identity",
+			err,
+		)
+	}
+	return nil
+}
+
+// FindByProviderSubject returns the identity with the given provider user id.
+func (r *Repository) FindByProviderSubject(ctx context.Context, provider, subject string) (*Identity, error) {
+	var identity Identity
+	err := r.DB(ctx).First(&identity, "provider = ? AND subject = ?", provider, subject).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errIdentityNotFound
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find user identity",
+			err,
+		)
+	}
+
+	return &identity, nil
+}
@@ -0,0 +1,65 @@
+package identities
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mrhpn/go-rest-api/internal/oidc"
+)
+
+// Service defines the business logic for linking external identities to users.
+type Service interface {
+	// Find returns the link of an external identity. An unlinked identity is a NotFound error.
+	Find(ctx context.Context, provider, subject string) (*Identity, error)
+
+	// Link links an external identity to a user. An identity can only belong to one user.
+	Link(ctx context.Context, userID string, identity *oidc.Identity) (*Identity, error)
+}
+
+// identityRepository interface defines the methods required for identity persistence.
+type identityRepository interface {
+	Create(ctx context.Context, identity *Identity) error
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*Identity, error)
+}
+
+type service struct {
+	repo identityRepository
+}
+
+// NewService constructs an identities Service with the provided repository.
+func NewService(repo identityRepository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Find(ctx context.Context, provider, subject string) (*Identity, error) {
+	return s.repo.FindByProviderSubject(ctx, provider, subject)
+}
+
+func (s *service) Link(ctx context.Context, userID string, identity *oidc.Identity) (*Identity, error) {
+	_, err := s.repo.FindByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return nil, errIdentityLinked
+	}
+	if !errors.Is(err, errIdentityNotFound) {
+		return nil, err
+	}
+
+	link := &Identity{
+		UserID:   userID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+	if err = s.repo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Str("provider", identity.Provider).
+		Msg("external identity linked")
+
+	return link, nil
+}
@@ -46,6 +46,24 @@ var (
 		"failed to upload file to storage",
 	)
 
+	errFileNotFound = apperror.New(
+		apperror.NotFound,
+		"FILE_NOT_FOUND",
+		"file not found",
+	)
+
+	errReadFromStorage = apperror.New(
+		apperror.Internal,
+		"STORAGE_READ_ERROR",
+		"failed to read file from storage",
+	)
+
+	errDeleteFromStorage = apperror.New(
+		apperror.Internal,
+		"STORAGE_DELETE_ERROR",
+		"failed to delete file from storage",
+	)
+
 	errStorageHealthCheck = apperror.New(
 		apperror.Internal,
 		"STORAGE_HEALTH_CHECK_ERROR",
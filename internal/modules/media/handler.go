@@ -8,24 +8,34 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
 
 	"github.com/mrhpn/go-rest-api/internal/httpx"
+	"github.com/mrhpn/go-rest-api/internal/security"
 )
 
 type mediaService interface {
 	Upload(ctx context.Context, file *multipart.FileHeader, subDir fileCategory) (string, error)
+	Delete(ctx context.Context, path string) error
+}
+
+// avatarSetter attaches an uploaded profile picture to a user.
+type avatarSetter interface {
+	SetAvatar(ctx context.Context, userID, url string) error
 }
 
 // Handler handles media-related HTTP endpoints such as uploads, retrieval, and media management operations.
 type Handler struct {
 	service  mediaService
+	avatars  avatarSetter
 	policies map[fileType]filePolicy
 }
 
-// NewHandler constructs a media Handler with its required service dependency.
-func NewHandler(service mediaService) *Handler {
+// NewHandler constructs a media Handler with its required service dependencies.
+func NewHandler(service mediaService, avatars avatarSetter) *Handler {
 	return &Handler{
 		service:  service,
+		avatars:  avatars,
 		policies: getDefaultPolicies(),
 	}
 }
@@ -33,65 +43,77 @@ func NewHandler(service mediaService) *Handler {
 // UploadProfilePicture godoc
 //
 //	@Summary		Upload profile picture
-//	@Description	Upload an image file to be used as a profile picture. Max 5MB.
+//	@Description	Upload an image file and make it the current user's avatar. The previous picture is deleted. Max 5MB.
 //	@Tags			Media
 //	@Accept			multipart/form-data
 //	@Produce		json
 //	@Param			file	formData	file	true	"Image file (jpg, jpeg, png)"
 //	@Success		201		{object}	httpx.SuccessResponse{data=media.Response}
 //	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
 //	@Security		BearerAuth
 //	@Router			/media/upload/profile [post]
 func (h *Handler) UploadProfilePicture(c *gin.Context) {
-	h.handleUpload(c, fileCategoryProfile, fileTypeImage)
+	claims, ok := security.ClaimsFromContext(httpx.ReqCtx(c))
+	if !ok {
+		httpx.FailWithError(c, security.ErrUnauthorized)
+		return
+	}
+
+	url, err := h.upload(c, fileCategoryProfile, fileTypeImage)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	// the upload is only kept if it became the avatar, otherwise nothing would reference it
+	if err = h.avatars.SetAvatar(httpx.ReqCtx(c), claims.UserID, url); err != nil {
+		if delErr := h.service.Delete(httpx.ReqCtx(c), url); delErr != nil {
+			log.Ctx(httpx.ReqCtx(c)).Error().Err(delErr).Str("path", url).Msg("failed to delete unused upload")
+		}
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusCreated, ToResponse(url))
 }
 
-func (h *Handler) handleUpload(c *gin.Context, subDir fileCategory, fileType fileType) {
+// upload validates the file of the request against the policy of its type and stores it
+func (h *Handler) upload(c *gin.Context, subDir fileCategory, fileType fileType) (string, error) {
 	// 1. get policy for type
 	policy, exists := h.policies[fileType]
 	if !exists {
-		httpx.FailWithError(c, errInvalidFileType)
-		return
+		return "", errInvalidFileType
 	}
 
 	// 2. early check for file too large error
 	if c.Request.ContentLength > policy.MaxSize {
-		httpx.FailWithError(c, errFileTooLarge)
-		return
+		return "", errFileTooLarge
 	}
 
 	// 3. parse file
 	file, err := c.FormFile("file")
 	if err != nil {
-		httpx.FailWithError(c, errNoFileUploaded)
-		return
+		return "", errNoFileUploaded
 	}
 
 	// 4. validate size - check for empty or invalid size
 	if file.Size <= 0 {
-		httpx.FailWithError(c, errFileEmpty)
-		return
+		return "", errFileEmpty
 	}
 
 	// 5. validate maximum size
 	if file.Size > policy.MaxSize {
-		httpx.FailWithError(c, errFileTooLarge)
-		return
+		return "", errFileTooLarge
 	}
 
 	// 6. validate extension
 	ext := strings.ToLower(filepath.Ext(file.Filename))
 	if !policy.AllowedExtensions[ext] {
-		httpx.FailWithError(c, errInvalidFile)
-		return
+		return "", errInvalidFile
 	}
 
 	// 7. upload
-	url, err := h.service.Upload(httpx.ReqCtx(c), file, subDir)
-	if err != nil {
-		httpx.FailWithError(c, err)
-		return
-	}
-
-	httpx.OK(c, http.StatusCreated, ToResponse(url))
+	return h.service.Upload(httpx.ReqCtx(c), file, subDir)
 }
@@ -8,6 +8,10 @@ type fileCategory string // fileCategory represents profile, thumbnail, etc
 const (
 	fileCategoryProfile   fileCategory = "profile"
 	fileCategoryThumbnail fileCategory = "thumbnail"
+
+	// FileCategoryExport holds personal data export archives. The MinIO bucket policy denies anonymous
+	// reads of them, so they are only streamed to their owner through the api.
+	FileCategoryExport fileCategory = "exports"
 )
 
 const (
@@ -2,6 +2,7 @@ package media
 
 import (
 	"context"
+	"io"
 	"mime/multipart"
 )
 
@@ -10,6 +11,16 @@ type Service interface {
 	// Upload stores a file under the given category and returns the publicly accessible object path or identifier.
 	Upload(ctx context.Context, file *multipart.FileHeader, subDir fileCategory) (string, error)
 
+	// Store saves content generated by the app itself (e.g. data export archives) under the given category
+	// and returns its object path. Unlike uploads, the content is stored as is.
+	Store(ctx context.Context, content io.Reader, size int64, subDir fileCategory, ext, contentType string) (string, error)
+
+	// Open returns the content of a file by the path Upload or Store returned for it. The caller closes it.
+	Open(ctx context.Context, path string) (io.ReadCloser, error)
+
+	// Delete removes a file by the path Upload returned for it. Deleting a missing file is not an error.
+	Delete(ctx context.Context, path string) error
+
 	// HealthCheck verifies that the underlying storage service is reachable and operational.
 	HealthCheck(ctx context.Context) error // Check if storage service is healthy
 }
@@ -7,6 +7,7 @@ import (
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
@@ -81,19 +82,77 @@ func (s *localService) Upload(_ context.Context, file *multipart.FileHeader, sub
 	objectName := fmt.Sprintf("%s/%s%s", subDir, uuid.New().String(), ext)
 
 	// 5. create directories and store the file
+	if err = s.write(objectName, reader); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("/%s", objectName), nil
+}
+
+// Store writes generated content to disk and returns the relative path.
+func (s *localService) Store(_ context.Context, content io.Reader, _ int64, subDir fileCategory, ext, _ string) (string, error) {
+	objectName := fmt.Sprintf("%s/%s%s", subDir, uuid.New().String(), ext)
+	if err := s.write(objectName, content); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/%s", objectName), nil
+}
+
+// Open opens a file stored under the base path.
+func (s *localService) Open(_ context.Context, path string) (io.ReadCloser, error) {
+	storagePath, err := s.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(storagePath) //nolint:gosec // resolve keeps the path within the base path
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errFileNotFound
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			errReadFromStorage.Code,
+			errReadFromStorage.Message,
+			err,
+		)
+	}
+	return f, nil
+}
+
+// Delete removes a file stored under the base path.
+func (s *localService) Delete(_ context.Context, path string) error {
+	storagePath, err := s.resolve(path)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(storagePath); err != nil && !os.IsNotExist(err) {
+		return apperror.Wrap(
+			apperror.Internal,
+			errDeleteFromStorage.Code,
+			errDeleteFromStorage.Message,
+			err,
+		)
+	}
+	return nil
+}
+
+// write creates the directories of the object and stores its content
+func (s *localService) write(objectName string, content io.Reader) error {
 	storagePath := filepath.Join(s.basePath, objectName)
-	if mkErr := os.MkdirAll(filepath.Dir(storagePath), 0750); mkErr != nil {
-		return "", apperror.Wrap(
+	if err := os.MkdirAll(filepath.Dir(storagePath), 0750); err != nil {
+		return apperror.Wrap(
 			apperror.Internal,
 			errUploadToStorage.Code,
 			errUploadToStorage.Message,
-			mkErr,
+			err,
 		)
 	}
 
 	dst, err := os.Create(storagePath)
 	if err != nil {
-		return "", apperror.Wrap(
+		return apperror.Wrap(
 			apperror.Internal,
 			errUploadToStorage.Code,
 			errUploadToStorage.Message,
@@ -102,16 +161,29 @@ func (s *localService) Upload(_ context.Context, file *multipart.FileHeader, sub
 	}
 	defer func() { _ = dst.Close() }()
 
-	if _, copyErr := io.Copy(dst, reader); copyErr != nil {
-		return "", apperror.Wrap(
+	if _, err = io.Copy(dst, content); err != nil {
+		return apperror.Wrap(
 			apperror.Internal,
 			errUploadToStorage.Code,
 			errUploadToStorage.Message,
-			copyErr,
+			err,
 		)
 	}
+	return nil
+}
 
-	return fmt.Sprintf("/%s", objectName), nil
+// resolve maps a path returned by Upload or Store to the file on disk. Paths come from the app,
+// but never let one escape the base path
+func (s *localService) resolve(path string) (string, error) {
+	rel := filepath.Clean(strings.TrimPrefix(path, "/"))
+	if rel == "." || filepath.IsAbs(rel) || strings.HasPrefix(rel, "..") {
+		return "", apperror.New(
+			apperror.BadRequest,
+			errInvalidFile.Code,
+			errInvalidFile.Message,
+		)
+	}
+	return filepath.Join(s.basePath, rel), nil
 }
 
 // HealthCheck verifies that the base path exists and is writable.
@@ -6,6 +6,7 @@ import (
 	"io"
 	"mime/multipart"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -105,6 +106,69 @@ func (s *minioService) Upload(ctx context.Context, file *multipart.FileHeader, s
 	return fmt.Sprintf("/%s", objectName), nil
 }
 
+// Store streams generated content to MinIO and returns the path
+func (s *minioService) Store(
+	ctx context.Context,
+	content io.Reader,
+	size int64,
+	subDir fileCategory,
+	ext, contentType string,
+) (string, error) {
+	objectName := fmt.Sprintf("%s/%s%s", subDir, uuid.New().String(), ext)
+
+	_, err := s.client.PutObject(ctx, s.bucketName, objectName, content, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", apperror.Wrap(
+			apperror.Internal,
+			errUploadToStorage.Code,
+			errUploadToStorage.Message,
+			err,
+		)
+	}
+
+	return fmt.Sprintf("/%s", objectName), nil
+}
+
+// Open returns the content of an object in the bucket
+func (s *minioService) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	obj, err := s.client.GetObject(ctx, s.bucketName, strings.TrimPrefix(path, "/"), minio.GetObjectOptions{})
+	if err == nil {
+		// GetObject is lazy, stat to find out whether the object exists
+		_, err = obj.Stat()
+	}
+	if err != nil {
+		if obj != nil {
+			_ = obj.Close()
+		}
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, errFileNotFound
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			errReadFromStorage.Code,
+			errReadFromStorage.Message,
+			err,
+		)
+	}
+	return obj, nil
+}
+
+// Delete removes an object from the bucket
+func (s *minioService) Delete(ctx context.Context, path string) error {
+	err := s.client.RemoveObject(ctx, s.bucketName, strings.TrimPrefix(path, "/"), minio.RemoveObjectOptions{})
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			errDeleteFromStorage.Code,
+			errDeleteFromStorage.Message,
+			err,
+		)
+	}
+	return nil
+}
+
 // HealthCheck verifies that MinIO is accessible and the bucket exists
 func (s *minioService) HealthCheck(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
@@ -43,34 +43,40 @@ func SetupMinio(cfg MinioConfig) (*minio.Client, error) {
 	}
 
 	if !exists {
-		if err = makePublicBucket(ctx, client, cfg.Bucket); err != nil {
-			return nil, err
+		if err = client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket: %w", err)
 		}
 	}
 
+	// applied on every start, so buckets created by earlier versions stop serving exports as well
+	if err = setPublicBucketPolicy(ctx, client, cfg.Bucket); err != nil {
+		return nil, err
+	}
+
 	log.Info().Msg("✅ Storage (MinIO) — Bucket checked successfully")
 
 	return client, nil
 }
 
-// makePublicBucket is a private helper for MinIO-specific policy configurations
-func makePublicBucket(ctx context.Context, client *minio.Client, bucketName string) error {
-	err := client.MakeBucket(ctx, bucketName, minio.MakeBucketOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create bucket: %w", err)
-	}
-
+// setPublicBucketPolicy is a private helper for MinIO-specific policy configurations. Objects are
+// readable anonymously, except for the export archives which may only be streamed through the api.
+func setPublicBucketPolicy(ctx context.Context, client *minio.Client, bucketName string) error {
 	policy := fmt.Sprintf(`{
 		"Version": "2012-10-17",
 		"Statement": [{
 				"Action": ["s3:GetObject"],
 				"Effect":"Allow",
 				"Principal":"*",
-				"Resource":["arn:aws:s3:::%s/*"]
+				"Resource":["arn:aws:s3:::%[1]s/*"]
+			}, {
+				"Action": ["s3:GetObject"],
+				"Effect":"Deny",
+				"Principal":"*",
+				"Resource":["arn:aws:s3:::%[1]s/%[2]s/*"]
 			}]
-		}`, bucketName)
+		}`, bucketName, FileCategoryExport)
 
-	if err = client.SetBucketPolicy(ctx, bucketName, policy); err != nil {
+	if err := client.SetBucketPolicy(ctx, bucketName, policy); err != nil {
 		return fmt.Errorf("failed to set bucket policy: %w", err)
 	}
 	return nil
@@ -0,0 +1,2 @@
+// Package mfa implements TOTP (RFC 6238) second-factor enrollment, verification and hashed one-time recovery codes.
+package mfa
@@ -0,0 +1,26 @@
+package mfa
+
+import "github.com/mrhpn/go-rest-api/internal/apperror"
+
+var (
+	// errMFANotEnrolled indicates that the user has no (confirmed) TOTP factor for the requested operation.
+	errMFANotEnrolled = apperror.New(
+		apperror.BadRequest,
+		"MFA_NOT_ENROLLED",
+		"two-factor authentication is not set up",
+	)
+
+	// errMFAAlreadyEnabled indicates that the user already has a confirmed TOTP factor.
+	errMFAAlreadyEnabled = apperror.New(
+		apperror.Conflict,
+		"MFA_ALREADY_ENABLED",
+		"two-factor authentication is already enabled",
+	)
+
+	// errInvalidMFACode indicates that the TOTP or recovery code is wrong, expired or was already used.
+	errInvalidMFACode = apperror.New(
+		apperror.Unauthorized,
+		"INVALID_MFA_CODE",
+		"invalid two-factor authentication code",
+	)
+)
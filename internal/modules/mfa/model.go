@@ -0,0 +1,47 @@
+package mfa
+
+import (
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/model"
+)
+
+// Factor represents the db model for a user's TOTP factor. It only counts as enabled once confirmed.
+type Factor struct {
+	model.Base
+
+	UserID       string     `gorm:"type:char(26);not null;uniqueIndex"`
+	Secret       string     `gorm:"type:varchar(64);not null"`
+	ConfirmedAt  *time.Time // nil while enrollment is pending
+	LastUsedStep int64      `gorm:"not null;default:0"` // last accepted TOTP time step, prevents replaying a code
+}
+
+// TableName specifies the table name for the Factor model
+func (Factor) TableName() string {
+	return "mfa_factors"
+}
+
+// IsConfirmed reports whether the factor finished enrollment
+func (f *Factor) IsConfirmed() bool {
+	return f.ConfirmedAt != nil
+}
+
+// RecoveryCode represents the db model for a hashed, one-time MFA recovery code.
+type RecoveryCode struct {
+	model.Base
+
+	UserID   string     `gorm:"type:char(26);not null;index"`
+	CodeHash string     `gorm:"type:char(64);not null"`
+	UsedAt   *time.Time // set once the code has been used
+}
+
+// TableName specifies the table name for the RecoveryCode model
+func (RecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+// Enrollment carries what a user needs to register the factor in an authenticator app.
+type Enrollment struct {
+	Secret string
+	URI    string // otpauth:// URI, usually rendered as a QR code
+}
@@ -0,0 +1,174 @@
+package mfa
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	repo "github.com/mrhpn/go-rest-api/internal/repository"
+)
+
+type Repository struct {
+	repo.Base
+}
+
+// NewRepository constructs an mfa Repository backed by a GORM database.
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		Base: repo.Base{
+			DBInstance: db,
+		},
+	}
+}
+
+func (r *Repository) FindFactorByUserID(ctx context.Context, userID string) (*Factor, error) {
+	var factor Factor
+	err := r.DB(ctx).Where("user_id = ?", userID).First(&factor).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errMFANotEnrolled
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find mfa factor",
+			err,
+		)
+	}
+
+	return &factor, nil
+}
+
+// SaveFactor stores a new pending factor, replacing any previous pending one of the user.
+func (r *Repository) SaveFactor(ctx context.Context, factor *Factor) error {
+	err := r.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Where("user_id = ? AND confirmed_at IS NULL", factor.UserID).
+			Delete(&Factor{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(factor).Error
+	})
+
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to save mfa factor",
+			err,
+		)
+	}
+	return nil
+}
+
+// ConfirmFactor enables a pending factor and stores its first set of recovery codes atomically.
+func (r *Repository) ConfirmFactor(ctx context.Context, factor *Factor, step int64, codes []*RecoveryCode) error {
+	err := r.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(factor).Updates(map[string]any{
+			"confirmed_at":   time.Now(),
+			"last_used_step": step,
+		}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, factor.UserID, codes)
+	})
+
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to confirm mfa factor",
+			err,
+		)
+	}
+	return nil
+}
+
+// MarkStepUsed records the TOTP step of an accepted code only if it is newer than the last accepted one.
+// Zero affected rows means the code was already used.
+func (r *Repository) MarkStepUsed(ctx context.Context, id string, step int64) (int64, error) {
+	result := r.DB(ctx).
+		Model(&Factor{}).
+		Where("id = ? AND last_used_step < ?", id, step).
+		Update("last_used_step", step)
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to update mfa factor",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *Repository) ReplaceRecoveryCodes(ctx context.Context, userID string, codes []*RecoveryCode) error {
+	err := r.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codes)
+	})
+
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to replace mfa recovery codes",
+			err,
+		)
+	}
+	return nil
+}
+
+// UseRecoveryCode marks a matching unused recovery code as used. Zero affected rows means no such code.
+func (r *Repository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (int64, error) {
+	result := r.DB(ctx).
+		Model(&RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to use mfa recovery code",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// DeleteByUserID removes the factor and all recovery codes of the user.
+func (r *Repository) DeleteByUserID(ctx context.Context, userID string) error {
+	err := r.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("user_id = ?", userID).Delete(&Factor{}).Error
+	})
+
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to delete mfa factor",
+			err,
+		)
+	}
+	return nil
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID string, codes []*RecoveryCode) error {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(codes).Error
+}
@@ -0,0 +1,2 @@
+// Package organizations manages the customer organizations (tenants) hosted on the deployment and their members.
+package organizations
@@ -0,0 +1,117 @@
+package organizations
+
+import (
+	"github.com/mrhpn/go-rest-api/internal/tenancy"
+	"github.com/mrhpn/go-rest-api/internal/timex"
+)
+
+type IDParam struct {
+	ID string `uri:"id" binding:"required,ulid"`
+}
+
+// MemberParam binds a member of an organization from the url.
+type MemberParam struct {
+	ID     string `uri:"id" binding:"required,ulid"`
+	UserID string `uri:"user_id" binding:"required,ulid"`
+}
+
+// CreateOrganizationRequest constructs organization creation request structure.
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+	Slug string `json:"slug" binding:"required,min=2,max=50,slug"`
+}
+
+// AddMemberRequest constructs the request to add a user to an organization.
+type AddMemberRequest struct {
+	UserID string             `json:"user_id" binding:"required,ulid"`
+	Role   tenancy.MemberRole `json:"role" binding:"required,oneof=owner admin member"`
+}
+
+// ChangeMemberRoleRequest constructs the request to change a member's organization role.
+type ChangeMemberRoleRequest struct {
+	Role tenancy.MemberRole `json:"role" binding:"required,oneof=owner admin member"`
+}
+
+// OrganizationResponse returns necessary data about an organization
+type OrganizationResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	CreatedAt string `json:"created_at"`
+}
+
+// MemberResponse returns necessary data about a member of an organization
+type MemberResponse struct {
+	UserID    string             `json:"user_id"`
+	Role      tenancy.MemberRole `json:"role"`
+	CreatedAt string             `json:"created_at"`
+}
+
+// MembershipResponse returns an organization the current user belongs to along with their role in it
+type MembershipResponse struct {
+	Organization OrganizationResponse `json:"organization"`
+	Role         tenancy.MemberRole   `json:"role"`
+}
+
+// OrganizationTokenResponse returns an access token acting in an organization
+type OrganizationTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+// ToOrganizationResponse converts an Organization model to OrganizationResponse DTO
+func ToOrganizationResponse(org *Organization) OrganizationResponse {
+	return OrganizationResponse{
+		ID:        org.ID,
+		Name:      org.Name,
+		Slug:      org.Slug,
+		CreatedAt: timex.ToAPIDateTimeFormat(org.CreatedAt),
+	}
+}
+
+// ToOrganizationResponseList converts a list of Organization models to OrganizationResponse DTOs
+func ToOrganizationResponseList(orgs []*Organization) []OrganizationResponse {
+	res := make([]OrganizationResponse, len(orgs))
+	for i, org := range orgs {
+		res[i] = ToOrganizationResponse(org)
+	}
+	return res
+}
+
+// ToMemberResponse converts a Member model to MemberResponse DTO
+func ToMemberResponse(member *Member) MemberResponse {
+	return MemberResponse{
+		UserID:    member.UserID,
+		Role:      member.Role,
+		CreatedAt: timex.ToAPIDateTimeFormat(member.CreatedAt),
+	}
+}
+
+// ToMemberResponseList converts a list of Member models to MemberResponse DTOs
+func ToMemberResponseList(members []*Member) []MemberResponse {
+	res := make([]MemberResponse, len(members))
+	for i, member := range members {
+		res[i] = ToMemberResponse(member)
+	}
+	return res
+}
+
+// ToMembershipResponseList converts memberships with their organizations to MembershipResponse DTOs
+func ToMembershipResponseList(members []*Member) []MembershipResponse {
+	res := make([]MembershipResponse, len(members))
+	for i, member := range members {
+		res[i] = MembershipResponse{
+			Organization: ToOrganizationResponse(&member.Organization),
+			Role:         member.Role,
+		}
+	}
+	return res
+}
+
+// ToOrganizationTokenResponse converts an organization token to OrganizationTokenResponse DTO
+func ToOrganizationTokenResponse(token *OrganizationToken) OrganizationTokenResponse {
+	return OrganizationTokenResponse{
+		AccessToken: token.AccessToken,
+		ExpiresAt:   timex.ToAPIDateTimeFormat(token.ExpiresAt),
+	}
+}
@@ -0,0 +1,54 @@
+package organizations
+
+import "github.com/mrhpn/go-rest-api/internal/apperror"
+
+var (
+	// errOrganizationNotFound indicates that a requested organization does not exist.
+	errOrganizationNotFound = apperror.New(
+		apperror.NotFound,
+		"ORGANIZATION_NOT_FOUND",
+		"organization not found",
+	)
+
+	// errSlugExists indicates that another organization already uses the slug.
+	errSlugExists = apperror.New(
+		apperror.Conflict,
+		"ORGANIZATION_SLUG_EXISTS",
+		"organization slug already exists",
+	)
+
+	// errMemberNotFound indicates that the user is not a member of the organization.
+	errMemberNotFound = apperror.New(
+		apperror.NotFound,
+		"ORGANIZATION_MEMBER_NOT_FOUND",
+		"organization member not found",
+	)
+
+	// errMemberExists indicates that the user already belongs to the organization.
+	errMemberExists = apperror.New(
+		apperror.Conflict,
+		"ORGANIZATION_MEMBER_EXISTS",
+		"user is already a member of this organization",
+	)
+
+	// errCannotManageMembers indicates that the caller's organization role does not allow managing members.
+	errCannotManageMembers = apperror.New(
+		apperror.Forbidden,
+		"CANNOT_MANAGE_MEMBERS",
+		"only owners and admins of the organization can manage its members",
+	)
+
+	// errOwnerRequired indicates that only an owner can grant or take away the owner role.
+	errOwnerRequired = apperror.New(
+		apperror.Forbidden,
+		"ORGANIZATION_OWNER_REQUIRED",
+		"only owners can manage owners of the organization",
+	)
+
+	// errLastOwner indicates that the change would leave the organization without an owner.
+	errLastOwner = apperror.New(
+		apperror.Conflict,
+		"LAST_ORGANIZATION_OWNER",
+		"an organization must keep at least one owner",
+	)
+)
@@ -0,0 +1,399 @@
+package organizations
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mrhpn/go-rest-api/internal/httpx"
+	"github.com/mrhpn/go-rest-api/internal/middlewares"
+	"github.com/mrhpn/go-rest-api/internal/pagination"
+	"github.com/mrhpn/go-rest-api/internal/security"
+	"github.com/mrhpn/go-rest-api/internal/tenancy"
+)
+
+// Service defines the business logic for organizations and their members.
+type Service interface {
+	// Create creates an organization with the actor as its first owner.
+	Create(ctx context.Context, actor *security.UserClaims, req CreateOrganizationRequest) (*Organization, error)
+	List(ctx context.Context, opts *pagination.QueryOptions) ([]*Organization, *httpx.PaginationMeta, error)
+	// ListMemberships returns the organizations the user belongs to.
+	ListMemberships(ctx context.Context, userID string) ([]*Member, error)
+	Get(ctx context.Context, actor *security.UserClaims, id string) (*Organization, error)
+	ListMembers(ctx context.Context, actor *security.UserClaims, id string) ([]*Member, error)
+	AddMember(ctx context.Context, actor *security.UserClaims, id string, req AddMemberRequest) (*Member, error)
+	ChangeMemberRole(ctx context.Context, actor *security.UserClaims, id, userID string, role tenancy.MemberRole) (*Member, error)
+	RemoveMember(ctx context.Context, actor *security.UserClaims, id, userID string) error
+	// IssueToken issues an access token for the actor's session that acts in the organization.
+	IssueToken(ctx context.Context, actor *security.UserClaims, id string) (*OrganizationToken, error)
+
+	tenancy.Resolver
+}
+
+// Handler handles organization related HTTP endpoints.
+type Handler struct {
+	service Service
+}
+
+// NewHandler constructs an organizations Handler with its required service dependency.
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Create organization godoc
+//
+//	@Summary		Create organization
+//	@Description	Create an organization. The caller becomes its first owner.
+//	@Tags			Organization
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		organizations.CreateOrganizationRequest	true	"CreateOrganizationRequest"
+//	@Success		201		{object}	organizations.OrganizationResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		409		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/organizations [post]
+func (h *Handler) Create(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req CreateOrganizationRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	org, err := h.service.Create(httpx.ReqCtx(c), actor, req)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusCreated, ToOrganizationResponse(org))
+}
+
+// List organizations godoc
+//
+//	@Summary		List organizations
+//	@Description	List every organization hosted on the deployment
+//	@Tags			Organization
+//	@Accept			json
+//	@Produce		json
+//	@Param			page			query		int		false	"Page number (default: 1)"					default(1)	minimum(1)
+//	@Param			limit			query		int		false	"Items per page (default: 10, max: 100)"		default(10)	minimum(1)
+//	@Param			search			query		string	false	"Search text (case-insensitive)"
+//	@Param			search_columns	query		[]string	false	"Columns to search in (default: all searchable)"
+//	@Param			sort_by			query		string	false	"Field to sort by (name, slug, created_at)"
+//	@Param			order			query		string	false	"Sort order (asc or desc)"					Enums(asc, desc)	default(desc)
+//	@Param			exact_match		query		bool	false	"Use exact match for search (default: false)"
+//	@Success		200				{object}	httpx.SuccessResponse{data=[]organizations.OrganizationResponse,meta=httpx.PaginationMeta}
+//	@Failure		400				{object}	httpx.ErrorResponse
+//	@Failure		401				{object}	httpx.ErrorResponse
+//	@Failure		403				{object}	httpx.ErrorResponse
+//	@Failure		500				{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/organizations [get]
+func (h *Handler) List(c *gin.Context) {
+	var query pagination.QueryList
+	if err := httpx.BindAndValidateQuery(c, &query); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	opts := pagination.NewQueryOptions(
+		&query,
+		pagination.SortSearchPolicy{
+			SortableCols:   []string{"name", "slug", "created_at"},
+			SearchableCols: []string{"name", "slug"},
+		},
+	)
+
+	orgs, meta, err := h.service.List(httpx.ReqCtx(c), opts)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OKWithMeta(c, http.StatusOK, ToOrganizationResponseList(orgs), meta)
+}
+
+// List my organizations godoc
+//
+//	@Summary		List my organizations
+//	@Description	List the organizations the current user belongs to, with their role in each
+//	@Tags			Organization
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	httpx.SuccessResponse{data=[]organizations.MembershipResponse}
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/organizations/my [get]
+func (h *Handler) ListMine(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	memberships, err := h.service.ListMemberships(httpx.ReqCtx(c), user.UserID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, ToMembershipResponseList(memberships))
+}
+
+// Get organization godoc
+//
+//	@Summary		Get organization
+//	@Description	Get an organization the current user belongs to by its ULID
+//	@Tags			Organization
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	organizations.OrganizationResponse
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		403	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/organizations/{id} [get]
+func (h *Handler) Get(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params IDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	org, err := h.service.Get(httpx.ReqCtx(c), actor, params.ID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, ToOrganizationResponse(org))
+}
+
+// Issue organization token godoc
+//
+//	@Summary		Switch organization
+//	@Description	Issue an access token for the current session that acts in the organization when a request doesn't name one. Refreshing the session drops the organization.
+//	@Tags			Organization
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	organizations.OrganizationTokenResponse
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		403	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/organizations/{id}/token [post]
+func (h *Handler) IssueToken(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params IDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	token, err := h.service.IssueToken(httpx.ReqCtx(c), actor, params.ID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, ToOrganizationTokenResponse(token))
+}
+
+// List organization members godoc
+//
+//	@Summary		List organization members
+//	@Description	List the members of an organization the current user belongs to
+//	@Tags			Organization
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	httpx.SuccessResponse{data=[]organizations.MemberResponse}
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		403	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/organizations/{id}/members [get]
+func (h *Handler) ListMembers(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params IDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	members, err := h.service.ListMembers(httpx.ReqCtx(c), actor, params.ID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, ToMemberResponseList(members))
+}
+
+// Add organization member godoc
+//
+//	@Summary		Add organization member
+//	@Description	Add a user to an organization. Requires the owner or admin role in the organization, only owners can add owners.
+//	@Tags			Organization
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"Organization ID"
+//	@Param			request	body		organizations.AddMemberRequest	true	"AddMemberRequest"
+//	@Success		201		{object}	organizations.MemberResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		404		{object}	httpx.ErrorResponse
+//	@Failure		409		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/organizations/{id}/members [post]
+func (h *Handler) AddMember(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params IDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req AddMemberRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	member, err := h.service.AddMember(httpx.ReqCtx(c), actor, params.ID, req)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusCreated, ToMemberResponse(member))
+}
+
+// Change organization member role godoc
+//
+//	@Summary		Change organization member role
+//	@Description	Change the role of a member within an organization. Only owners can grant or take away ownership, and the last owner cannot be demoted.
+//	@Tags			Organization
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string									true	"Organization ID"
+//	@Param			user_id	path		string									true	"User ID"
+//	@Param			request	body		organizations.ChangeMemberRoleRequest	true	"ChangeMemberRoleRequest"
+//	@Success		200		{object}	organizations.MemberResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		404		{object}	httpx.ErrorResponse
+//	@Failure		409		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/organizations/{id}/members/{user_id} [put]
+func (h *Handler) ChangeMemberRole(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params MemberParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req ChangeMemberRoleRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	member, err := h.service.ChangeMemberRole(httpx.ReqCtx(c), actor, params.ID, params.UserID, req.Role)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, ToMemberResponse(member))
+}
+
+// Remove organization member godoc
+//
+//	@Summary		Remove organization member
+//	@Description	Remove a member from an organization, or leave it when user_id is the caller. The last owner cannot be removed.
+//	@Tags			Organization
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	string	true	"Organization ID"
+//	@Param			user_id	path	string	true	"User ID"
+//	@Success		204
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		403	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		409	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/organizations/{id}/members/{user_id} [delete]
+func (h *Handler) RemoveMember(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params MemberParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err = h.service.RemoveMember(httpx.ReqCtx(c), actor, params.ID, params.UserID); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
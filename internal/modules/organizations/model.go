@@ -0,0 +1,43 @@
+package organizations
+
+import (
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/model"
+	"github.com/mrhpn/go-rest-api/internal/tenancy"
+)
+
+// Organization represents the db model for a customer organization (tenant)
+type Organization struct {
+	model.Base
+
+	Name string `gorm:"type:varchar(100);not null"`
+	Slug string `gorm:"type:varchar(50);not null;uniqueIndex"` // used in the organization header and as subdomain
+}
+
+// TableName specifies the table name for the Organization model
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+// Member represents the db model for a user's membership in an organization
+type Member struct {
+	model.Base
+
+	OrganizationID string             `gorm:"type:char(26);not null;uniqueIndex:idx_organization_members_organization_user"`
+	UserID         string             `gorm:"type:char(26);not null;uniqueIndex:idx_organization_members_organization_user;index"`
+	Role           tenancy.MemberRole `gorm:"type:varchar(20);not null;default:'member'"`
+
+	Organization Organization `gorm:"foreignKey:OrganizationID"`
+}
+
+// TableName specifies the table name for the Member model
+func (Member) TableName() string {
+	return "organization_members"
+}
+
+// OrganizationToken is an access token that acts in an organization unless a request names another one
+type OrganizationToken struct {
+	AccessToken string
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,234 @@
+package organizations
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/pagination"
+	repo "github.com/mrhpn/go-rest-api/internal/repository"
+	"github.com/mrhpn/go-rest-api/internal/tenancy"
+)
+
+type Repository struct {
+	repo.Base
+}
+
+// NewRepository constructs an organizations Repository backed by a GORM database.
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		Base: repo.Base{
+			DBInstance: db,
+		},
+	}
+}
+
+func (r *Repository) Create(ctx context.Context, org *Organization) error {
+	err := r.DB(ctx).Create(org).Error
+	if err != nil {
+		// the unique index catches a concurrent create with the same slug
+		if repo.IsUniqueViolation(err) {
+			return errSlugExists
+		}
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to create organization",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id string) (*Organization, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+func (r *Repository) FindBySlug(ctx context.Context, slug string) (*Organization, error) {
+	return r.findOne(ctx, "slug = ?", slug)
+}
+
+func (r *Repository) List(ctx context.Context, opts *pagination.QueryOptions) ([]*Organization, int64, error) {
+	var orgs []*Organization
+	var total int64
+
+	// 1. Get total count
+	err := r.DB(ctx).Model(&Organization{}).
+		Scopes(pagination.SearchScope(opts)).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to count organizations",
+			err,
+		)
+	}
+
+	// 2. Fetch data
+	err = r.DB(ctx).
+		Scopes(pagination.SearchScope(opts), pagination.Paginate(opts)).
+		Find(&orgs).Error
+	if err != nil {
+		return nil, 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find organizations",
+			err,
+		)
+	}
+
+	return orgs, total, nil
+}
+
+// ListByUser returns the memberships of a user with their organizations.
+func (r *Repository) ListByUser(ctx context.Context, userID string) ([]*Member, error) {
+	var members []*Member
+	err := r.DB(ctx).
+		Preload("Organization").
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Find(&members).Error
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to list organization memberships",
+			err,
+		)
+	}
+	return members, nil
+}
+
+func (r *Repository) FindMember(ctx context.Context, organizationID, userID string) (*Member, error) {
+	var member Member
+	err := r.DB(ctx).First(&member, "organization_id = ? AND user_id = ?", organizationID, userID).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errMemberNotFound
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find organization member",
+			err,
+		)
+	}
+
+	return &member, nil
+}
+
+func (r *Repository) ListMembers(ctx context.Context, organizationID string) ([]*Member, error) {
+	var members []*Member
+	err := r.DB(ctx).
+		Where("organization_id = ?", organizationID).
+		Order("created_at").
+		Find(&members).Error
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to list organization members",
+			err,
+		)
+	}
+	return members, nil
+}
+
+// AddMember makes the user a member of the organization with the given role.
+func (r *Repository) AddMember(ctx context.Context, organizationID, userID string, role tenancy.MemberRole) error {
+	member := &Member{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Role:           role,
+	}
+	if err := r.DB(ctx).Create(member).Error; err != nil {
+		// the unique index catches the same user being added concurrently
+		if repo.IsUniqueViolation(err) {
+			return errMemberExists
+		}
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to add organization member",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *Repository) UpdateMemberRole(ctx context.Context, organizationID, userID string, role tenancy.MemberRole) (int64, error) {
+	result := r.DB(ctx).
+		Model(&Member{}).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Update("role", role)
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to update organization member",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// RemoveMember deletes the membership for good, so the user can be added again later.
+func (r *Repository) RemoveMember(ctx context.Context, organizationID, userID string) (int64, error) {
+	result := r.DB(ctx).
+		Unscoped().
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Delete(&Member{})
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to remove organization member",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *Repository) CountOwners(ctx context.Context, organizationID string) (int64, error) {
+	var count int64
+	err := r.DB(ctx).
+		Model(&Member{}).
+		Where("organization_id = ? AND role = ?", organizationID, tenancy.MemberRoleOwner).
+		Count(&count).Error
+	if err != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to count organization owners",
+			err,
+		)
+	}
+	return count, nil
+}
+
+func (r *Repository) findOne(ctx context.Context, query string, arg string) (*Organization, error) {
+	var org Organization
+	err := r.DB(ctx).First(&org, query, arg).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errOrganizationNotFound
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find organization",
+			err,
+		)
+	}
+
+	return &org, nil
+}
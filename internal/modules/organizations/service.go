@@ -0,0 +1,366 @@
+package organizations
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/audit"
+	"github.com/mrhpn/go-rest-api/internal/httpx"
+	"github.com/mrhpn/go-rest-api/internal/modules/users"
+	"github.com/mrhpn/go-rest-api/internal/pagination"
+	"github.com/mrhpn/go-rest-api/internal/security"
+	"github.com/mrhpn/go-rest-api/internal/tenancy"
+)
+
+// organizationRepository interface defines the methods required for organization and membership persistence.
+type organizationRepository interface {
+	Create(ctx context.Context, org *Organization) error
+	FindByID(ctx context.Context, id string) (*Organization, error)
+	FindBySlug(ctx context.Context, slug string) (*Organization, error)
+	List(ctx context.Context, opts *pagination.QueryOptions) ([]*Organization, int64, error)
+	ListByUser(ctx context.Context, userID string) ([]*Member, error)
+	FindMember(ctx context.Context, organizationID, userID string) (*Member, error)
+	ListMembers(ctx context.Context, organizationID string) ([]*Member, error)
+	AddMember(ctx context.Context, organizationID, userID string, role tenancy.MemberRole) error
+	UpdateMemberRole(ctx context.Context, organizationID, userID string, role tenancy.MemberRole) (int64, error)
+	RemoveMember(ctx context.Context, organizationID, userID string) (int64, error)
+	CountOwners(ctx context.Context, organizationID string) (int64, error)
+	Transaction(ctx context.Context, fn func(context.Context) error) error
+}
+
+// userFinder checks that users exist before they are added to an organization.
+type userFinder interface {
+	FindByID(ctx context.Context, id string) (*users.User, error)
+}
+
+// tokenIssuer issues access tokens that act in an organization.
+type tokenIssuer interface {
+	GenerateOrganizationToken(claims *security.UserClaims, organizationID string) (string, time.Time, error)
+}
+
+type service struct {
+	repo       organizationRepository
+	users      userFinder
+	authorizer *security.Authorizer
+	auditor    audit.Recorder
+	tokens     tokenIssuer
+}
+
+// NewService constructs an organizations Service with the provided repository.
+func NewService(
+	repo organizationRepository,
+	users userFinder,
+	authorizer *security.Authorizer,
+	auditor audit.Recorder,
+	tokens tokenIssuer,
+) Service {
+	return &service{
+		repo:       repo,
+		users:      users,
+		authorizer: authorizer,
+		auditor:    auditor,
+		tokens:     tokens,
+	}
+}
+
+func (s *service) Create(ctx context.Context, actor *security.UserClaims, req CreateOrganizationRequest) (*Organization, error) {
+	// 1. slugs identify organizations in headers and subdomains, so they must be unique
+	_, err := s.repo.FindBySlug(ctx, req.Slug)
+	if err == nil {
+		return nil, errSlugExists
+	}
+	if !errors.Is(err, errOrganizationNotFound) {
+		return nil, err
+	}
+
+	// 2. the creator becomes the first owner
+	org := &Organization{
+		Name: req.Name,
+		Slug: req.Slug,
+	}
+	err = s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		if createErr := s.repo.Create(txCtx, org); createErr != nil {
+			return createErr
+		}
+		return s.repo.AddMember(txCtx, org.ID, actor.UserID, tenancy.MemberRoleOwner)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("organization_id", org.ID).
+		Str("slug", org.Slug).
+		Msg("organization created")
+
+	return org, nil
+}
+
+func (s *service) List(ctx context.Context, opts *pagination.QueryOptions) ([]*Organization, *httpx.PaginationMeta, error) {
+	orgs, total, err := s.repo.List(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return orgs, pagination.BuildMeta(opts, total), nil
+}
+
+func (s *service) ListMemberships(ctx context.Context, userID string) ([]*Member, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+func (s *service) Get(ctx context.Context, actor *security.UserClaims, id string) (*Organization, error) {
+	org, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.actorRole(ctx, actor, id); err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+func (s *service) ListMembers(ctx context.Context, actor *security.UserClaims, id string) ([]*Member, error) {
+	if _, err := s.Get(ctx, actor, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListMembers(ctx, id)
+}
+
+func (s *service) AddMember(ctx context.Context, actor *security.UserClaims, id string, req AddMemberRequest) (*Member, error) {
+	// 1. only owners and admins (or platform admins) manage members, only owners hand out ownership
+	actorRole, err := s.managerRole(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Role == tenancy.MemberRoleOwner && actorRole != tenancy.MemberRoleOwner {
+		return nil, errOwnerRequired
+	}
+
+	// 2. the user must exist and not be a member yet
+	if _, err = s.users.FindByID(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	_, err = s.repo.FindMember(ctx, id, req.UserID)
+	if err == nil {
+		return nil, errMemberExists
+	}
+	if !errors.Is(err, errMemberNotFound) {
+		return nil, err
+	}
+
+	if err = s.repo.AddMember(ctx, id, req.UserID, req.Role); err != nil {
+		return nil, err
+	}
+
+	s.recordMemberChange(ctx, audit.ActionMemberAdded, id, map[string]any{
+		"user_id": req.UserID,
+		"role":    req.Role,
+	})
+
+	return s.repo.FindMember(ctx, id, req.UserID)
+}
+
+func (s *service) ChangeMemberRole(
+	ctx context.Context,
+	actor *security.UserClaims,
+	id, userID string,
+	role tenancy.MemberRole,
+) (*Member, error) {
+	actorRole, err := s.managerRole(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.FindMember(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == role {
+		return member, nil
+	}
+
+	// granting or taking away ownership is reserved to owners, and the last owner has to stay
+	if member.Role == tenancy.MemberRoleOwner || role == tenancy.MemberRoleOwner {
+		if actorRole != tenancy.MemberRoleOwner {
+			return nil, errOwnerRequired
+		}
+	}
+	if member.Role == tenancy.MemberRoleOwner {
+		if err = s.ensureAnotherOwner(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	affected, err := s.repo.UpdateMemberRole(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, errMemberNotFound
+	}
+
+	s.recordMemberChange(ctx, audit.ActionMemberRoleChanged, id, map[string]any{
+		"user_id": userID,
+		"from":    member.Role,
+		"to":      role,
+	})
+
+	member.Role = role
+	return member, nil
+}
+
+func (s *service) RemoveMember(ctx context.Context, actor *security.UserClaims, id, userID string) error {
+	// members may always leave, removing others requires managing the organization
+	actorRole := tenancy.MemberRoleMember
+	if actor.UserID != userID {
+		var err error
+		if actorRole, err = s.managerRole(ctx, actor, id); err != nil {
+			return err
+		}
+	}
+
+	member, err := s.repo.FindMember(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role == tenancy.MemberRoleOwner {
+		if actor.UserID != userID && actorRole != tenancy.MemberRoleOwner {
+			return errOwnerRequired
+		}
+		if err = s.ensureAnotherOwner(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	// membership is checked on every tenant-scoped request, so the user loses access right away
+	affected, err := s.repo.RemoveMember(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errMemberNotFound
+	}
+
+	s.recordMemberChange(ctx, audit.ActionMemberRemoved, id, map[string]any{
+		"user_id": userID,
+		"role":    member.Role,
+	})
+
+	return nil
+}
+
+func (s *service) IssueToken(ctx context.Context, actor *security.UserClaims, id string) (*OrganizationToken, error) {
+	if _, err := s.Get(ctx, actor, id); err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := s.tokens.GenerateOrganizationToken(actor, id)
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to generate organization token",
+			err,
+		)
+	}
+
+	return &OrganizationToken{AccessToken: token, ExpiresAt: expiresAt}, nil
+}
+
+func (s *service) Resolve(ctx context.Context, ref string) (*tenancy.Tenant, error) {
+	var (
+		org *Organization
+		err error
+	)
+	if _, parseErr := ulid.ParseStrict(ref); parseErr == nil {
+		org, err = s.repo.FindByID(ctx, ref)
+	} else {
+		org, err = s.repo.FindBySlug(ctx, ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &tenancy.Tenant{ID: org.ID, Slug: org.Slug}, nil
+}
+
+func (s *service) MemberRole(ctx context.Context, tenantID, userID string) (tenancy.MemberRole, error) {
+	member, err := s.repo.FindMember(ctx, tenantID, userID)
+	if errors.Is(err, errMemberNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// actorRole returns the actor's role in the organization. Platform admins who may manage every
+// organization count as owners.
+func (s *service) actorRole(ctx context.Context, actor *security.UserClaims, id string) (tenancy.MemberRole, error) {
+	if s.authorizer.Can(actor, security.PermOrganizationsManage) {
+		return tenancy.MemberRoleOwner, nil
+	}
+
+	role, err := s.MemberRole(ctx, id, actor.UserID)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", tenancy.ErrNotMember
+	}
+	return role, nil
+}
+
+// managerRole returns the actor's role in the organization if it allows managing members
+func (s *service) managerRole(ctx context.Context, actor *security.UserClaims, id string) (tenancy.MemberRole, error) {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return "", err
+	}
+
+	role, err := s.actorRole(ctx, actor, id)
+	if err != nil {
+		return "", err
+	}
+	if !role.CanManageMembers() {
+		return "", errCannotManageMembers
+	}
+	return role, nil
+}
+
+// ensureAnotherOwner refuses changes that would leave the organization without an owner
+func (s *service) ensureAnotherOwner(ctx context.Context, id string) error {
+	owners, err := s.repo.CountOwners(ctx, id)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return errLastOwner
+	}
+	return nil
+}
+
+func (s *service) recordMemberChange(ctx context.Context, action, id string, metadata map[string]any) {
+	if err := s.auditor.Record(ctx, audit.Entry{
+		Action:     action,
+		TargetType: audit.TargetOrganization,
+		TargetID:   id,
+		Metadata:   metadata,
+	}); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("organization_id", id).Msg("failed to record organization member change")
+	}
+
+	log.Ctx(ctx).Info().
+		Str("organization_id", id).
+		Str("action", action).
+		Msg("organization members changed")
+}
@@ -0,0 +1,2 @@
+// Package permissions stores role to permission grants in the database, used when RBAC_SOURCE=database.
+package permissions
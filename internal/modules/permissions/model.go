@@ -0,0 +1,19 @@
+package permissions
+
+import (
+	"github.com/mrhpn/go-rest-api/internal/model"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// RolePermission represents the db model for a permission granted to a role.
+type RolePermission struct {
+	model.Base
+
+	Role       security.Role       `gorm:"type:varchar(32);not null;uniqueIndex:idx_role_permissions_role_permission"`
+	Permission security.Permission `gorm:"type:varchar(64);not null;uniqueIndex:idx_role_permissions_role_permission"`
+}
+
+// TableName specifies the table name for the RolePermission model
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
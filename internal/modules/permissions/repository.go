@@ -0,0 +1,43 @@
+package permissions
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	repo "github.com/mrhpn/go-rest-api/internal/repository"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+type Repository struct {
+	repo.Base
+}
+
+// NewRepository constructs a permissions Repository backed by a GORM database.
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		Base: repo.Base{
+			DBInstance: db,
+		},
+	}
+}
+
+// ListByRole returns the granted permissions grouped by role. Roles without rows are missing from the map.
+func (r *Repository) ListByRole(ctx context.Context) (map[security.Role][]security.Permission, error) {
+	var grants []*RolePermission
+	if err := r.DB(ctx).Order("role, permission").Find(&grants).Error; err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to list role permissions",
+			err,
+		)
+	}
+
+	byRole := make(map[security.Role][]security.Permission)
+	for _, g := range grants {
+		byRole[g.Role] = append(byRole[g.Role], g.Permission)
+	}
+	return byRole, nil
+}
@@ -10,6 +10,11 @@ type IDParam struct {
 	ID string `uri:"id" binding:"required,ulid"`
 }
 
+// VisibilityQuery lets admins opt into seeing every post, drafts of other users included.
+type VisibilityQuery struct {
+	All bool `form:"all"`
+}
+
 type CreatePostRequest struct {
 	Title   string     `json:"title" binding:"required,min=1,max=200"`
 	Content string     `json:"content" binding:"required,min=1"`
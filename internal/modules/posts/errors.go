@@ -16,4 +16,11 @@ var (
 		"UNAUTHORIZED",
 		"unauthorized to modify this resource",
 	)
+
+	// errReadAllForbidden indicates that the caller asked for all posts without the permission to read them.
+	errReadAllForbidden = apperror.New(
+		apperror.Forbidden,
+		"READ_ALL_FORBIDDEN",
+		"you are not allowed to see all posts",
+	)
 )
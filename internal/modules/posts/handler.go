@@ -9,16 +9,19 @@ import (
 	"github.com/mrhpn/go-rest-api/internal/httpx"
 	"github.com/mrhpn/go-rest-api/internal/middlewares"
 	"github.com/mrhpn/go-rest-api/internal/pagination"
+	"github.com/mrhpn/go-rest-api/internal/security"
 )
 
 // PostService defines the business logic for managing posts.
 type PostService interface {
 	Create(ctx context.Context, userID string, req CreatePostRequest) (*Post, error)
-	GetByID(ctx context.Context, id string) (*Post, error)
-	GetByUserID(ctx context.Context, userID string, opts *pagination.QueryOptions) ([]*Post, *httpx.PaginationMeta, error)
-	List(ctx context.Context, opts *pagination.QueryOptions) ([]*Post, *httpx.PaginationMeta, error)
-	Update(ctx context.Context, id string, userID string, req UpdatePostRequest) error
-	Delete(ctx context.Context, id string, userID string) error
+	// GetByID returns a post the viewer can see. Published posts are visible to everyone, drafts to
+	// their author only and archived posts to their author and admins. all shows every post to admins.
+	GetByID(ctx context.Context, id string, viewer *security.UserClaims, all bool) (*Post, error)
+	GetByUserID(ctx context.Context, userID string, viewer *security.UserClaims, opts *pagination.QueryOptions, all bool) ([]*Post, *httpx.PaginationMeta, error)
+	List(ctx context.Context, viewer *security.UserClaims, opts *pagination.QueryOptions, all bool) ([]*Post, *httpx.PaginationMeta, error)
+	Update(ctx context.Context, id string, actor *security.UserClaims, req UpdatePostRequest) (*Post, error)
+	Delete(ctx context.Context, id string, actor *security.UserClaims) error
 }
 
 // Handler handles post-related HTTP endpoints such as post creation, reading, updating, and deletion.
@@ -75,26 +78,40 @@ func (h *Handler) Create(c *gin.Context) {
 // Get post godoc
 //
 //	@Summary		Get post
-//	@Description	Get a post by its ID
+//	@Description	Get a post by its ID. Drafts are only visible to their author, archived posts to their author and admins
 //	@Tags			Post
 //	@Accept			json
 //	@Produce		json
 //	@Param			id	path		string	true	"Post ID"
+//	@Param			all	query		bool	false	"Show the post whatever its status (admins only, default: false)"
 //	@Success		200	{object}	posts.PostResponse
 //	@Failure		400	{object}	httpx.ErrorResponse
 //	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		403	{object}	httpx.ErrorResponse
 //	@Failure		404	{object}	httpx.ErrorResponse
 //	@Failure		500	{object}	httpx.ErrorResponse
 //	@Security		BearerAuth
 //	@Router			/posts/{id} [get]
 func (h *Handler) Get(c *gin.Context) {
+	viewer, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
 	var params IDParam
-	if err := httpx.BindAndValidateURI(c, &params); err != nil {
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
 
-	post, err := h.service.GetByID(httpx.ReqCtx(c), params.ID)
+	var visibility VisibilityQuery
+	if err = httpx.BindAndValidateQuery(c, &visibility); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	post, err := h.service.GetByID(httpx.ReqCtx(c), params.ID, viewer, visibility.All)
 	if err != nil {
 		httpx.FailWithError(c, err)
 		return
@@ -110,7 +127,7 @@ func (h *Handler) Get(c *gin.Context) {
 // List posts godoc
 //
 //	@Summary		List posts
-//	@Description	Get a paginated list of posts with search and sorting
+//	@Description	Get a paginated list of posts with search and sorting. Drafts are only listed for their author, archived posts for their author and admins
 //	@Tags			Post
 //	@Accept			json
 //	@Produce		json
@@ -119,15 +136,29 @@ func (h *Handler) Get(c *gin.Context) {
 //	@Param			search		query		string	false	"Search text (case-insensitive)"
 //	@Param			sort_by		query		string	false	"Field to sort by (title, created_at, updated_at)"
 //	@Param			order		query		string	false	"Sort order (asc or desc)"					Enums(asc, desc)	default(desc)
+//	@Param			all			query		bool	false	"List posts whatever their status (admins only, default: false)"
 //	@Success		200			{object}	httpx.SuccessResponse{data=[]posts.PostResponse,meta=httpx.PaginationMeta}
 //	@Failure		400			{object}	httpx.ErrorResponse
 //	@Failure		401			{object}	httpx.ErrorResponse
+//	@Failure		403			{object}	httpx.ErrorResponse
 //	@Failure		500			{object}	httpx.ErrorResponse
 //	@Security		BearerAuth
 //	@Router			/posts [get]
 func (h *Handler) List(c *gin.Context) {
+	viewer, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
 	var query pagination.QueryList
-	if err := httpx.BindAndValidateQuery(c, &query); err != nil {
+	if err = httpx.BindAndValidateQuery(c, &query); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var visibility VisibilityQuery
+	if err = httpx.BindAndValidateQuery(c, &visibility); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
@@ -140,7 +171,7 @@ func (h *Handler) List(c *gin.Context) {
 		},
 	)
 
-	posts, meta, err := h.service.List(httpx.ReqCtx(c), opts)
+	posts, meta, err := h.service.List(httpx.ReqCtx(c), viewer, opts, visibility.All)
 	if err != nil {
 		httpx.FailWithError(c, err)
 		return
@@ -194,7 +225,7 @@ func (h *Handler) ListMyPosts(c *gin.Context) {
 		},
 	)
 
-	posts, meta, err := h.service.GetByUserID(httpx.ReqCtx(c), user.UserID, opts)
+	posts, meta, err := h.service.GetByUserID(httpx.ReqCtx(c), user.UserID, user, opts, false)
 	if err != nil {
 		httpx.FailWithError(c, err)
 		return
@@ -211,7 +242,7 @@ func (h *Handler) ListMyPosts(c *gin.Context) {
 // Update post godoc
 //
 //	@Summary		Update post
-//	@Description	Update a post by its ID (only the owner can update)
+//	@Description	Update a post by its ID (only the owner or users with the posts:update:any permission)
 //	@Tags			Post
 //	@Accept			json
 //	@Produce		json
@@ -245,13 +276,7 @@ func (h *Handler) Update(c *gin.Context) {
 		return
 	}
 
-	if err = h.service.Update(httpx.ReqCtx(c), params.ID, user.UserID, req); err != nil {
-		httpx.FailWithError(c, err)
-		return
-	}
-
-	// Fetch updated post
-	post, err := h.service.GetByID(httpx.ReqCtx(c), params.ID)
+	post, err := h.service.Update(httpx.ReqCtx(c), params.ID, user, req)
 	if err != nil {
 		httpx.FailWithError(c, err)
 		return
@@ -263,7 +288,7 @@ func (h *Handler) Update(c *gin.Context) {
 // Delete post godoc
 //
 //	@Summary		Delete post
-//	@Description	Delete a post by its ID (only the owner can delete)
+//	@Description	Delete a post by its ID (only the owner or users with the posts:delete:any permission)
 //	@Tags			Post
 //	@Accept			json
 //	@Produce		json
@@ -290,7 +315,7 @@ func (h *Handler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err = h.service.Delete(httpx.ReqCtx(c), params.ID, user.UserID); err != nil {
+	if err = h.service.Delete(httpx.ReqCtx(c), params.ID, user); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
@@ -18,10 +18,11 @@ const (
 type Post struct {
 	model.Base
 
-	UserID  string     `gorm:"column:user_id;type:char(26);not null;index" json:"user_id"`
-	Title   string     `gorm:"not null" json:"title"`
-	Content string     `gorm:"type:text;not null" json:"content"`
-	Status  PostStatus `gorm:"type:varchar(20);not null;default:'draft';index" json:"status"`
+	UserID         string     `gorm:"column:user_id;type:char(26);not null;index" json:"user_id"`
+	OrganizationID *string    `gorm:"type:char(26);index" json:"organization_id"` // nil for posts created outside any organization
+	Title          string     `gorm:"not null" json:"title"`
+	Content        string     `gorm:"type:text;not null" json:"content"`
+	Status         PostStatus `gorm:"type:varchar(20);not null;default:'draft';index" json:"status"`
 
 	User users.User `gorm:"foreignKey:UserID"`
 }
@@ -31,6 +32,14 @@ func (Post) TableName() string {
 	return "posts"
 }
 
+// Visibility decides which posts a viewer can see. Published posts are visible to everyone and
+// authors always see their own posts.
+type Visibility struct {
+	ViewerID string
+	Archived bool // archived posts of other users are visible too
+	All      bool // every post is visible, drafts of other users included
+}
+
 // IsValidPostStatus reports whether the given status is supported by the system.
 func IsValidPostStatus(status PostStatus) bool {
 	switch status {
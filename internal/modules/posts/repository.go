@@ -19,11 +19,26 @@ type Repository struct {
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{
 		Base: repo.Base{
-			DBInstance: db,
+			DBInstance:  db,
+			TenantScope: repo.TenantColumn("organization_id"),
 		},
 	}
 }
 
+// visibilityScope limits a posts query to the posts the viewer can see
+func visibilityScope(v Visibility) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if v.All {
+			return db
+		}
+		statuses := []PostStatus{PostStatusPublished}
+		if v.Archived {
+			statuses = append(statuses, PostStatusArchived)
+		}
+		return db.Where("(posts.status IN ? OR posts.user_id = ?)", statuses, v.ViewerID)
+	}
+}
+
 func (r *Repository) Create(ctx context.Context, post *Post) error {
 	err := r.DB(ctx).Create(post).Error
 	if err != nil {
@@ -37,9 +52,11 @@ func (r *Repository) Create(ctx context.Context, post *Post) error {
 	return nil
 }
 
-func (r *Repository) FindByID(ctx context.Context, id string) (*Post, error) {
+// FindByID finds a post the viewer can see. Hidden posts are reported as not found.
+func (r *Repository) FindByID(ctx context.Context, id string, v Visibility) (*Post, error) {
 	var post Post
 	err := r.DB(ctx).
+		Scopes(visibilityScope(v)).
 		Preload("User").
 		First(&post, "id = ?", id).
 		Error
@@ -59,14 +76,14 @@ func (r *Repository) FindByID(ctx context.Context, id string) (*Post, error) {
 	return &post, nil
 }
 
-func (r *Repository) FindByUserID(ctx context.Context, userID string, opts *pagination.QueryOptions) ([]*Post, int64, error) {
+func (r *Repository) FindByUserID(ctx context.Context, userID string, v Visibility, opts *pagination.QueryOptions) ([]*Post, int64, error) {
 	var posts []*Post
 	var total int64
 
 	// 1. Get total count
 	err := r.DB(ctx).Model(&Post{}).
 		Where("user_id = ?", userID).
-		Scopes(pagination.SearchScope(opts)).
+		Scopes(visibilityScope(v), pagination.SearchScope(opts)).
 		Count(&total).Error
 	if err != nil {
 		return nil, 0, apperror.Wrap(
@@ -81,7 +98,7 @@ func (r *Repository) FindByUserID(ctx context.Context, userID string, opts *pagi
 	err = r.DB(ctx).
 		Preload("User").
 		Where("user_id = ?", userID).
-		Scopes(pagination.Paginate(opts)).
+		Scopes(visibilityScope(v), pagination.Paginate(opts)).
 		Find(&posts).Error
 	if err != nil {
 		return nil, 0, apperror.Wrap(
@@ -95,13 +112,13 @@ func (r *Repository) FindByUserID(ctx context.Context, userID string, opts *pagi
 	return posts, total, nil
 }
 
-func (r *Repository) List(ctx context.Context, opts *pagination.QueryOptions) ([]*Post, int64, error) {
+func (r *Repository) List(ctx context.Context, v Visibility, opts *pagination.QueryOptions) ([]*Post, int64, error) {
 	var posts []*Post
 	var total int64
 
 	// 1. Get total count
 	err := r.DB(ctx).Model(&Post{}).
-		Scopes(pagination.SearchScope(opts)).
+		Scopes(visibilityScope(v), pagination.SearchScope(opts)).
 		Count(&total).Error
 	if err != nil {
 		return nil, 0, apperror.Wrap(
@@ -115,7 +132,7 @@ func (r *Repository) List(ctx context.Context, opts *pagination.QueryOptions) ([
 	// 2. Fetch data
 	err = r.DB(ctx).
 		Preload("User").
-		Scopes(pagination.Paginate(opts)).
+		Scopes(visibilityScope(v), pagination.Paginate(opts)).
 		Find(&posts).Error
 	if err != nil {
 		return nil, 0, apperror.Wrap(
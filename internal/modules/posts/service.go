@@ -8,25 +8,34 @@ import (
 	"github.com/mrhpn/go-rest-api/internal/apperror"
 	"github.com/mrhpn/go-rest-api/internal/httpx"
 	"github.com/mrhpn/go-rest-api/internal/pagination"
+	"github.com/mrhpn/go-rest-api/internal/security"
+	"github.com/mrhpn/go-rest-api/internal/tenancy"
 )
 
 // Repository defines the persistence operations for post entities.
 type postRepository interface {
 	Create(ctx context.Context, post *Post) error
-	FindByID(ctx context.Context, id string) (*Post, error)
-	FindByUserID(ctx context.Context, userID string, opts *pagination.QueryOptions) ([]*Post, int64, error)
-	List(ctx context.Context, opts *pagination.QueryOptions) ([]*Post, int64, error)
+	FindByID(ctx context.Context, id string, v Visibility) (*Post, error)
+	FindByUserID(ctx context.Context, userID string, v Visibility, opts *pagination.QueryOptions) ([]*Post, int64, error)
+	List(ctx context.Context, v Visibility, opts *pagination.QueryOptions) ([]*Post, int64, error)
 	Update(ctx context.Context, id string, updates *Post) error
 	Delete(ctx context.Context, id string) (int64, error)
 }
 
+// authorizer answers object-level permission checks
+type authorizer interface {
+	Can(claims *security.UserClaims, p security.Permission) bool
+	CanAccess(claims *security.UserClaims, ownerID string, p security.Permission) bool
+}
+
 type service struct {
-	repo postRepository
+	repo  postRepository
+	authz authorizer
 }
 
 // NewService constructs a posts Service with the provided repository.
-func NewService(repo postRepository) PostService {
-	return &service{repo: repo}
+func NewService(repo postRepository, authz authorizer) PostService {
+	return &service{repo: repo, authz: authz}
 }
 
 func (s *service) Create(ctx context.Context, userID string, req CreatePostRequest) (*Post, error) {
@@ -48,6 +57,10 @@ func (s *service) Create(ctx context.Context, userID string, req CreatePostReque
 		Content: req.Content,
 		Status:  status,
 	}
+	// posts belong to the organization they were created in
+	if tenant, ok := tenancy.FromContext(ctx); ok {
+		post.OrganizationID = &tenant.ID
+	}
 
 	if err := s.repo.Create(ctx, post); err != nil {
 		return nil, err
@@ -62,12 +75,28 @@ func (s *service) Create(ctx context.Context, userID string, req CreatePostReque
 	return post, nil
 }
 
-func (s *service) GetByID(ctx context.Context, id string) (*Post, error) {
-	return s.repo.FindByID(ctx, id)
+func (s *service) GetByID(ctx context.Context, id string, viewer *security.UserClaims, all bool) (*Post, error) {
+	v, err := s.visibility(viewer, all)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(ctx, id, v)
 }
 
-func (s *service) GetByUserID(ctx context.Context, userID string, opts *pagination.QueryOptions) ([]*Post, *httpx.PaginationMeta, error) {
-	posts, total, err := s.repo.FindByUserID(ctx, userID, opts)
+func (s *service) GetByUserID(
+	ctx context.Context,
+	userID string,
+	viewer *security.UserClaims,
+	opts *pagination.QueryOptions,
+	all bool,
+) ([]*Post, *httpx.PaginationMeta, error) {
+	v, err := s.visibility(viewer, all)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	posts, total, err := s.repo.FindByUserID(ctx, userID, v, opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -75,8 +104,13 @@ func (s *service) GetByUserID(ctx context.Context, userID string, opts *paginati
 	return posts, pagination.BuildMeta(opts, total), nil
 }
 
-func (s *service) List(ctx context.Context, opts *pagination.QueryOptions) ([]*Post, *httpx.PaginationMeta, error) {
-	posts, total, err := s.repo.List(ctx, opts)
+func (s *service) List(ctx context.Context, viewer *security.UserClaims, opts *pagination.QueryOptions, all bool) ([]*Post, *httpx.PaginationMeta, error) {
+	v, err := s.visibility(viewer, all)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	posts, total, err := s.repo.List(ctx, v, opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -84,21 +118,22 @@ func (s *service) List(ctx context.Context, opts *pagination.QueryOptions) ([]*P
 	return posts, pagination.BuildMeta(opts, total), nil
 }
 
-func (s *service) Update(ctx context.Context, id string, userID string, req UpdatePostRequest) error {
+func (s *service) Update(ctx context.Context, id string, actor *security.UserClaims, req UpdatePostRequest) (*Post, error) {
 	// Check if post exists and belongs to user
-	post, err := s.repo.FindByID(ctx, id)
+	v := s.modifiable(actor, security.PermPostsUpdateAny)
+	post, err := s.repo.FindByID(ctx, id, v)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Verify ownership
-	if post.UserID != userID {
-		return errUnauthorized
+	// Verify ownership, moderators may update any post
+	if !s.authz.CanAccess(actor, post.UserID, security.PermPostsUpdateAny) {
+		return nil, errUnauthorized
 	}
 
 	// Validate status if provided
 	if req.Status != "" && !IsValidPostStatus(req.Status) {
-		return errInvalidStatus
+		return nil, errInvalidStatus
 	}
 
 	// Prepare updates
@@ -114,26 +149,27 @@ func (s *service) Update(ctx context.Context, id string, userID string, req Upda
 	}
 
 	if err = s.repo.Update(ctx, id, updates); err != nil {
-		return err
+		return nil, err
 	}
 
 	log.Ctx(ctx).Info().
 		Str("post_id", id).
-		Str("user_id", userID).
+		Str("user_id", actor.UserID).
+		Str("owner_id", post.UserID).
 		Msg("post updated")
 
-	return nil
+	return s.repo.FindByID(ctx, id, v)
 }
 
-func (s *service) Delete(ctx context.Context, id string, userID string) error {
+func (s *service) Delete(ctx context.Context, id string, actor *security.UserClaims) error {
 	// Check if post exists and belongs to user
-	post, err := s.repo.FindByID(ctx, id)
+	post, err := s.repo.FindByID(ctx, id, s.modifiable(actor, security.PermPostsDeleteAny))
 	if err != nil {
 		return err
 	}
 
-	// Verify ownership
-	if post.UserID != userID {
+	// Verify ownership, moderators may delete any post
+	if !s.authz.CanAccess(actor, post.UserID, security.PermPostsDeleteAny) {
 		return errUnauthorized
 	}
 
@@ -148,8 +184,34 @@ func (s *service) Delete(ctx context.Context, id string, userID string) error {
 
 	log.Ctx(ctx).Info().
 		Str("post_id", id).
-		Str("user_id", userID).
+		Str("user_id", actor.UserID).
+		Str("owner_id", post.UserID).
 		Msg("post deleted")
 
 	return nil
 }
+
+// visibility returns the posts the viewer can see. Archived posts of other users need the
+// permission to read any post, and so does the opt-in to see all posts.
+func (s *service) visibility(viewer *security.UserClaims, all bool) (Visibility, error) {
+	readAny := s.authz.Can(viewer, security.PermPostsReadAny)
+	if all && !readAny {
+		return Visibility{}, errReadAllForbidden
+	}
+
+	v := Visibility{Archived: readAny, All: all}
+	if viewer != nil {
+		v.ViewerID = viewer.UserID
+	}
+	return v, nil
+}
+
+// modifiable returns the posts the actor can look up to modify. Whoever may modify any post may
+// find any post, everybody else is limited to the posts they can see.
+func (s *service) modifiable(actor *security.UserClaims, p security.Permission) Visibility {
+	if s.authz.Can(actor, p) {
+		return Visibility{All: true}
+	}
+	v, _ := s.visibility(actor, false)
+	return v
+}
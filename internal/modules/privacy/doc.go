@@ -0,0 +1,2 @@
+// Package privacy exports the personal data of users on request and erases it after a grace period.
+package privacy
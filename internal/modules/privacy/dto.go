@@ -0,0 +1,125 @@
+package privacy
+
+import (
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/modules/posts"
+	"github.com/mrhpn/go-rest-api/internal/modules/users"
+	"github.com/mrhpn/go-rest-api/internal/timex"
+)
+
+type IDParam struct {
+	ID string `uri:"id" binding:"required,ulid"`
+}
+
+// RequestErasureRequest constructs the request of a user to erase their own account.
+type RequestErasureRequest struct {
+	Password string `json:"password" binding:"required"` // current password
+}
+
+// DataExportResponse returns necessary data about a data export
+type DataExportResponse struct {
+	ID          string       `json:"id"`
+	Status      ExportStatus `json:"status"`
+	Size        int64        `json:"size"`         // archive size in bytes, 0 until ready
+	CompletedAt string       `json:"completed_at"` // empty until processed
+	ExpiresAt   string       `json:"expires_at"`   // empty until ready
+	CreatedAt   string       `json:"created_at"`
+}
+
+// ErasureResponse returns necessary data about an erasure request
+type ErasureResponse struct {
+	ID           string        `json:"id"`
+	UserID       string        `json:"user_id"`
+	RequestedBy  string        `json:"requested_by"`
+	Status       ErasureStatus `json:"status"`
+	ScheduledFor string        `json:"scheduled_for"`
+	CreatedAt    string        `json:"created_at"`
+}
+
+// ToDataExportResponse converts a DataExport model to DataExportResponse DTO
+func ToDataExportResponse(export *DataExport) DataExportResponse {
+	return DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		Size:        export.Size,
+		CompletedAt: formatOptionalTime(export.CompletedAt),
+		ExpiresAt:   formatOptionalTime(export.ExpiresAt),
+		CreatedAt:   timex.ToAPIDateTimeFormat(export.CreatedAt),
+	}
+}
+
+// ToDataExportResponseList converts a slice of DataExport models to DataExportResponse DTOs
+func ToDataExportResponseList(exports []*DataExport) []DataExportResponse {
+	responses := make([]DataExportResponse, len(exports))
+	for i, export := range exports {
+		responses[i] = ToDataExportResponse(export)
+	}
+	return responses
+}
+
+// ToErasureResponse converts an ErasureRequest model to ErasureResponse DTO
+func ToErasureResponse(req *ErasureRequest) ErasureResponse {
+	return ErasureResponse{
+		ID:           req.ID,
+		UserID:       req.UserID,
+		RequestedBy:  req.RequestedBy,
+		Status:       req.Status,
+		ScheduledFor: timex.ToAPIDateTimeFormat(req.ScheduledFor),
+		CreatedAt:    timex.ToAPIDateTimeFormat(req.CreatedAt),
+	}
+}
+
+// archivePost is a post as written to posts.json of an export archive
+type archivePost struct {
+	ID             string           `json:"id"`
+	OrganizationID *string          `json:"organization_id"`
+	Title          string           `json:"title"`
+	Content        string           `json:"content"`
+	Status         posts.PostStatus `json:"status"`
+	CreatedAt      string           `json:"created_at"`
+	UpdatedAt      string           `json:"updated_at"`
+	DeletedAt      string           `json:"deleted_at,omitempty"`
+}
+
+// archiveMedia is a stored file as written to media.json of an export archive
+type archiveMedia struct {
+	Kind string `json:"kind"` // e.g. avatar
+	Path string `json:"path"` // path returned by media.Service
+}
+
+// toArchivePosts converts Post models to the content of posts.json
+func toArchivePosts(userPosts []*posts.Post) []archivePost {
+	result := make([]archivePost, len(userPosts))
+	for i, post := range userPosts {
+		result[i] = archivePost{
+			ID:             post.ID,
+			OrganizationID: post.OrganizationID,
+			Title:          post.Title,
+			Content:        post.Content,
+			Status:         post.Status,
+			CreatedAt:      timex.ToAPIDateTimeFormat(post.CreatedAt),
+			UpdatedAt:      timex.ToAPIDateTimeFormat(post.UpdatedAt),
+		}
+		if post.DeletedAt.Valid {
+			result[i].DeletedAt = timex.ToAPIDateTimeFormat(post.DeletedAt.Time)
+		}
+	}
+	return result
+}
+
+// toArchiveMedia lists the files stored for a user in media.json
+func toArchiveMedia(user *users.User) []archiveMedia {
+	result := []archiveMedia{}
+	if user.AvatarURL != "" {
+		result = append(result, archiveMedia{Kind: "avatar", Path: user.AvatarURL})
+	}
+	return result
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return timex.ToAPIDateTimeFormat(*t)
+}
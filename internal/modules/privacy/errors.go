@@ -0,0 +1,54 @@
+package privacy
+
+import "github.com/mrhpn/go-rest-api/internal/apperror"
+
+var (
+	// errExportNotFound indicates that the user has no such data export.
+	errExportNotFound = apperror.New(
+		apperror.NotFound,
+		"DATA_EXPORT_NOT_FOUND",
+		"data export not found",
+	)
+
+	// errExportInProgress indicates that an earlier export of the user is still being prepared.
+	errExportInProgress = apperror.New(
+		apperror.Conflict,
+		"DATA_EXPORT_IN_PROGRESS",
+		"a data export is already being prepared",
+	)
+
+	// errExportNotReady indicates that the archive is not available for download (yet or anymore).
+	errExportNotReady = apperror.New(
+		apperror.Conflict,
+		"DATA_EXPORT_NOT_READY",
+		"data export is not ready for download",
+	)
+
+	// errUserNotFound indicates that the user to erase does not exist.
+	errUserNotFound = apperror.New(
+		apperror.NotFound,
+		"USER_NOT_FOUND",
+		"user not found",
+	)
+
+	// errAlreadyErased indicates that the user's personal data was erased already.
+	errAlreadyErased = apperror.New(
+		apperror.Conflict,
+		"USER_ALREADY_ERASED",
+		"user was already erased",
+	)
+
+	// errErasureNotFound indicates that the user has no pending erasure request.
+	errErasureNotFound = apperror.New(
+		apperror.NotFound,
+		"ERASURE_REQUEST_NOT_FOUND",
+		"no pending erasure request",
+	)
+
+	// errErasureExists indicates that an erasure of the user is already scheduled.
+	errErasureExists = apperror.New(
+		apperror.Conflict,
+		"ERASURE_REQUEST_EXISTS",
+		"an erasure is already scheduled",
+	)
+)
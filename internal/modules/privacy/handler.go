@@ -0,0 +1,333 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mrhpn/go-rest-api/internal/httpx"
+	"github.com/mrhpn/go-rest-api/internal/middlewares"
+	"github.com/mrhpn/go-rest-api/internal/modules/users"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// Service defines the business logic for personal data exports and erasure.
+type Service interface {
+	// RequestExport queues an archive of the user's personal data, built by ProcessExports.
+	RequestExport(ctx context.Context, userID string) (*DataExport, error)
+	ListExports(ctx context.Context, userID string) ([]*DataExport, error)
+	// OpenExport returns a ready export with its archive. The caller closes the archive.
+	OpenExport(ctx context.Context, userID, id string) (*DataExport, io.ReadCloser, error)
+	// RequestErasure schedules the erasure of the user's own account after the grace period.
+	// The caller has confirmed the user's password.
+	RequestErasure(ctx context.Context, userID string) (*ErasureRequest, error)
+	GetErasure(ctx context.Context, userID string) (*ErasureRequest, error)
+	CancelErasure(ctx context.Context, userID string) error
+	// ScheduleErasure schedules the erasure of another user after the grace period.
+	ScheduleErasure(ctx context.Context, actor *security.UserClaims, userID string) (*ErasureRequest, error)
+	CancelScheduledErasure(ctx context.Context, actor *security.UserClaims, userID string) error
+	// ProcessExports deletes expired archives and builds a batch of pending exports.
+	ProcessExports(ctx context.Context) error
+	// ProcessErasures erases the users of a batch of due erasure requests.
+	ProcessErasures(ctx context.Context) error
+}
+
+// reauthenticator confirms the password of users requesting their own erasure. Wrong passwords
+// count towards the login lockout.
+type reauthenticator interface {
+	Reauthenticate(ctx context.Context, userID, password string) (*users.User, error)
+}
+
+// Handler handles personal data export and erasure HTTP endpoints.
+type Handler struct {
+	service Service
+	reauth  reauthenticator
+}
+
+// NewHandler constructs a privacy Handler with its required dependencies.
+func NewHandler(service Service, reauth reauthenticator) *Handler {
+	return &Handler{service: service, reauth: reauth}
+}
+
+// Request data export godoc
+//
+//	@Summary		Request data export
+//	@Description	Queue an archive (ZIP) of the current user's profile, posts and media references. It is built in the background, poll the export list for its status
+//	@Tags			Privacy
+//	@Accept			json
+//	@Produce		json
+//	@Success		202	{object}	privacy.DataExportResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		409	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me/exports [post]
+func (h *Handler) RequestExport(c *gin.Context) {
+	claims, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	export, err := h.service.RequestExport(httpx.ReqCtx(c), claims.UserID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusAccepted, ToDataExportResponse(export))
+}
+
+// List data exports godoc
+//
+//	@Summary		List data exports
+//	@Description	List the data exports of the current user, newest first
+//	@Tags			Privacy
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	httpx.SuccessResponse{data=[]privacy.DataExportResponse}
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me/exports [get]
+func (h *Handler) ListExports(c *gin.Context) {
+	claims, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	exports, err := h.service.ListExports(httpx.ReqCtx(c), claims.UserID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, ToDataExportResponseList(exports))
+}
+
+// Download data export godoc
+//
+//	@Summary		Download data export
+//	@Description	Download the archive of a ready data export of the current user
+//	@Tags			Privacy
+//	@Produce		application/zip
+//	@Param			id	path	string	true	"Export ID"
+//	@Success		200	{file}	file
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		409	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me/exports/{id}/download [get]
+func (h *Handler) DownloadExport(c *gin.Context) {
+	claims, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params IDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	export, content, err := h.service.OpenExport(httpx.ReqCtx(c), claims.UserID, params.ID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+	defer func() {
+		if closeErr := content.Close(); closeErr != nil {
+			log.Ctx(httpx.ReqCtx(c)).Warn().Err(closeErr).Msg("failed to close data export archive")
+		}
+	}()
+
+	c.DataFromReader(http.StatusOK, export.Size, "application/zip", content, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="data-export-%s.zip"`, export.ID),
+	})
+}
+
+// Request account erasure godoc
+//
+//	@Summary		Request account erasure
+//	@Description	Schedule the erasure of the current user's account and personal data after a grace period. The account stays usable and the request can be cancelled until then
+//	@Tags			Privacy
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		privacy.RequestErasureRequest	true	"RequestErasureRequest"
+//	@Success		202		{object}	privacy.ErasureResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		409		{object}	httpx.ErrorResponse
+//	@Failure		429		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me/erasure [post]
+func (h *Handler) RequestErasure(c *gin.Context) {
+	claims, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req RequestErasureRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	// erasure can't be undone once due, so the account owner has to confirm it
+	if _, err = h.reauth.Reauthenticate(httpx.ReqCtx(c), claims.UserID, req.Password); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	erasure, err := h.service.RequestErasure(httpx.ReqCtx(c), claims.UserID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusAccepted, ToErasureResponse(erasure))
+}
+
+// Get account erasure godoc
+//
+//	@Summary		Get account erasure
+//	@Description	Get the pending erasure request of the current user
+//	@Tags			Privacy
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	privacy.ErasureResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me/erasure [get]
+func (h *Handler) GetErasure(c *gin.Context) {
+	claims, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	erasure, err := h.service.GetErasure(httpx.ReqCtx(c), claims.UserID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, ToErasureResponse(erasure))
+}
+
+// Cancel account erasure godoc
+//
+//	@Summary		Cancel account erasure
+//	@Description	Cancel the pending erasure request of the current user
+//	@Tags			Privacy
+//	@Accept			json
+//	@Produce		json
+//	@Success		204
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me/erasure [delete]
+func (h *Handler) CancelErasure(c *gin.Context) {
+	claims, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err = h.service.CancelErasure(httpx.ReqCtx(c), claims.UserID); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Schedule user erasure godoc
+//
+//	@Summary		Schedule user erasure
+//	@Description	Schedule the erasure of a user's account and personal data by their ULID after a grace period. Deleted users can be erased as well
+//	@Tags			Privacy
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"User ID"
+//	@Success		202	{object}	privacy.ErasureResponse
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		403	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		409	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/{id}/erasure [post]
+func (h *Handler) ScheduleErasure(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params IDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	erasure, err := h.service.ScheduleErasure(httpx.ReqCtx(c), actor, params.ID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusAccepted, ToErasureResponse(erasure))
+}
+
+// Cancel user erasure godoc
+//
+//	@Summary		Cancel user erasure
+//	@Description	Cancel the pending erasure request of a user by their ULID
+//	@Tags			Privacy
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path	string	true	"User ID"
+//	@Success		204
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		403	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/{id}/erasure [delete]
+func (h *Handler) CancelScheduledErasure(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params IDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err = h.service.CancelScheduledErasure(httpx.ReqCtx(c), actor, params.ID); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
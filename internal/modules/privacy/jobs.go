@@ -0,0 +1,25 @@
+package privacy
+
+import (
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/scheduler"
+)
+
+// NewExportJob returns the job that builds pending data exports and deletes expired archives.
+func NewExportJob(service Service, interval time.Duration) scheduler.Job {
+	return scheduler.Job{
+		Name:     "privacy.process_exports",
+		Interval: interval,
+		Run:      service.ProcessExports,
+	}
+}
+
+// NewErasureJob returns the job that erases users whose erasure grace period ended.
+func NewErasureJob(service Service, interval time.Duration) scheduler.Job {
+	return scheduler.Job{
+		Name:     "privacy.process_erasures",
+		Interval: interval,
+		Run:      service.ProcessErasures,
+	}
+}
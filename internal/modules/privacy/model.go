@@ -0,0 +1,60 @@
+package privacy
+
+import (
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/model"
+)
+
+// ExportStatus is the processing state of a data export
+type ExportStatus string
+
+const (
+	ExportStatusPending    ExportStatus = "pending"
+	ExportStatusProcessing ExportStatus = "processing"
+	ExportStatusReady      ExportStatus = "ready"
+	ExportStatusFailed     ExportStatus = "failed"
+	ExportStatusExpired    ExportStatus = "expired" // the archive was deleted after the retention period
+)
+
+// DataExport represents the db model for an archive of a user's personal data
+type DataExport struct {
+	model.Base
+
+	UserID      string       `gorm:"type:char(26);not null;index"`
+	Status      ExportStatus `gorm:"type:varchar(20);not null;default:'pending'"`
+	Path        string       `gorm:"type:text;not null;default:''"` // path of the archive returned by media.Service
+	Size        int64        `gorm:"not null;default:0"`
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time // the archive is deleted afterwards
+}
+
+// TableName specifies the table name for the DataExport model
+func (DataExport) TableName() string {
+	return "data_exports"
+}
+
+// ErasureStatus is the state of an erasure request
+type ErasureStatus string
+
+const (
+	ErasureStatusPending   ErasureStatus = "pending"
+	ErasureStatusCancelled ErasureStatus = "cancelled"
+	ErasureStatusCompleted ErasureStatus = "completed"
+)
+
+// ErasureRequest represents the db model for a scheduled erasure of a user's personal data
+type ErasureRequest struct {
+	model.Base
+
+	UserID       string        `gorm:"type:char(26);not null;index"` // no foreign key, the request outlives the user
+	RequestedBy  string        `gorm:"type:char(26);not null"`       // the user or the admin who scheduled it
+	Status       ErasureStatus `gorm:"type:varchar(20);not null;default:'pending'"`
+	ScheduledFor time.Time     `gorm:"not null"` // end of the grace period
+	CompletedAt  *time.Time
+}
+
+// TableName specifies the table name for the ErasureRequest model
+func (ErasureRequest) TableName() string {
+	return "erasure_requests"
+}
@@ -0,0 +1,437 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/modules/posts"
+	"github.com/mrhpn/go-rest-api/internal/modules/users"
+	repo "github.com/mrhpn/go-rest-api/internal/repository"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// credentialTables hold a user's sessions, credentials and memberships. Anonymized users keep their
+// row, so these are removed explicitly instead of by the foreign key cascade.
+var credentialTables = []string{
+	"refresh_tokens",
+	"user_tokens",
+	"mfa_factors",
+	"mfa_recovery_codes",
+	"api_keys",
+	"user_identities",
+	"organization_members",
+	"data_exports",
+}
+
+type Repository struct {
+	repo.Base
+}
+
+// NewRepository constructs a privacy Repository backed by a GORM database.
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		Base: repo.Base{
+			DBInstance: db,
+		},
+	}
+}
+
+func (r *Repository) CreateExport(ctx context.Context, export *DataExport) error {
+	err := r.DB(ctx).Create(export).Error
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to create data export",
+			err,
+		)
+	}
+	return nil
+}
+
+// CountUnfinishedExports counts the exports of a user that are still waiting or being prepared
+func (r *Repository) CountUnfinishedExports(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.DB(ctx).
+		Model(&DataExport{}).
+		Where("user_id = ? AND status IN ?", userID, []ExportStatus{ExportStatusPending, ExportStatusProcessing}).
+		Count(&count).Error
+
+	if err != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to count data exports",
+			err,
+		)
+	}
+	return count, nil
+}
+
+func (r *Repository) ListExports(ctx context.Context, userID string) ([]*DataExport, error) {
+	var exports []*DataExport
+	err := r.DB(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&exports).Error
+
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to list data exports",
+			err,
+		)
+	}
+	return exports, nil
+}
+
+func (r *Repository) FindExport(ctx context.Context, userID, id string) (*DataExport, error) {
+	var export DataExport
+	err := r.DB(ctx).First(&export, "id = ? AND user_id = ?", id, userID).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errExportNotFound
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find data export",
+			err,
+		)
+	}
+	return &export, nil
+}
+
+// ClaimExport marks the oldest pending export processing and returns it. Exports left processing
+// since staleBefore (e.g. by a crashed instance) are claimed again. Concurrent instances skip rows
+// locked by each other. Returns errExportNotFound when there is nothing to do.
+func (r *Repository) ClaimExport(ctx context.Context, staleBefore time.Time) (*DataExport, error) {
+	var export DataExport
+	err := r.Transaction(ctx, func(txCtx context.Context) error {
+		err := r.DB(txCtx).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND updated_at < ?)", ExportStatusPending, ExportStatusProcessing, staleBefore).
+			Order("created_at").
+			First(&export).Error
+		if err != nil {
+			return err
+		}
+
+		export.Status = ExportStatusProcessing
+		return r.DB(txCtx).Model(&export).Update("status", ExportStatusProcessing).Error
+	})
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errExportNotFound
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to claim data export",
+			err,
+		)
+	}
+	return &export, nil
+}
+
+// CompleteExport marks an export ready for download until expiresAt
+func (r *Repository) CompleteExport(ctx context.Context, id, path string, size int64, expiresAt time.Time) error {
+	return r.updateExport(ctx, id, map[string]any{
+		"status":       ExportStatusReady,
+		"path":         path,
+		"size":         size,
+		"completed_at": time.Now(),
+		"expires_at":   expiresAt,
+	})
+}
+
+func (r *Repository) FailExport(ctx context.Context, id string) error {
+	return r.updateExport(ctx, id, map[string]any{
+		"status":       ExportStatusFailed,
+		"completed_at": time.Now(),
+	})
+}
+
+// ExpireExport marks an export expired once its archive was deleted
+func (r *Repository) ExpireExport(ctx context.Context, id string) error {
+	return r.updateExport(ctx, id, map[string]any{
+		"status": ExportStatusExpired,
+		"path":   "",
+		"size":   0,
+	})
+}
+
+// ListExpiredExports returns up to limit ready exports whose archive expired before now
+func (r *Repository) ListExpiredExports(ctx context.Context, now time.Time, limit int) ([]*DataExport, error) {
+	var exports []*DataExport
+	err := r.DB(ctx).
+		Where("status = ? AND expires_at < ?", ExportStatusReady, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&exports).Error
+
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to list expired data exports",
+			err,
+		)
+	}
+	return exports, nil
+}
+
+// ExportPaths returns the archives stored for a user, which have to be deleted with the user
+func (r *Repository) ExportPaths(ctx context.Context, userID string) ([]string, error) {
+	var paths []string
+	err := r.DB(ctx).
+		Model(&DataExport{}).
+		Where("user_id = ? AND path <> ''", userID).
+		Pluck("path", &paths).Error
+
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to list data export archives",
+			err,
+		)
+	}
+	return paths, nil
+}
+
+// ListPosts returns every post of a user, including deleted ones and across organizations
+func (r *Repository) ListPosts(ctx context.Context, userID string) ([]*posts.Post, error) {
+	var userPosts []*posts.Post
+	err := r.DB(ctx).
+		Unscoped().
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Find(&userPosts).Error
+
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to list posts",
+			err,
+		)
+	}
+	return userPosts, nil
+}
+
+func (r *Repository) CreateErasure(ctx context.Context, req *ErasureRequest) error {
+	err := r.DB(ctx).Create(req).Error
+	if err != nil {
+		// a partial unique index allows one pending request per user
+		if repo.IsUniqueViolation(err) {
+			return errErasureExists
+		}
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to create erasure request",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *Repository) FindPendingErasure(ctx context.Context, userID string) (*ErasureRequest, error) {
+	var req ErasureRequest
+	err := r.DB(ctx).First(&req, "user_id = ? AND status = ?", userID, ErasureStatusPending).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errErasureNotFound
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find erasure request",
+			err,
+		)
+	}
+	return &req, nil
+}
+
+func (r *Repository) CancelErasure(ctx context.Context, userID string) (int64, error) {
+	result := r.DB(ctx).
+		Model(&ErasureRequest{}).
+		Where("user_id = ? AND status = ?", userID, ErasureStatusPending).
+		Update("status", ErasureStatusCancelled)
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to cancel erasure request",
+			result.Error,
+		)
+	}
+	return result.RowsAffected, nil
+}
+
+// ClaimDueErasure locks the oldest pending erasure whose grace period ended before now. The lock is
+// held until the transaction in ctx ends, so it must be called within one. Returns
+// errErasureNotFound when there is nothing to do.
+func (r *Repository) ClaimDueErasure(ctx context.Context, now time.Time) (*ErasureRequest, error) {
+	var req ErasureRequest
+	err := r.DB(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND scheduled_for <= ?", ErasureStatusPending, now).
+		Order("scheduled_for").
+		First(&req).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errErasureNotFound
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to claim erasure request",
+			err,
+		)
+	}
+	return &req, nil
+}
+
+func (r *Repository) CompleteErasure(ctx context.Context, id string) error {
+	err := r.DB(ctx).
+		Model(&ErasureRequest{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":       ErasureStatusCompleted,
+			"completed_at": time.Now(),
+		}).Error
+
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to complete erasure request",
+			err,
+		)
+	}
+	return nil
+}
+
+// FindUser returns a user even if it was deleted, erasure applies to deleted users as well
+func (r *Repository) FindUser(ctx context.Context, id string) (*users.User, error) {
+	var user users.User
+	err := r.DB(ctx).Unscoped().First(&user, "id = ?", id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errUserNotFound
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find user",
+			err,
+		)
+	}
+	return &user, nil
+}
+
+// DeleteUser removes the user row. Its posts, credentials and exports go with it by the foreign key cascade.
+func (r *Repository) DeleteUser(ctx context.Context, id string) error {
+	err := r.DB(ctx).Unscoped().Delete(&users.User{}, "id = ?", id).Error
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to delete user",
+			err,
+		)
+	}
+	return nil
+}
+
+// DeletePosts removes every post of a user, including deleted ones
+func (r *Repository) DeletePosts(ctx context.Context, userID string) error {
+	err := r.DB(ctx).Unscoped().Delete(&posts.Post{}, "user_id = ?", userID).Error
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to delete posts",
+			err,
+		)
+	}
+	return nil
+}
+
+// AnonymizeUser scrubs the personal data of a user and removes its credentials. The row stays as a
+// deleted, blocked tombstone so content kept under it still has an author.
+func (r *Repository) AnonymizeUser(ctx context.Context, id string) error {
+	now := time.Now()
+	err := r.DB(ctx).
+		Unscoped().
+		Model(&users.User{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			// the email stays unique and can't receive mail (.invalid is reserved)
+			"email":         fmt.Sprintf("erased-%s@erased.invalid", strings.ToLower(id)),
+			"password_hash": "",
+			"pending_email": nil,
+			"status":        security.UserStatusBlocked,
+			"display_name":  "",
+			"bio":           "",
+			"avatar_url":    "",
+			"locale":        "",
+			"timezone":      "",
+			"last_login_ip": "",
+			"erased_at":     now,
+			"deleted_at":    now,
+		}).Error
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to anonymize user",
+			err,
+		)
+	}
+
+	for _, table := range credentialTables {
+		if err = r.DB(ctx).Exec("DELETE FROM "+table+" WHERE user_id = ?", id).Error; err != nil {
+			return apperror.Wrap(
+				apperror.Internal,
+				apperror.ErrDatabaseError.Code,
+				"failed to delete "+table+" of user",
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) updateExport(ctx context.Context, id string, fields map[string]any) error {
+	err := r.DB(ctx).
+		Model(&DataExport{}).
+		Where("id = ?", id).
+		Updates(fields).Error
+
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to update data export",
+			err,
+		)
+	}
+	return nil
+}
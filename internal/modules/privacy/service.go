@@ -0,0 +1,428 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/audit"
+	"github.com/mrhpn/go-rest-api/internal/config"
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/modules/media"
+	"github.com/mrhpn/go-rest-api/internal/modules/posts"
+	"github.com/mrhpn/go-rest-api/internal/modules/users"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// privacyRepository interface defines the methods required for export and erasure persistence.
+type privacyRepository interface {
+	CreateExport(ctx context.Context, export *DataExport) error
+	CountUnfinishedExports(ctx context.Context, userID string) (int64, error)
+	ListExports(ctx context.Context, userID string) ([]*DataExport, error)
+	FindExport(ctx context.Context, userID, id string) (*DataExport, error)
+	ClaimExport(ctx context.Context, staleBefore time.Time) (*DataExport, error)
+	CompleteExport(ctx context.Context, id, path string, size int64, expiresAt time.Time) error
+	FailExport(ctx context.Context, id string) error
+	ExpireExport(ctx context.Context, id string) error
+	ListExpiredExports(ctx context.Context, now time.Time, limit int) ([]*DataExport, error)
+	ExportPaths(ctx context.Context, userID string) ([]string, error)
+	ListPosts(ctx context.Context, userID string) ([]*posts.Post, error)
+	CreateErasure(ctx context.Context, req *ErasureRequest) error
+	FindPendingErasure(ctx context.Context, userID string) (*ErasureRequest, error)
+	CancelErasure(ctx context.Context, userID string) (int64, error)
+	ClaimDueErasure(ctx context.Context, now time.Time) (*ErasureRequest, error)
+	CompleteErasure(ctx context.Context, id string) error
+	FindUser(ctx context.Context, id string) (*users.User, error)
+	DeleteUser(ctx context.Context, id string) error
+	DeletePosts(ctx context.Context, userID string) error
+	AnonymizeUser(ctx context.Context, id string) error
+	Transaction(ctx context.Context, fn func(context.Context) error) error
+}
+
+// tokenRevoker revokes the access tokens of erased users.
+type tokenRevoker interface {
+	RevokeUser(ctx context.Context, userID string) error
+}
+
+type service struct {
+	repo    privacyRepository
+	files   media.Service
+	revoker tokenRevoker
+	auditor audit.Recorder
+	cfg     config.PrivacyConfig
+}
+
+// NewService constructs a privacy Service with the provided repository.
+func NewService(
+	repo privacyRepository,
+	files media.Service,
+	revoker tokenRevoker,
+	auditor audit.Recorder,
+	cfg config.PrivacyConfig,
+) Service {
+	return &service{
+		repo:    repo,
+		files:   files,
+		revoker: revoker,
+		auditor: auditor,
+		cfg:     cfg,
+	}
+}
+
+func (s *service) RequestExport(ctx context.Context, userID string) (*DataExport, error) {
+	// archives are built in the background, one at a time per user
+	unfinished, err := s.repo.CountUnfinishedExports(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if unfinished > 0 {
+		return nil, errExportInProgress
+	}
+
+	export := &DataExport{UserID: userID, Status: ExportStatusPending}
+	if err = s.repo.CreateExport(ctx, export); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", userID).Str("export_id", export.ID).Msg("data export requested")
+
+	return export, nil
+}
+
+func (s *service) ListExports(ctx context.Context, userID string) ([]*DataExport, error) {
+	return s.repo.ListExports(ctx, userID)
+}
+
+func (s *service) OpenExport(ctx context.Context, userID, id string) (*DataExport, io.ReadCloser, error) {
+	export, err := s.repo.FindExport(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if export.Status != ExportStatusReady || export.ExpiresAt == nil || !time.Now().Before(*export.ExpiresAt) {
+		return nil, nil, errExportNotReady
+	}
+
+	content, err := s.files.Open(ctx, export.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return export, content, nil
+}
+
+func (s *service) RequestErasure(ctx context.Context, userID string) (*ErasureRequest, error) {
+	if _, err := s.erasableUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.scheduleErasure(ctx, userID, userID)
+}
+
+func (s *service) GetErasure(ctx context.Context, userID string) (*ErasureRequest, error) {
+	return s.repo.FindPendingErasure(ctx, userID)
+}
+
+func (s *service) CancelErasure(ctx context.Context, userID string) error {
+	affected, err := s.repo.CancelErasure(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errErasureNotFound
+	}
+
+	s.recordErasure(ctx, audit.ActionErasureCancelled, userID, nil)
+
+	log.Ctx(ctx).Info().Str("user_id", userID).Msg("erasure cancelled")
+
+	return nil
+}
+
+func (s *service) ScheduleErasure(ctx context.Context, actor *security.UserClaims, userID string) (*ErasureRequest, error) {
+	user, err := s.erasableUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err = users.AuthorizeManagement(actor, user); err != nil {
+		return nil, err
+	}
+
+	return s.scheduleErasure(ctx, userID, actor.UserID)
+}
+
+func (s *service) CancelScheduledErasure(ctx context.Context, actor *security.UserClaims, userID string) error {
+	user, err := s.repo.FindUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err = users.AuthorizeManagement(actor, user); err != nil {
+		return err
+	}
+
+	return s.CancelErasure(ctx, userID)
+}
+
+func (s *service) ProcessExports(ctx context.Context) error {
+	// 1. archives past their retention are deleted first
+	if err := s.expireExports(ctx); err != nil {
+		return err
+	}
+
+	// 2. then a batch of pending exports is built
+	staleBefore := time.Now().Add(-constants.DataExportStaleAfterMinute * time.Minute)
+	for range constants.PrivacyJobBatchSize {
+		export, err := s.repo.ClaimExport(ctx, staleBefore)
+		if errors.Is(err, errExportNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = s.buildExport(ctx, export); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("export_id", export.ID).Msg("failed to build data export")
+			if failErr := s.repo.FailExport(ctx, export.ID); failErr != nil {
+				return failErr
+			}
+		}
+	}
+	return nil
+}
+
+func (s *service) ProcessErasures(ctx context.Context) error {
+	for range constants.PrivacyJobBatchSize {
+		erased, err := s.eraseNext(ctx)
+		if err != nil {
+			return err
+		}
+		if !erased {
+			return nil
+		}
+	}
+	return nil
+}
+
+// scheduleErasure creates a pending erasure that becomes due after the grace period
+func (s *service) scheduleErasure(ctx context.Context, userID, requestedBy string) (*ErasureRequest, error) {
+	req := &ErasureRequest{
+		UserID:       userID,
+		RequestedBy:  requestedBy,
+		Status:       ErasureStatusPending,
+		ScheduledFor: time.Now().AddDate(0, 0, s.cfg.ErasureGraceDays),
+	}
+	if err := s.repo.CreateErasure(ctx, req); err != nil {
+		return nil, err
+	}
+
+	s.recordErasure(ctx, audit.ActionErasureRequested, userID, map[string]any{
+		"requested_by":  requestedBy,
+		"scheduled_for": req.ScheduledFor,
+	})
+
+	log.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Time("scheduled_for", req.ScheduledFor).
+		Msg("erasure scheduled")
+
+	return req, nil
+}
+
+// erasableUser returns the user unless its personal data was erased already
+func (s *service) erasableUser(ctx context.Context, userID string) (*users.User, error) {
+	user, err := s.repo.FindUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ErasedAt != nil {
+		return nil, errAlreadyErased
+	}
+	return user, nil
+}
+
+// buildExport writes the user's profile, posts and media references into a ZIP archive and stores it
+func (s *service) buildExport(ctx context.Context, export *DataExport) error {
+	user, err := s.repo.FindUser(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+	userPosts, err := s.repo.ListPosts(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := []struct {
+		name    string
+		content any
+	}{
+		{"profile.json", users.ToUserActivityResponse(user)},
+		{"posts.json", toArchivePosts(userPosts)},
+		{"media.json", toArchiveMedia(user)},
+	}
+	for _, file := range files {
+		w, createErr := archive.Create(file.name)
+		if createErr != nil {
+			return wrapArchiveError(createErr)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(file.content); err != nil {
+			return wrapArchiveError(err)
+		}
+	}
+	if err = archive.Close(); err != nil {
+		return wrapArchiveError(err)
+	}
+
+	size := int64(buf.Len())
+	path, err := s.files.Store(ctx, &buf, size, media.FileCategoryExport, ".zip", "application/zip")
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().AddDate(0, 0, s.cfg.ExportRetentionDays)
+	if err = s.repo.CompleteExport(ctx, export.ID, path, size, expiresAt); err != nil {
+		// nothing refers to the archive, don't keep it around
+		if delErr := s.files.Delete(ctx, path); delErr != nil {
+			log.Ctx(ctx).Error().Err(delErr).Str("path", path).Msg("failed to delete unused data export archive")
+		}
+		return err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("user_id", export.UserID).
+		Str("export_id", export.ID).
+		Int64("size", size).
+		Msg("data export ready")
+
+	return nil
+}
+
+// expireExports deletes the archives of a batch of exports past their retention
+func (s *service) expireExports(ctx context.Context) error {
+	expired, err := s.repo.ListExpiredExports(ctx, time.Now(), constants.PrivacyJobBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range expired {
+		if err = s.files.Delete(ctx, export.Path); err != nil {
+			return err
+		}
+		if err = s.repo.ExpireExport(ctx, export.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eraseNext erases the user of the oldest due erasure request. It reports false when none is due.
+func (s *service) eraseNext(ctx context.Context) (bool, error) {
+	var (
+		req   *ErasureRequest
+		files []string
+	)
+	err := s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if req, err = s.repo.ClaimDueErasure(txCtx, time.Now()); err != nil {
+			return err
+		}
+
+		// 1. stored files are collected before their references are gone
+		user, err := s.repo.FindUser(txCtx, req.UserID)
+		if errors.Is(err, errUserNotFound) {
+			// deleted by other means in the meantime, there is nothing left to erase
+			return s.repo.CompleteErasure(txCtx, req.ID)
+		}
+		if err != nil {
+			return err
+		}
+		if files, err = s.repo.ExportPaths(txCtx, req.UserID); err != nil {
+			return err
+		}
+		if user.AvatarURL != "" {
+			files = append(files, user.AvatarURL)
+		}
+
+		// 2. the user and the content are erased as configured per data type
+		if err = s.eraseUser(txCtx, req.UserID); err != nil {
+			return err
+		}
+		return s.repo.CompleteErasure(txCtx, req.ID)
+	})
+	if errors.Is(err, errErasureNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// 3. files can't be part of the transaction, a failed delete only leaves an unreferenced file
+	for _, path := range files {
+		if delErr := s.files.Delete(ctx, path); delErr != nil {
+			log.Ctx(ctx).Error().Err(delErr).Str("path", path).Msg("failed to delete file of erased user")
+		}
+	}
+
+	// 4. access tokens already issued would stay valid until they expire
+	if revokeErr := s.revoker.RevokeUser(ctx, req.UserID); revokeErr != nil {
+		log.Ctx(ctx).Error().Err(revokeErr).Str("user_id", req.UserID).Msg("failed to revoke tokens of erased user")
+	}
+
+	s.recordErasure(ctx, audit.ActionUserErased, req.UserID, map[string]any{
+		"requested_by": req.RequestedBy,
+		"profile":      s.cfg.EraseProfile,
+		"posts":        s.cfg.ErasePosts,
+	})
+
+	log.Ctx(ctx).Info().
+		Str("user_id", req.UserID).
+		Str("profile", s.cfg.EraseProfile).
+		Str("posts", s.cfg.ErasePosts).
+		Msg("user erased")
+
+	return true, nil
+}
+
+// eraseUser anonymizes or deletes the user and its posts. Deleting the user also deletes its posts
+// by the foreign key cascade, config validation rejects keeping them in that case.
+func (s *service) eraseUser(ctx context.Context, userID string) error {
+	if s.cfg.EraseProfile == constants.ErasureModeDelete {
+		return s.repo.DeleteUser(ctx, userID)
+	}
+
+	if s.cfg.ErasePosts == constants.ErasureModeDelete {
+		if err := s.repo.DeletePosts(ctx, userID); err != nil {
+			return err
+		}
+	}
+	return s.repo.AnonymizeUser(ctx, userID)
+}
+
+func (s *service) recordErasure(ctx context.Context, action, userID string, metadata map[string]any) {
+	if err := s.auditor.Record(ctx, audit.Entry{
+		Action:     action,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Metadata:   metadata,
+	}); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID).Msg("failed to record erasure")
+	}
+}
+
+// wrapArchiveError reports a failure to write an export archive
+func wrapArchiveError(err error) error {
+	return apperror.Wrap(
+		apperror.Internal,
+		apperror.ErrInternal.Code,
+		"failed to write data export archive",
+		err,
+	)
+}
@@ -0,0 +1,3 @@
+// Package retention removes soft-deleted users and posts for good once they were deleted longer than
+// the configured retention period.
+package retention
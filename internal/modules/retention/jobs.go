@@ -0,0 +1,16 @@
+package retention
+
+import (
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/scheduler"
+)
+
+// NewPurgeJob returns the job that purges records deleted longer than their retention period.
+func NewPurgeJob(service Service, interval time.Duration) scheduler.Job {
+	return scheduler.Job{
+		Name:     "retention.purge_deleted",
+		Interval: interval,
+		Run:      service.Purge,
+	}
+}
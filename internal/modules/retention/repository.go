@@ -0,0 +1,107 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/modules/posts"
+	"github.com/mrhpn/go-rest-api/internal/modules/privacy"
+	"github.com/mrhpn/go-rest-api/internal/modules/users"
+	repo "github.com/mrhpn/go-rest-api/internal/repository"
+)
+
+type Repository struct {
+	repo.Base
+}
+
+// NewRepository constructs a retention Repository backed by a GORM database.
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		Base: repo.Base{
+			DBInstance: db,
+		},
+	}
+}
+
+// PurgeUsers removes up to limit users deleted before deletedBefore, along with everything the
+// foreign keys cascade to, and returns the stored files they referenced. Erased users are kept, they
+// are the anonymized authors of content kept after an erasure. Rows locked by another instance are skipped.
+func (r *Repository) PurgeUsers(ctx context.Context, deletedBefore time.Time, limit int) (int64, []string, error) {
+	var (
+		purged int64
+		files  []string
+	)
+	err := r.Transaction(ctx, func(txCtx context.Context) error {
+		var batch []*users.User
+		err := r.DB(txCtx).
+			Unscoped().
+			Select("id", "avatar_url").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("deleted_at < ? AND erased_at IS NULL", deletedBefore).
+			Order("deleted_at").
+			Limit(limit).
+			Find(&batch).Error
+		if err != nil || len(batch) == 0 {
+			return err
+		}
+
+		ids := make([]string, len(batch))
+		for i, user := range batch {
+			ids[i] = user.ID
+			if user.AvatarURL != "" {
+				files = append(files, user.AvatarURL)
+			}
+		}
+
+		// the rows referring to files are gone with the users, collect the paths first
+		var exports []string
+		err = r.DB(txCtx).
+			Model(&privacy.DataExport{}).
+			Where("user_id IN ? AND path <> ''", ids).
+			Pluck("path", &exports).Error
+		if err != nil {
+			return err
+		}
+		files = append(files, exports...)
+
+		result := r.DB(txCtx).Unscoped().Delete(&users.User{}, "id IN ?", ids)
+		purged = result.RowsAffected
+		return result.Error
+	})
+
+	if err != nil {
+		return 0, nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to purge deleted users",
+			err,
+		)
+	}
+	return purged, files, nil
+}
+
+// PurgePosts removes up to limit posts deleted before deletedBefore
+func (r *Repository) PurgePosts(ctx context.Context, deletedBefore time.Time, limit int) (int64, error) {
+	batch := r.DB(ctx).
+		Unscoped().
+		Model(&posts.Post{}).
+		Select("id").
+		Where("deleted_at < ?", deletedBefore).
+		Order("deleted_at").
+		Limit(limit)
+
+	result := r.DB(ctx).Unscoped().Where("id IN (?)", batch).Delete(&posts.Post{})
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to purge deleted posts",
+			result.Error,
+		)
+	}
+	return result.RowsAffected, nil
+}
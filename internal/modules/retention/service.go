@@ -0,0 +1,120 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mrhpn/go-rest-api/internal/config"
+)
+
+// metrics counts the purged records since the start of the process, published by expvar
+var metrics = expvar.NewMap("retention")
+
+// Service defines the purge of soft-deleted records.
+type Service interface {
+	// Purge removes the users and posts deleted longer than their retention period, in batches.
+	Purge(ctx context.Context) error
+}
+
+// retentionRepository interface defines the methods required to purge records.
+type retentionRepository interface {
+	PurgeUsers(ctx context.Context, deletedBefore time.Time, limit int) (int64, []string, error)
+	PurgePosts(ctx context.Context, deletedBefore time.Time, limit int) (int64, error)
+}
+
+// fileRemover deletes the stored files of purged users.
+type fileRemover interface {
+	Delete(ctx context.Context, path string) error
+}
+
+type service struct {
+	repo  retentionRepository
+	files fileRemover
+	cfg   config.RetentionConfig
+}
+
+// NewService constructs a retention Service with the provided repository.
+func NewService(repo retentionRepository, files fileRemover, cfg config.RetentionConfig) Service {
+	return &service{
+		repo:  repo,
+		files: files,
+		cfg:   cfg,
+	}
+}
+
+func (s *service) Purge(ctx context.Context) error {
+	var errs []error
+
+	var purgedUsers, purgedPosts int64
+	if days := s.cfg.DeletedUsersDays; days > 0 {
+		var err error
+		purgedUsers, err = s.purgeUsers(ctx, time.Now().AddDate(0, 0, -days))
+		errs = append(errs, err)
+	}
+	if days := s.cfg.DeletedPostsDays; days > 0 {
+		var err error
+		purgedPosts, err = s.purgePosts(ctx, time.Now().AddDate(0, 0, -days))
+		errs = append(errs, err)
+	}
+
+	metrics.Add("runs", 1)
+	metrics.Add("users_purged", purgedUsers)
+	metrics.Add("posts_purged", purgedPosts)
+
+	if purgedUsers > 0 || purgedPosts > 0 {
+		log.Ctx(ctx).Info().
+			Int64("users", purgedUsers).
+			Int64("posts", purgedPosts).
+			Msg("deleted records purged")
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		metrics.Add("failures", 1)
+		return err
+	}
+	return nil
+}
+
+// purgeUsers removes batches of users until a batch comes back short
+func (s *service) purgeUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		purged, files, err := s.repo.PurgeUsers(ctx, deletedBefore, s.cfg.BatchSize)
+		total += purged
+		if err != nil {
+			return total, err
+		}
+
+		// the batch is committed already, so a file that fails to delete is only logged
+		for _, path := range files {
+			if delErr := s.files.Delete(ctx, path); delErr != nil {
+				log.Ctx(ctx).Error().Err(delErr).Str("path", path).Msg("failed to delete file of purged user")
+			}
+		}
+
+		if purged < int64(s.cfg.BatchSize) {
+			return total, nil
+		}
+	}
+	return total, ctx.Err()
+}
+
+// purgePosts removes batches of posts until a batch comes back short
+func (s *service) purgePosts(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		purged, err := s.repo.PurgePosts(ctx, deletedBefore, s.cfg.BatchSize)
+		total += purged
+		if err != nil {
+			return total, err
+		}
+		if purged < int64(s.cfg.BatchSize) {
+			return total, nil
+		}
+	}
+	return total, ctx.Err()
+}
@@ -0,0 +1,2 @@
+// Package sessions persists refresh-token families so that refresh tokens can be rotated and revoked server-side.
+package sessions
@@ -0,0 +1,43 @@
+package sessions
+
+import (
+	"github.com/mrhpn/go-rest-api/internal/timex"
+)
+
+// SessionIDParam binds a session id from the url.
+type SessionIDParam struct {
+	SessionID string `uri:"session_id" binding:"required,ulid"`
+}
+
+// SessionResponse returns necessary data about a login session
+type SessionResponse struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+	Current    bool   `json:"current"` // the session the request was made with
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+// ToSessionResponse converts a Session to SessionResponse DTO. currentID is the session of the request, if any.
+func ToSessionResponse(session *Session, currentID string) SessionResponse {
+	return SessionResponse{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		Current:    currentID != "" && session.ID == currentID,
+		CreatedAt:  timex.ToAPIDateTimeFormat(session.CreatedAt),
+		LastUsedAt: timex.ToAPIDateTimeFormat(session.LastUsedAt),
+		ExpiresAt:  timex.ToAPIDateTimeFormat(session.ExpiresAt),
+	}
+}
+
+// ToSessionResponseList converts a list of Sessions to SessionResponse DTOs
+func ToSessionResponseList(sessions []*Session, currentID string) []SessionResponse {
+	res := make([]SessionResponse, len(sessions))
+	for i, session := range sessions {
+		res[i] = ToSessionResponse(session, currentID)
+	}
+	return res
+}
@@ -0,0 +1,26 @@
+package sessions
+
+import "github.com/mrhpn/go-rest-api/internal/apperror"
+
+var (
+	// errRefreshTokenReused indicates that an already rotated refresh token was presented again.
+	errRefreshTokenReused = apperror.New(
+		apperror.Unauthorized,
+		"REFRESH_TOKEN_REUSED",
+		"refresh token has already been used",
+	)
+
+	// errRefreshTokenRevoked indicates that the refresh token belongs to a revoked family.
+	errRefreshTokenRevoked = apperror.New(
+		apperror.Unauthorized,
+		"REFRESH_TOKEN_REVOKED",
+		"refresh token has been revoked",
+	)
+
+	// errSessionNotFound indicates that the session does not exist, has ended or belongs to someone else.
+	errSessionNotFound = apperror.New(
+		apperror.NotFound,
+		"SESSION_NOT_FOUND",
+		"session not found",
+	)
+)
@@ -0,0 +1,50 @@
+package sessions
+
+import (
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/model"
+)
+
+// RefreshToken represents the db model for an issued refresh token.
+// Every token minted from the same login shares a FamilyID, so replaying a rotated token can revoke the whole chain.
+type RefreshToken struct {
+	model.Base
+
+	FamilyID  string     `gorm:"type:char(26);not null;index"`
+	UserID    string     `gorm:"type:char(26);not null;index"`
+	UserAgent string     `gorm:"type:text;not null;default:''"`
+	IPAddress string     `gorm:"type:varchar(45);not null;default:''"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // set once the token has been exchanged for a new pair
+	RevokedAt *time.Time // set when the family is revoked (reuse, logout, etc.)
+}
+
+// TableName specifies the table name for the RefreshToken model
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// Session is a login as seen by the user: the active refresh token family. Its id is the family id,
+// which is also the sid claim of every token issued for it.
+type Session struct {
+	ID         string
+	UserID     string
+	UserAgent  string // of the last refresh
+	IPAddress  string // of the last refresh
+	CreatedAt  time.Time
+	LastUsedAt time.Time // last login or refresh
+	ExpiresAt  time.Time // unless refreshed before
+}
+
+// Device describes the client a refresh token was issued to.
+type Device struct {
+	UserAgent string
+	IPAddress string
+}
+
+// IssuedToken describes a freshly signed refresh token that needs to be persisted.
+type IssuedToken struct {
+	ID        string // jti of the refresh token
+	ExpiresAt time.Time
+}
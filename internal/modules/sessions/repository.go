@@ -0,0 +1,142 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	repo "github.com/mrhpn/go-rest-api/internal/repository"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+type Repository struct {
+	repo.Base
+}
+
+// NewRepository constructs a sessions Repository backed by a GORM database.
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		Base: repo.Base{
+			DBInstance: db,
+		},
+	}
+}
+
+func (r *Repository) Create(ctx context.Context, token *RefreshToken) error {
+	err := r.DB(ctx).Create(token).Error
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to create refresh token",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id string) (*RefreshToken, error) {
+	var token RefreshToken
+	err := r.DB(ctx).First(&token, "id = ?", id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, security.ErrInvalidToken
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find refresh token",
+			err,
+		)
+	}
+
+	return &token, nil
+}
+
+// ListActiveSessions returns the user's sessions whose latest refresh token is still usable, most recently used first.
+// The first token of a family has the family id as its id, so it tells when the session started.
+func (r *Repository) ListActiveSessions(ctx context.Context, userID string) ([]*Session, error) {
+	var sessions []*Session
+	err := r.DB(ctx).
+		Table("refresh_tokens AS t").
+		Select(
+			"t.family_id AS id, t.user_id, t.user_agent, t.ip_address, "+
+				"first.created_at AS created_at, t.created_at AS last_used_at, t.expires_at",
+		).
+		Joins("JOIN refresh_tokens AS first ON first.id = t.family_id").
+		Where("t.user_id = ? AND t.used_at IS NULL AND t.revoked_at IS NULL AND t.expires_at > ?", userID, time.Now()).
+		Where("t.deleted_at IS NULL").
+		Order("t.created_at DESC").
+		Scan(&sessions).Error
+
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to list sessions",
+			err,
+		)
+	}
+
+	return sessions, nil
+}
+
+// MarkUsed flags a token as used only if it is still unused and not revoked.
+// Zero affected rows means another request already consumed the token.
+func (r *Repository) MarkUsed(ctx context.Context, id string) (int64, error) {
+	result := r.DB(ctx).
+		Model(&RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to mark refresh token as used",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *Repository) RevokeFamily(ctx context.Context, familyID string) (int64, error) {
+	result := r.DB(ctx).
+		Model(&RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to revoke refresh token family",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *Repository) RevokeByUserID(ctx context.Context, userID string) (int64, error) {
+	result := r.DB(ctx).
+		Model(&RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to revoke refresh tokens",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
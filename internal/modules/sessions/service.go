@@ -0,0 +1,228 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// Service defines the business logic for persisting, rotating and revoking refresh-token families.
+type Service interface {
+	// Start persists the first refresh token of a new family (i.e. a new login) and returns the family id.
+	Start(ctx context.Context, userID string, token IssuedToken, device Device) (string, error)
+
+	// Rotate marks the presented refresh token as used and persists the token returned by issue in
+	// its family, in one transaction: when issue or persisting fails, the presented token stays usable.
+	// Replaying an already used token revokes its whole family.
+	Rotate(ctx context.Context, tokenID, userID string, device Device, issue IssueFunc) error
+
+	// Revoke revokes the family the given refresh token belongs to.
+	Revoke(ctx context.Context, tokenID, userID string) error
+
+	// RevokeFamily revokes every refresh token in the given family.
+	RevokeFamily(ctx context.Context, familyID string) error
+
+	// RevokeAll revokes every refresh token issued to the user.
+	RevokeAll(ctx context.Context, userID string) error
+
+	// ListActive returns the user's active sessions, most recently used first.
+	ListActive(ctx context.Context, userID string) ([]*Session, error)
+
+	// RevokeSession ends one of the user's active sessions.
+	RevokeSession(ctx context.Context, sessionID, userID string) error
+}
+
+// sessionRepository interface defines the methods required for refresh token persistence.
+type sessionRepository interface {
+	Create(ctx context.Context, token *RefreshToken) error
+	FindByID(ctx context.Context, id string) (*RefreshToken, error)
+	MarkUsed(ctx context.Context, id string) (int64, error)
+	RevokeFamily(ctx context.Context, familyID string) (int64, error)
+	RevokeByUserID(ctx context.Context, userID string) (int64, error)
+	ListActiveSessions(ctx context.Context, userID string) ([]*Session, error)
+	Transaction(ctx context.Context, fn func(context.Context) error) error
+}
+
+// IssueFunc issues the refresh token that replaces current. ctx carries the rotation's transaction.
+type IssueFunc func(ctx context.Context, current *RefreshToken) (IssuedToken, error)
+
+type service struct {
+	repo sessionRepository
+}
+
+// NewService constructs a sessions Service with the provided repository.
+func NewService(repo sessionRepository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Start(ctx context.Context, userID string, token IssuedToken, device Device) (string, error) {
+	// the first token's id doubles as the family id
+	if err := s.persist(ctx, token.ID, userID, token, device); err != nil {
+		return "", err
+	}
+	return token.ID, nil
+}
+
+func (s *service) Rotate(ctx context.Context, tokenID, userID string, device Device, issue IssueFunc) error {
+	var replayed *RefreshToken
+	err := s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		current, err := s.consume(txCtx, tokenID, userID)
+		if errors.Is(err, errRefreshTokenReused) {
+			replayed = current
+		}
+		if err != nil {
+			return err
+		}
+
+		token, err := issue(txCtx, current)
+		if err != nil {
+			return err
+		}
+		return s.persist(txCtx, current.FamilyID, userID, token, device)
+	})
+
+	// the family is revoked after the rollback, a replay must not be undone with it
+	if replayed != nil {
+		return s.handleReuse(ctx, replayed)
+	}
+	return err
+}
+
+// consume validates that the presented refresh token is still usable and marks it as used. A replayed
+// token is returned along with errRefreshTokenReused.
+func (s *service) consume(ctx context.Context, tokenID, userID string) (*RefreshToken, error) {
+	// 1. look up the presented token
+	token, err := s.repo.FindByID(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. the token must belong to the user it was signed for
+	if token.UserID != userID {
+		return nil, security.ErrInvalidToken
+	}
+
+	// 3. the whole family was revoked earlier (logout, reuse, etc.)
+	if token.RevokedAt != nil {
+		return nil, errRefreshTokenRevoked
+	}
+
+	// 4. an already rotated token is being replayed: assume theft
+	if token.UsedAt != nil {
+		return token, errRefreshTokenReused
+	}
+
+	// 5. atomically mark as used; losing the race means a concurrent replay
+	affected, err := s.repo.MarkUsed(ctx, token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return token, errRefreshTokenReused
+	}
+
+	return token, nil
+}
+
+func (s *service) Revoke(ctx context.Context, tokenID, userID string) error {
+	token, err := s.repo.FindByID(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+
+	if token.UserID != userID {
+		return security.ErrInvalidToken
+	}
+
+	return s.RevokeFamily(ctx, token.FamilyID)
+}
+
+func (s *service) RevokeFamily(ctx context.Context, familyID string) error {
+	affected, err := s.repo.RevokeFamily(ctx, familyID)
+	if err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("family_id", familyID).
+		Int64("revoked", affected).
+		Msg("refresh token family revoked")
+
+	return nil
+}
+
+func (s *service) RevokeAll(ctx context.Context, userID string) error {
+	affected, err := s.repo.RevokeByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Int64("revoked", affected).
+		Msg("all refresh tokens revoked")
+
+	return nil
+}
+
+func (s *service) ListActive(ctx context.Context, userID string) ([]*Session, error) {
+	return s.repo.ListActiveSessions(ctx, userID)
+}
+
+func (s *service) RevokeSession(ctx context.Context, sessionID, userID string) error {
+	// the first token of a family carries the family id as its id
+	token, err := s.repo.FindByID(ctx, sessionID)
+	if errors.Is(err, security.ErrInvalidToken) {
+		return errSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if token.UserID != userID || token.FamilyID != sessionID {
+		return errSessionNotFound
+	}
+
+	affected, err := s.repo.RevokeFamily(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errSessionNotFound // ended already
+	}
+
+	log.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Str("session_id", sessionID).
+		Msg("session revoked")
+
+	return nil
+}
+
+func (s *service) persist(ctx context.Context, familyID, userID string, token IssuedToken, device Device) error {
+	refreshToken := &RefreshToken{
+		FamilyID:  familyID,
+		UserID:    userID,
+		UserAgent: device.UserAgent,
+		IPAddress: device.IPAddress,
+		ExpiresAt: token.ExpiresAt,
+	}
+	refreshToken.ID = token.ID
+
+	return s.repo.Create(ctx, refreshToken)
+}
+
+func (s *service) handleReuse(ctx context.Context, token *RefreshToken) error {
+	log.Ctx(ctx).Warn().
+		Str("user_id", token.UserID).
+		Str("family_id", token.FamilyID).
+		Str("token_id", token.ID).
+		Msg("refresh token reuse detected, revoking family")
+
+	if err := s.RevokeFamily(ctx, token.FamilyID); err != nil {
+		return err
+	}
+	return errRefreshTokenReused
+}
@@ -0,0 +1,63 @@
+package users
+
+import (
+	"errors"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// checkBulkTarget reports why a bulk action can't be applied to a user, nil if it can
+func checkBulkTarget(actor *security.UserClaims, action BulkAction, target *User) error {
+	if target == nil {
+		return errUserNotFound
+	}
+	if err := AuthorizeManagement(actor, target); err != nil {
+		return err
+	}
+	if action == BulkActionRestore && target.ErasedAt != nil {
+		return errUserErased
+	}
+	return nil
+}
+
+// bulkRejected reports the users an atomic bulk action failed for, keyed by id
+func bulkRejected(fields map[string]string) error {
+	return &apperror.AppError{
+		Kind:    errBulkRejected.Kind,
+		Code:    errBulkRejected.Code,
+		Message: errBulkRejected.Message,
+		Fields:  fields,
+	}
+}
+
+// bulkFailures returns the messages of the failed users of a bulk action, keyed by id
+func bulkFailures(ids []string, errs []error) map[string]string {
+	fields := make(map[string]string)
+	for i, err := range errs {
+		if err != nil {
+			fields[ids[i]] = bulkItemResult(ids[i], err).Message
+		}
+	}
+	return fields
+}
+
+// bulkItemResult reports the outcome of a bulk action for one user. Unexpected errors are reported
+// without their details, like HTTP responses do.
+func bulkItemResult(id string, err error) BulkItemResult {
+	if err == nil {
+		return BulkItemResult{ID: id, OK: true}
+	}
+
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || !isClientError(err) {
+		return BulkItemResult{ID: id, Code: apperror.ErrInternal.Code, Message: apperror.ErrInternal.Message}
+	}
+	return BulkItemResult{ID: id, Code: appErr.Code, Message: appErr.Message}
+}
+
+// isClientError reports whether err is caused by the request rather than a failure of the server
+func isClientError(err error) bool {
+	var appErr *apperror.AppError
+	return errors.As(err, &appErr) && appErr.Kind != apperror.Internal
+}
@@ -1,6 +1,8 @@
 package users
 
 import (
+	"time"
+
 	"github.com/mrhpn/go-rest-api/internal/security"
 	"github.com/mrhpn/go-rest-api/internal/timex"
 )
@@ -9,12 +11,46 @@ type IDParam struct {
 	ID string `uri:"id" binding:"required,ulid"`
 }
 
+// SessionParam binds a session of a user from the url.
+type SessionParam struct {
+	ID        string `uri:"id" binding:"required,ulid"`
+	SessionID string `uri:"session_id" binding:"required,ulid"`
+}
+
 type CreateUserRequest struct {
 	Email    string        `json:"email" binding:"required,email"`
-	Password string        `json:"password" binding:"required,min=8"`
+	Password string        `json:"password" binding:"required,password"`
 	Role     security.Role `json:"role" binding:"required,oneof=admin employee user"`
 }
 
+// ChangeRoleRequest constructs the request to change a user's role.
+type ChangeRoleRequest struct {
+	Role security.Role `json:"role" binding:"required,oneof=admin employee user"`
+}
+
+// ListFilter narrows the users list down by status and login activity. Zero values don't filter.
+// It's bound from the query of the list or from the body of a bulk action.
+type ListFilter struct {
+	Status          security.UserStatus `json:"status" form:"status" binding:"omitempty,oneof=active inactive blocked pending_verification dormant"`
+	LastLoginAfter  *time.Time          `json:"last_login_after" form:"last_login_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	LastLoginBefore *time.Time          `json:"last_login_before" form:"last_login_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	NeverLoggedIn   bool                `json:"never_logged_in" form:"never_logged_in"`
+	MinFailedLogins int                 `json:"min_failed_logins" form:"min_failed_logins" binding:"omitempty,min=1"`
+}
+
+// filtersActivity reports whether the filter selects users by their login activity
+func (f ListFilter) filtersActivity() bool {
+	return f.LastLoginAfter != nil || f.LastLoginBefore != nil || f.NeverLoggedIn || f.MinFailedLogins > 0
+}
+
+// UpdateProfileRequest constructs the request to update a user's profile. Omitted fields are left unchanged.
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"display_name" binding:"omitempty,max=100"`
+	Bio         *string `json:"bio" binding:"omitempty,max=500"`
+	Locale      *string `json:"locale" binding:"omitempty,max=35,bcp47_language_tag"`
+	Timezone    *string `json:"timezone" binding:"omitempty,max=64,timezone"`
+}
+
 // UserResponse returns necessary data about a user
 type UserResponse struct {
 	ID        string              `json:"id"`
@@ -22,6 +58,25 @@ type UserResponse struct {
 	Role      security.Role       `json:"role"`
 	Status    security.UserStatus `json:"status"`
 	CreatedAt string              `json:"created_at"`
+
+	PendingEmail *string `json:"pending_email,omitempty"` // awaiting confirmation of an email change
+
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	AvatarURL   string `json:"avatar_url"`
+	Locale      string `json:"locale"`
+	Timezone    string `json:"timezone"`
+}
+
+// UserActivityResponse returns a user along with their login activity, which only the user
+// themselves and admins may see
+type UserActivityResponse struct {
+	UserResponse
+
+	LastLoginAt      string `json:"last_login_at"` // empty until the first login
+	LastLoginIP      string `json:"last_login_ip"`
+	LoginCount       int64  `json:"login_count"`
+	FailedLoginCount int    `json:"failed_login_count"`
 }
 
 // ToUserResponse converts a User model to UserResponse DTO
@@ -32,6 +87,30 @@ func ToUserResponse(user *User) UserResponse {
 		Role:      user.Role,
 		Status:    user.Status,
 		CreatedAt: timex.ToAPIDateTimeFormat(user.CreatedAt),
+
+		PendingEmail: user.PendingEmail,
+
+		DisplayName: user.DisplayName,
+		Bio:         user.Bio,
+		AvatarURL:   user.AvatarURL,
+		Locale:      user.Locale,
+		Timezone:    user.Timezone,
+	}
+}
+
+// ToUserActivityResponse converts a User model to UserActivityResponse DTO
+func ToUserActivityResponse(user *User) UserActivityResponse {
+	var lastLoginAt string
+	if user.LastLoginAt != nil {
+		lastLoginAt = timex.ToAPIDateTimeFormat(*user.LastLoginAt)
+	}
+
+	return UserActivityResponse{
+		UserResponse:     ToUserResponse(user),
+		LastLoginAt:      lastLoginAt,
+		LastLoginIP:      user.LastLoginIP,
+		LoginCount:       user.LoginCount,
+		FailedLoginCount: user.FailedLoginCount,
 	}
 }
 
@@ -46,3 +125,62 @@ func ToUserResponseList(users []*User) []UserResponse {
 	}
 	return responses
 }
+
+// ToUserActivityResponseList converts a slice of User models to UserActivityResponse DTOs
+func ToUserActivityResponseList(users []*User) []UserActivityResponse {
+	responses := make([]UserActivityResponse, len(users))
+	for i, user := range users {
+		responses[i] = ToUserActivityResponse(user)
+	}
+	return responses
+}
+
+// ImportQuery constructs the options of a bulk user import.
+type ImportQuery struct {
+	DryRun bool   `form:"dry_run"`                                     // only validate the rows, create nothing
+	Format string `form:"format" binding:"omitempty,oneof=csv ndjson"` // defaults to the file extension
+}
+
+// ImportResult reports the outcome of a bulk user import
+type ImportResult struct {
+	DryRun  bool              `json:"dry_run"`
+	Total   int               `json:"total"`            // rows in the file
+	Created int               `json:"created"`          // 0 unless every row is valid, the import is all or nothing
+	Errors  map[string]string `json:"errors,omitempty"` // keyed by rows.<row number>.<field>
+}
+
+// BulkAction is an action applied to many users at once
+type BulkAction string
+
+// Actions of bulk requests
+const (
+	BulkActionBlock   BulkAction = "block"
+	BulkActionRestore BulkAction = "restore"
+	BulkActionDelete  BulkAction = "delete"
+)
+
+// BulkActionRequest selects the users of a bulk action, either by id or by filter. A filter only
+// selects users the caller may manage, deleted ones when restoring.
+type BulkActionRequest struct {
+	IDs    []string    `json:"ids" binding:"max=500,dive,ulid"`
+	Filter *ListFilter `json:"filter"`
+	Atomic bool        `json:"atomic"` // change all users or none, instead of reporting each one
+}
+
+// BulkActionResult reports the outcome of a bulk action per user
+type BulkActionResult struct {
+	Action    BulkAction       `json:"action"`
+	Atomic    bool             `json:"atomic"`
+	Total     int              `json:"total"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// BulkItemResult reports the outcome of a bulk action for one user
+type BulkItemResult struct {
+	ID      string `json:"id"`
+	OK      bool   `json:"ok"`
+	Code    string `json:"code,omitempty"`    // error code, if it failed
+	Message string `json:"message,omitempty"` // error message, if it failed
+}
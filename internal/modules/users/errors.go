@@ -10,10 +10,144 @@ var (
 		"user not found",
 	)
 
+	// errUserErased indicates that the user's personal data was erased, so the account can't come back.
+	errUserErased = apperror.New(
+		apperror.Conflict,
+		"USER_ERASED",
+		"user was erased and cannot be restored",
+	)
+
 	// errEmailExists indicates that the provided email address is already associated with an existing user.
 	errEmailExists = apperror.New(
 		apperror.Conflict,
 		"EMAIL_EXISTS",
 		"email already exists",
 	)
+
+	// errUserNotPendingVerification indicates that the user's email was already verified (or the account is no longer pending).
+	errUserNotPendingVerification = apperror.New(
+		apperror.Conflict,
+		"USER_NOT_PENDING_VERIFICATION",
+		"user is not pending email verification",
+	)
+
+	// errCannotManageSelf indicates that an admin tried to apply a user management action to their own account.
+	errCannotManageSelf = apperror.New(
+		apperror.Forbidden,
+		"CANNOT_MANAGE_SELF",
+		"you cannot perform this action on your own account",
+	)
+
+	// errTargetRoleNotLower indicates that the target user's role is not strictly below the caller's role.
+	errTargetRoleNotLower = apperror.New(
+		apperror.Forbidden,
+		"TARGET_ROLE_NOT_LOWER",
+		"you can only manage users with a lower role than yours",
+	)
+
+	// errRoleNotAssignable indicates that the caller tried to grant a role that is not strictly below their own.
+	errRoleNotAssignable = apperror.New(
+		apperror.Forbidden,
+		"ROLE_NOT_ASSIGNABLE",
+		"you can only assign roles lower than yours",
+	)
+
+	// errEmailUnchanged indicates that a user asked to change their email to the one they already have.
+	errEmailUnchanged = apperror.New(
+		apperror.BadRequest,
+		"EMAIL_UNCHANGED",
+		"new email must be different from the current one",
+	)
+
+	// errNoPendingEmail indicates that a user confirmed an email change they did not request (or already confirmed).
+	errNoPendingEmail = apperror.New(
+		apperror.Conflict,
+		"NO_PENDING_EMAIL_CHANGE",
+		"there is no pending email change",
+	)
+
+	// errImportFormat indicates that the format of an import file is neither given nor recognizable by its name.
+	errImportFormat = apperror.New(
+		apperror.BadRequest,
+		"IMPORT_FORMAT_UNKNOWN",
+		"import format must be csv or ndjson",
+	)
+
+	// errImportNoFile indicates that an import request has no file.
+	errImportNoFile = apperror.New(
+		apperror.BadRequest,
+		"NO_FILE_UPLOADED",
+		"no import file uploaded",
+	)
+
+	// errImportEmpty indicates that an import file has no rows.
+	errImportEmpty = apperror.New(
+		apperror.BadRequest,
+		"IMPORT_EMPTY",
+		"import file has no rows",
+	)
+
+	// errImportTooLarge indicates that an import file exceeds the row or size limit.
+	errImportTooLarge = apperror.New(
+		apperror.BadRequest,
+		"IMPORT_TOO_LARGE",
+		"import file has too many rows or is too large",
+	)
+
+	// errImportMalformed indicates that a row of an import file can't be parsed.
+	errImportMalformed = apperror.New(
+		apperror.BadRequest,
+		"IMPORT_MALFORMED",
+		"import file is malformed",
+	)
+
+	// errImportInvalid indicates that rows of an import failed validation, so nothing was imported.
+	errImportInvalid = apperror.New(
+		apperror.InvalidInput,
+		"IMPORT_INVALID",
+		"import has invalid rows, no user was created",
+	)
+
+	// errPasswordContainsEmail indicates that a new password contains the email of its account. It
+	// is reported like the binding errors of the password field, which can't see the stored email.
+	errPasswordContainsEmail = apperror.New(
+		apperror.InvalidInput,
+		"INVALID_REQUEST",
+		"invalid request",
+	).WithFields(map[string]string{"password": "password must not contain your email address"})
+
+	// errBulkTargets indicates that a bulk action selects its users by neither or both ids and filter.
+	errBulkTargets = apperror.New(
+		apperror.BadRequest,
+		"BULK_TARGETS_INVALID",
+		"select the users either by ids or by filter",
+	)
+
+	// errBulkFilterEmpty indicates that the filter of a bulk action would select every user.
+	errBulkFilterEmpty = apperror.New(
+		apperror.BadRequest,
+		"BULK_FILTER_EMPTY",
+		"filter must set at least one condition",
+	)
+
+	// errBulkTooMany indicates that a bulk action selects more users than it may change at once.
+	errBulkTooMany = apperror.New(
+		apperror.BadRequest,
+		"BULK_TOO_MANY_TARGETS",
+		"bulk action selects too many users, narrow down the filter",
+	)
+
+	// errBulkRejected indicates that an atomic bulk action failed for some users, so none was changed.
+	errBulkRejected = apperror.New(
+		apperror.Conflict,
+		"BULK_REJECTED",
+		"bulk action failed for some users, no user was changed",
+	)
+
+	// errActivityForbidden indicates that the caller filtered users by login activity without the permission to see it.
+	errActivityForbidden = apperror.New(
+		apperror.Forbidden,
+		"ACTIVITY_FORBIDDEN",
+		"you are not allowed to filter users by login activity",
+	)
 )
@@ -2,34 +2,97 @@ package users
 
 import (
 	"context"
+	"encoding/csv"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
 
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/constants"
 	"github.com/mrhpn/go-rest-api/internal/httpx"
+	"github.com/mrhpn/go-rest-api/internal/middlewares"
+	"github.com/mrhpn/go-rest-api/internal/modules/sessions"
 	"github.com/mrhpn/go-rest-api/internal/pagination"
+	"github.com/mrhpn/go-rest-api/internal/security"
 )
 
 type Service interface {
-	Create(ctx context.Context, req CreateUserRequest) (*User, error)
+	Create(ctx context.Context, actor *security.UserClaims, req CreateUserRequest) (*User, error)
+	Register(ctx context.Context, email, password string) (*User, error)
+	RegisterExternal(ctx context.Context, email string) (*User, error)
 	GetByID(ctx context.Context, id string) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
-	List(ctx context.Context, opts *pagination.QueryOptions) ([]*User, *httpx.PaginationMeta, error)
-	Delete(ctx context.Context, id string) error
-	Restore(ctx context.Context, id string) error
-	Block(ctx context.Context, id string) error
-	Reactivate(ctx context.Context, id string) error
-	Activate(ctx context.Context, id string) (*User, error)
+	List(ctx context.Context, filter ListFilter, opts *pagination.QueryOptions) ([]*User, *httpx.PaginationMeta, error)
+	Delete(ctx context.Context, actor *security.UserClaims, id string) error
+	Restore(ctx context.Context, actor *security.UserClaims, id string) error
+	Block(ctx context.Context, actor *security.UserClaims, id string) error
+	Reactivate(ctx context.Context, actor *security.UserClaims, id string) error
+	Unlock(ctx context.Context, actor *security.UserClaims, id string) error
+	// Bulk applies an action to many users, to all or none of them if the request is atomic.
+	Bulk(ctx context.Context, actor *security.UserClaims, action BulkAction, req BulkActionRequest) (*BulkActionResult, error)
+	ChangeRole(ctx context.Context, actor *security.UserClaims, id string, role security.Role) (*User, error)
+	UpdateProfile(ctx context.Context, id string, req UpdateProfileRequest) (*User, error)
+	Update(ctx context.Context, actor *security.UserClaims, id string, req UpdateProfileRequest) (*User, error)
+	// SetAvatar points the user's avatar to an uploaded picture and deletes the previous one.
+	SetAvatar(ctx context.Context, id, url string) error
+	// ListSessions lists the sessions of the actor or of a user they may manage.
+	ListSessions(ctx context.Context, actor *security.UserClaims, id string) ([]*sessions.Session, error)
+	RevokeSession(ctx context.Context, actor *security.UserClaims, id, sessionID string) error
+	// RecordLogin activates the user after a successful login and updates their login activity.
+	RecordLogin(ctx context.Context, id, ip string) (*User, error)
+	RecordFailedLogin(ctx context.Context, id string) error
+	VerifyEmail(ctx context.Context, id string) error
+	// SetPassword replaces the password of the user, which must not contain the user's email.
+	SetPassword(ctx context.Context, id, password string) error
+	// DiscardPassword replaces the password of the user with a random one nobody knows.
+	DiscardPassword(ctx context.Context, id string) error
+	VerifyPassword(ctx context.Context, user *User, password string) (bool, error)
+	// RequestEmailChange stores a new email for the user, which replaces the current one once ConfirmEmailChange is called.
+	RequestEmailChange(ctx context.Context, id, email string) error
+	ConfirmEmailChange(ctx context.Context, id string) (*User, error)
+	// Import validates the rows of a bulk import and, unless it's a dry run, creates all users or none.
+	Import(ctx context.Context, actor *security.UserClaims, rows []CreateUserRequest, dryRun bool) (*ImportResult, error)
+	// Export calls fn for each user matching the filter, without loading the whole list.
+	Export(ctx context.Context, filter ListFilter, opts *pagination.QueryOptions, fn func(*User) error) error
+}
+
+// listPolicy are the sortable and searchable columns of the users list and export
+var listPolicy = pagination.SortSearchPolicy{
+	SortableCols:   []string{"role", "created_at", "updated_at", "last_login_at", "login_count", "failed_login_count"},
+	SearchableCols: []string{"email"},
+}
+
+// listPolicyWithoutActivity is listPolicy for callers who may not see the login activity of others
+var listPolicyWithoutActivity = pagination.SortSearchPolicy{
+	SortableCols:   []string{"role", "created_at", "updated_at"},
+	SearchableCols: []string{"email"},
+}
+
+// authorizer answers permission checks
+type authorizer interface {
+	Can(claims *security.UserClaims, p security.Permission) bool
 }
 
 // Handler handles user-related HTTP endpoints such as user profile access and account management operations.
 type Handler struct {
 	service Service
+	authz   authorizer
 }
 
-// NewHandler constructs a users Handler with its required service dependency.
-func NewHandler(service Service) *Handler {
-	return &Handler{service: service}
+// NewHandler constructs a users Handler with its required dependencies.
+func NewHandler(service Service, authz authorizer) *Handler {
+	return &Handler{service: service, authz: authz}
+}
+
+// userResponse returns the user with their login activity if the viewer may see it
+func (h *Handler) userResponse(viewer *security.UserClaims, user *User) any {
+	if viewer.UserID == user.ID || h.authz.Can(viewer, security.PermUsersActivity) {
+		return ToUserActivityResponse(user)
+	}
+	return ToUserResponse(user)
 }
 
 // Create user godoc
@@ -47,13 +110,19 @@ func NewHandler(service Service) *Handler {
 //	@Security		BearerAuth
 //	@Router			/users [post]
 func (h *Handler) Create(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
 	var req CreateUserRequest
-	if err := httpx.BindAndValidateJSON(c, &req); err != nil {
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
 
-	user, err := h.service.Create(httpx.ReqCtx(c), req)
+	user, err := h.service.Create(httpx.ReqCtx(c), actor, req)
 	if err != nil {
 		httpx.FailWithError(c, err)
 		return
@@ -62,14 +131,14 @@ func (h *Handler) Create(c *gin.Context) {
 	httpx.OK(
 		c,
 		http.StatusCreated,
-		ToUserResponse(user),
+		h.userResponse(actor, user),
 	)
 }
 
 // Get a user godoc
 //
 //	@Summary		Get user
-//	@Description	Get a user by their ULID
+//	@Description	Get a user by their ULID. Login activity is only returned for users with the users:activity permission
 //	@Tags			User
 //	@Accept			json
 //	@Produce		json
@@ -81,8 +150,14 @@ func (h *Handler) Create(c *gin.Context) {
 //	@Security		BearerAuth
 //	@Router			/users/{id} [get]
 func (h *Handler) Get(c *gin.Context) {
+	viewer, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
 	var params IDParam
-	if err := httpx.BindAndValidateURI(c, &params); err != nil {
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
@@ -96,55 +171,82 @@ func (h *Handler) Get(c *gin.Context) {
 	httpx.OK(
 		c,
 		http.StatusOK,
-		ToUserResponse(user),
+		h.userResponse(viewer, user),
 	)
 }
 
 // List users godoc
 //
 //	@Summary		List users
-//	@Description	Get a paginated list of users with search and sorting
+//	@Description	Get a paginated list of users with search, sorting and filters on status and login activity. Login activity is only returned, filtered and sorted by for users with the users:activity permission
 //	@Tags			User
 //	@Accept			json
 //	@Produce		json
-//	@Param			page			query		int		false	"Page number (default: 1)"					default(1)	minimum(1)
-//	@Param			limit			query		int		false	"Items per page (default: 10, max: 100)"		default(10)	minimum(1)
-//	@Param			search			query		string	false	"Search text (case-insensitive)"
-//	@Param			search_columns	query		[]string	false	"Columns to search in (default: all searchable)"
-//	@Param			sort_by			query		string	false	"Field to sort by (email, role, created_at)"
-//	@Param			order			query		string	false	"Sort order (asc or desc)"					Enums(asc, desc)	default(desc)
-//	@Param			exact_match		query		bool	false	"Use exact match for search (default: false)"
-//	@Success		200				{object}	httpx.SuccessResponse{data=[]users.UserResponse,meta=httpx.PaginationMeta}
-//	@Failure		400				{object}	httpx.ErrorResponse
-//	@Failure		401				{object}	httpx.ErrorResponse
-//	@Failure		500				{object}	httpx.ErrorResponse
+//	@Param			page				query		int		false	"Page number (default: 1)"					default(1)	minimum(1)
+//	@Param			limit				query		int		false	"Items per page (default: 10, max: 100)"		default(10)	minimum(1)
+//	@Param			search				query		string	false	"Search text (case-insensitive)"
+//	@Param			search_columns		query		[]string	false	"Columns to search in (default: all searchable)"
+//	@Param			sort_by				query		string	false	"Field to sort by (role, created_at, updated_at, last_login_at, login_count, failed_login_count)"
+//	@Param			order				query		string	false	"Sort order (asc or desc)"					Enums(asc, desc)	default(desc)
+//	@Param			exact_match			query		bool	false	"Use exact match for search (default: false)"
+//	@Param			status				query		string	false	"Only users with this status"	Enums(active, inactive, blocked, pending_verification, dormant)
+//	@Param			last_login_after	query		string	false	"Only users who last logged in at or after this time (RFC 3339)"
+//	@Param			last_login_before	query		string	false	"Only users who last logged in before this time (RFC 3339)"
+//	@Param			never_logged_in		query		bool	false	"Only users who never logged in"
+//	@Param			min_failed_logins	query		int		false	"Only users with at least this many failed logins since their last login"	minimum(1)
+//	@Success		200					{object}	httpx.SuccessResponse{data=[]users.UserActivityResponse,meta=httpx.PaginationMeta}
+//	@Failure		400					{object}	httpx.ErrorResponse
+//	@Failure		401					{object}	httpx.ErrorResponse
+//	@Failure		403					{object}	httpx.ErrorResponse
+//	@Failure		500					{object}	httpx.ErrorResponse
 //	@Security		BearerAuth
 //	@Router			/users [get]
 func (h *Handler) List(c *gin.Context) {
+	viewer, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
 	var query pagination.QueryList
-	if err := httpx.BindAndValidateQuery(c, &query); err != nil {
+	if err = httpx.BindAndValidateQuery(c, &query); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
 
-	opts := pagination.NewQueryOptions(
-		&query,
-		pagination.SortSearchPolicy{
-			SortableCols:   []string{"role", "created_at", "updated_at"},
-			SearchableCols: []string{"email"},
-		},
-	)
+	var filter ListFilter
+	if err = httpx.BindAndValidateQuery(c, &filter); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
 
-	users, meta, err := h.service.List(httpx.ReqCtx(c), opts)
+	// the login activity of others can't be seen, nor inferred from filtering or sorting by it
+	seeActivity := h.authz.Can(viewer, security.PermUsersActivity)
+	policy := listPolicy
+	if !seeActivity {
+		if filter.filtersActivity() {
+			httpx.FailWithError(c, errActivityForbidden)
+			return
+		}
+		policy = listPolicyWithoutActivity
+	}
+
+	opts := pagination.NewQueryOptions(&query, policy)
+
+	users, meta, err := h.service.List(httpx.ReqCtx(c), filter, opts)
 	if err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
 
+	var data any = ToUserResponseList(users)
+	if seeActivity {
+		data = ToUserActivityResponseList(users)
+	}
 	httpx.OKWithMeta(
 		c,
 		http.StatusOK,
-		ToUserResponseList(users),
+		data,
 		meta,
 	)
 }
@@ -164,13 +266,19 @@ func (h *Handler) List(c *gin.Context) {
 //	@Security		BearerAuth
 //	@Router			/users/{id} [delete]
 func (h *Handler) Delete(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
 	var params IDParam
-	if err := httpx.BindAndValidateURI(c, &params); err != nil {
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
 
-	if err := h.service.Delete(httpx.ReqCtx(c), params.ID); err != nil {
+	if err = h.service.Delete(httpx.ReqCtx(c), actor, params.ID); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
@@ -193,13 +301,19 @@ func (h *Handler) Delete(c *gin.Context) {
 //	@Security		BearerAuth
 //	@Router			/users/{id}/restore [put]
 func (h *Handler) Restore(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
 	var params IDParam
-	if err := httpx.BindAndValidateURI(c, &params); err != nil {
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
 
-	if err := h.service.Restore(httpx.ReqCtx(c), params.ID); err != nil {
+	if err = h.service.Restore(httpx.ReqCtx(c), actor, params.ID); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
@@ -222,13 +336,19 @@ func (h *Handler) Restore(c *gin.Context) {
 //	@Security		BearerAuth
 //	@Router			/users/{id}/block [put]
 func (h *Handler) Block(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
 	var params IDParam
-	if err := httpx.BindAndValidateURI(c, &params); err != nil {
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
 
-	if err := h.service.Block(httpx.ReqCtx(c), params.ID); err != nil {
+	if err = h.service.Block(httpx.ReqCtx(c), actor, params.ID); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
@@ -239,7 +359,7 @@ func (h *Handler) Block(c *gin.Context) {
 		return
 	}
 
-	httpx.OK(c, http.StatusOK, ToUserResponse(user))
+	httpx.OK(c, http.StatusOK, h.userResponse(actor, user))
 }
 
 // Reactivate a user godoc
@@ -257,13 +377,61 @@ func (h *Handler) Block(c *gin.Context) {
 //	@Security		BearerAuth
 //	@Router			/users/{id}/reactivate [put]
 func (h *Handler) Reactivate(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params IDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err = h.service.Reactivate(httpx.ReqCtx(c), actor, params.ID); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	user, err := h.service.GetByID(httpx.ReqCtx(c), params.ID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, h.userResponse(actor, user))
+}
+
+// Unlock a user godoc
+//
+//	@Summary		Unlock user
+//	@Description	Clear failed login attempts and lift a temporary lockout of a user by their ULID
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path	string	true	"User ID"
+//	@Success		200	{object}	users.UserResponse
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/{id}/unlock [put]
+func (h *Handler) Unlock(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
 	var params IDParam
-	if err := httpx.BindAndValidateURI(c, &params); err != nil {
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
 
-	if err := h.service.Reactivate(httpx.ReqCtx(c), params.ID); err != nil {
+	if err = h.service.Unlock(httpx.ReqCtx(c), actor, params.ID); err != nil {
 		httpx.FailWithError(c, err)
 		return
 	}
@@ -274,5 +442,498 @@ func (h *Handler) Reactivate(c *gin.Context) {
 		return
 	}
 
-	httpx.OK(c, http.StatusOK, ToUserResponse(user))
+	httpx.OK(c, http.StatusOK, h.userResponse(actor, user))
+}
+
+// Change a user's role godoc
+//
+//	@Summary		Change user role
+//	@Description	Change the role of a user by their ULID. Only users with a lower role than yours can be changed, and only to a role lower than yours. The user's tokens are revoked
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"User ID"
+//	@Param			request	body		users.ChangeRoleRequest	true	"ChangeRoleRequest"
+//	@Success		200		{object}	users.UserResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		404		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/{id}/role [put]
+func (h *Handler) ChangeRole(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params IDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req ChangeRoleRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	user, err := h.service.ChangeRole(httpx.ReqCtx(c), actor, params.ID, req.Role)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, h.userResponse(actor, user))
+}
+
+// Get my profile godoc
+//
+//	@Summary		Get my profile
+//	@Description	Get the profile of the current user
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	users.UserActivityResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me [get]
+func (h *Handler) GetMe(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	profile, err := h.service.GetByID(httpx.ReqCtx(c), user.UserID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, ToUserActivityResponse(profile))
+}
+
+// Update my profile godoc
+//
+//	@Summary		Update my profile
+//	@Description	Update the display name, bio, locale or timezone of the current user. Omitted fields are left unchanged. The avatar is set by uploading a profile picture
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		users.UpdateProfileRequest	true	"UpdateProfileRequest"
+//	@Success		200		{object}	users.UserActivityResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		404		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/me [patch]
+func (h *Handler) UpdateMe(c *gin.Context) {
+	user, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	profile, err := h.service.UpdateProfile(httpx.ReqCtx(c), user.UserID, req)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, ToUserActivityResponse(profile))
+}
+
+// Update a user godoc
+//
+//	@Summary		Update user
+//	@Description	Update the profile of a user by their ULID. Omitted fields are left unchanged. Only users with a lower role than yours can be updated
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"User ID"
+//	@Param			request	body		users.UpdateProfileRequest	true	"UpdateProfileRequest"
+//	@Success		200		{object}	users.UserResponse
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		404		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/{id} [patch]
+func (h *Handler) Update(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params IDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	user, err := h.service.Update(httpx.ReqCtx(c), actor, params.ID, req)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, h.userResponse(actor, user))
+}
+
+// List a user's sessions godoc
+//
+//	@Summary		List user sessions
+//	@Description	List the devices a user is logged in on by their ULID, most recently used first
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"User ID"
+//	@Success		200	{object}	httpx.SuccessResponse{data=[]sessions.SessionResponse}
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		403	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/{id}/sessions [get]
+func (h *Handler) ListSessions(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params IDParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	list, err := h.service.ListSessions(httpx.ReqCtx(c), actor, params.ID)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, sessions.ToSessionResponseList(list, ""))
+}
+
+// Revoke a user's session godoc
+//
+//	@Summary		Revoke user session
+//	@Description	Log a user out on one device. Its refresh token stops working and its access tokens are rejected immediately
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path	string	true	"User ID"
+//	@Param			session_id	path	string	true	"Session ID"
+//	@Success		204
+//	@Failure		400	{object}	httpx.ErrorResponse
+//	@Failure		401	{object}	httpx.ErrorResponse
+//	@Failure		404	{object}	httpx.ErrorResponse
+//	@Failure		500	{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/{id}/sessions/{session_id} [delete]
+func (h *Handler) RevokeSession(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var params SessionParam
+	if err = httpx.BindAndValidateURI(c, &params); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if err = h.service.RevokeSession(httpx.ReqCtx(c), actor, params.ID, params.SessionID); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Import users godoc
+//
+//	@Summary		Import users
+//	@Description	Create users from an uploaded CSV (header row with email, password and role) or NDJSON file (one CreateUserRequest per line). Every row is validated first and the import is all or nothing. A dry run only reports the invalid rows
+//	@Tags			User
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			file	formData	file	true	"CSV or NDJSON file"
+//	@Param			format	query		string	false	"File format (default: by file extension)"	Enums(csv, ndjson)
+//	@Param			dry_run	query		bool	false	"Only validate the rows (default: false)"
+//	@Success		200		{object}	users.ImportResult	"Dry run"
+//	@Success		201		{object}	users.ImportResult
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		422		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/import [post]
+func (h *Handler) Import(c *gin.Context) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var query ImportQuery
+	if err = httpx.BindAndValidateQuery(c, &query); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	if c.Request.ContentLength > constants.UserImportMaxFileSize {
+		httpx.FailWithError(c, errImportTooLarge)
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		httpx.FailWithError(c, errImportNoFile)
+		return
+	}
+	if file.Size > constants.UserImportMaxFileSize {
+		httpx.FailWithError(c, errImportTooLarge)
+		return
+	}
+
+	format, err := importFormat(query.Format, file.Filename)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		httpx.FailWithError(c, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to open import file",
+			err,
+		))
+		return
+	}
+	defer content.Close()
+
+	rows, err := parseImport(content, format)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	result, err := h.service.Import(httpx.ReqCtx(c), actor, rows, query.DryRun)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if result.DryRun {
+		status = http.StatusOK
+	}
+	httpx.OK(c, status, result)
+}
+
+// Export users godoc
+//
+//	@Summary		Export users
+//	@Description	Download all users matching the search and filters as CSV, in the order of the users list. The file is streamed, so it isn't limited to a page
+//	@Tags			User
+//	@Produce		text/csv
+//	@Param			search				query		string	false	"Search text (case-insensitive)"
+//	@Param			search_columns		query		[]string	false	"Columns to search in (default: all searchable)"
+//	@Param			sort_by				query		string	false	"Field to sort by (role, created_at, updated_at, last_login_at, login_count, failed_login_count)"
+//	@Param			order				query		string	false	"Sort order (asc or desc)"					Enums(asc, desc)	default(desc)
+//	@Param			exact_match			query		bool	false	"Use exact match for search (default: false)"
+//	@Param			status				query		string	false	"Only users with this status"	Enums(active, inactive, blocked, pending_verification, dormant)
+//	@Param			last_login_after	query		string	false	"Only users who last logged in at or after this time (RFC 3339)"
+//	@Param			last_login_before	query		string	false	"Only users who last logged in before this time (RFC 3339)"
+//	@Param			never_logged_in		query		bool	false	"Only users who never logged in"
+//	@Param			min_failed_logins	query		int		false	"Only users with at least this many failed logins since their last login"	minimum(1)
+//	@Success		200					{file}		file
+//	@Failure		400					{object}	httpx.ErrorResponse
+//	@Failure		401					{object}	httpx.ErrorResponse
+//	@Failure		403					{object}	httpx.ErrorResponse
+//	@Failure		500					{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/export [get]
+func (h *Handler) Export(c *gin.Context) {
+	var query pagination.QueryList
+	if err := httpx.BindAndValidateQuery(c, &query); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var filter ListFilter
+	if err := httpx.BindAndValidateQuery(c, &filter); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	opts := pagination.NewQueryOptions(&query, listPolicy)
+
+	// the response starts with the first row, so a failing query can still be reported as JSON
+	writer := csv.NewWriter(c.Writer)
+	controller := http.NewResponseController(c.Writer)
+	written := 0
+	start := func() error {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="users-%s.csv"`, time.Now().UTC().Format("20060102-150405")))
+		c.Status(http.StatusOK)
+		return writer.Write(exportColumns)
+	}
+
+	err := h.service.Export(httpx.ReqCtx(c), filter, opts, func(user *User) error {
+		if written == 0 {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(exportRecord(user)); err != nil {
+			return err
+		}
+		written++
+
+		if written%constants.UserExportFlushRows == 0 {
+			writer.Flush()
+			// the write timeout of the server is meant for regular responses, keep a long export going
+			// as long as the client reads it
+			_ = controller.SetWriteDeadline(time.Now().Add(constants.ServerWriteTimeoutSecond * time.Second))
+			if err := controller.Flush(); err != nil {
+				return err
+			}
+			return writer.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		if written == 0 {
+			httpx.FailWithError(c, err)
+			return
+		}
+		// the status is sent already, cut the response short so the client notices the incomplete file
+		log.Ctx(httpx.ReqCtx(c)).Error().Err(err).Int("rows", written).Msg("failed to stream users export")
+		panic(http.ErrAbortHandler)
+	}
+
+	if written == 0 {
+		if err = start(); err != nil {
+			log.Ctx(httpx.ReqCtx(c)).Error().Err(err).Msg("failed to write users export")
+			return
+		}
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		log.Ctx(httpx.ReqCtx(c)).Error().Err(err).Int("rows", written).Msg("failed to write users export")
+	}
+}
+
+// Bulk block users godoc
+//
+//	@Summary		Bulk block users
+//	@Description	Block the users selected by ids or by filter, up to 500 at once. Each user is checked against the role hierarchy and gets an audit record. By default every user is changed on its own and reported in the results, an atomic request changes all users or none
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		users.BulkActionRequest	true	"BulkActionRequest"
+//	@Success		200		{object}	users.BulkActionResult
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		409		{object}	httpx.ErrorResponse
+//	@Failure		422		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/bulk/block [post]
+func (h *Handler) BulkBlock(c *gin.Context) {
+	h.bulk(c, BulkActionBlock)
+}
+
+// Bulk restore users godoc
+//
+//	@Summary		Bulk restore users
+//	@Description	Restore the deleted users selected by ids or by filter, up to 500 at once. Each user is checked against the role hierarchy and gets an audit record. By default every user is changed on its own and reported in the results, an atomic request changes all users or none
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		users.BulkActionRequest	true	"BulkActionRequest"
+//	@Success		200		{object}	users.BulkActionResult
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		409		{object}	httpx.ErrorResponse
+//	@Failure		422		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/bulk/restore [post]
+func (h *Handler) BulkRestore(c *gin.Context) {
+	h.bulk(c, BulkActionRestore)
+}
+
+// Bulk delete users godoc
+//
+//	@Summary		Bulk delete users
+//	@Description	Delete the users selected by ids or by filter, up to 500 at once. Each user is checked against the role hierarchy and gets an audit record. By default every user is changed on its own and reported in the results, an atomic request changes all users or none
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		users.BulkActionRequest	true	"BulkActionRequest"
+//	@Success		200		{object}	users.BulkActionResult
+//	@Failure		400		{object}	httpx.ErrorResponse
+//	@Failure		401		{object}	httpx.ErrorResponse
+//	@Failure		403		{object}	httpx.ErrorResponse
+//	@Failure		409		{object}	httpx.ErrorResponse
+//	@Failure		422		{object}	httpx.ErrorResponse
+//	@Failure		500		{object}	httpx.ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/users/bulk/delete [post]
+func (h *Handler) BulkDelete(c *gin.Context) {
+	h.bulk(c, BulkActionDelete)
+}
+
+func (h *Handler) bulk(c *gin.Context, action BulkAction) {
+	actor, err := middlewares.GetUser(httpx.ReqCtx(c))
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	var req BulkActionRequest
+	if err = httpx.BindAndValidateJSON(c, &req); err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	result, err := h.service.Bulk(httpx.ReqCtx(c), actor, action, req)
+	if err != nil {
+		httpx.FailWithError(c, err)
+		return
+	}
+
+	httpx.OK(c, http.StatusOK, result)
 }
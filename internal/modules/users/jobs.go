@@ -0,0 +1,29 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mrhpn/go-rest-api/internal/scheduler"
+)
+
+// NewDormancyJob returns the job that marks users dormant once they haven't logged in for idleAfter.
+func NewDormancyJob(repo *Repository, idleAfter, interval time.Duration) scheduler.Job {
+	return scheduler.Job{
+		Name:     "users.mark_dormant",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			affected, err := repo.MarkDormant(ctx, time.Now().Add(-idleAfter))
+			if err != nil {
+				return err
+			}
+
+			if affected > 0 {
+				log.Ctx(ctx).Info().Int64("users", affected).Msg("idle users marked dormant")
+			}
+			return nil
+		},
+	}
+}
@@ -1,6 +1,8 @@
 package users
 
 import (
+	"time"
+
 	"github.com/mrhpn/go-rest-api/internal/model"
 	"github.com/mrhpn/go-rest-api/internal/security"
 )
@@ -13,4 +15,18 @@ type User struct {
 	PasswordHash string              `gorm:"not null"`
 	Role         security.Role       `gorm:"type:varchar(20);not null;default:'user'"`
 	Status       security.UserStatus `gorm:"type:varchar(20);not null;default:'inactive'"`
+	PendingEmail *string             // requested new email, only swapped into Email once confirmed
+
+	DisplayName string `gorm:"type:varchar(100);not null;default:''"`
+	Bio         string `gorm:"type:text;not null;default:''"`
+	AvatarURL   string `gorm:"type:text;not null;default:''"`        // path of the profile picture returned by media.Service
+	Locale      string `gorm:"type:varchar(35);not null;default:''"` // BCP 47 language tag, e.g. en-US
+	Timezone    string `gorm:"type:varchar(64);not null;default:''"` // IANA time zone, e.g. Asia/Yangon
+
+	LastLoginAt      *time.Time // nil until the first login
+	LastLoginIP      string     `gorm:"type:varchar(45);not null;default:''"`
+	LoginCount       int64      `gorm:"not null;default:0"`
+	FailedLoginCount int        `gorm:"not null;default:0"` // failed attempts since the last successful login
+
+	ErasedAt *time.Time // set once personal data was anonymized by an erasure request
 }
@@ -3,8 +3,10 @@ package users
 import (
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/mrhpn/go-rest-api/internal/apperror"
 	"github.com/mrhpn/go-rest-api/internal/pagination"
@@ -20,14 +22,54 @@ type Repository struct {
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{
 		Base: repo.Base{
-			DBInstance: db,
+			DBInstance:  db,
+			TenantScope: memberScope,
 		},
 	}
 }
 
+// memberScope limits users to the members of an organization. Users can belong to several
+// organizations, so they are scoped through the membership table instead of a column of their own.
+// Without an organization, only users who belong to none are left.
+func memberScope(db *gorm.DB, tenantID string) *gorm.DB {
+	if tenantID == "" {
+		return db.Where("users.id NOT IN (SELECT user_id FROM organization_members WHERE deleted_at IS NULL)")
+	}
+	return db.Where(
+		"users.id IN (SELECT user_id FROM organization_members WHERE organization_id = ? AND deleted_at IS NULL)",
+		tenantID,
+	)
+}
+
+// filterScope narrows a users query down to the given filter
+func filterScope(filter ListFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Status != "" {
+			db = db.Where("status = ?", filter.Status)
+		}
+		if filter.LastLoginAfter != nil {
+			db = db.Where("last_login_at >= ?", *filter.LastLoginAfter)
+		}
+		if filter.LastLoginBefore != nil {
+			db = db.Where("last_login_at < ?", *filter.LastLoginBefore)
+		}
+		if filter.NeverLoggedIn {
+			db = db.Where("last_login_at IS NULL")
+		}
+		if filter.MinFailedLogins > 0 {
+			db = db.Where("failed_login_count >= ?", filter.MinFailedLogins)
+		}
+		return db
+	}
+}
+
 func (r *Repository) Create(ctx context.Context, user *User) error {
 	err := r.DB(ctx).Create(user).Error
 	if err != nil {
+		// the unique constraint catches a concurrent sign-up with the same email
+		if repo.IsUniqueViolation(err) {
+			return errEmailExists
+		}
 		// Wrap database errors to preserve context while maintaining client-safe messages
 		return apperror.Wrap(
 			apperror.Internal,
@@ -59,6 +101,26 @@ func (r *Repository) FindByID(ctx context.Context, id string) (*User, error) {
 	return &user, nil
 }
 
+// FindByIDWithDeleted finds a user by id including soft-deleted users.
+func (r *Repository) FindByIDWithDeleted(ctx context.Context, id string) (*User, error) {
+	var user User
+	err := r.DB(ctx).Unscoped().First(&user, "id = ?", id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errUserNotFound
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find user",
+			err,
+		)
+	}
+
+	return &user, nil
+}
+
 func (r *Repository) FindByEmail(ctx context.Context, email string) (*User, error) {
 	var user User
 	err := r.DB(ctx).Where("email = ?", email).First(&user).Error
@@ -79,13 +141,13 @@ func (r *Repository) FindByEmail(ctx context.Context, email string) (*User, erro
 	return &user, nil
 }
 
-func (r *Repository) List(ctx context.Context, opts *pagination.QueryOptions) ([]*User, int64, error) {
+func (r *Repository) List(ctx context.Context, filter ListFilter, opts *pagination.QueryOptions) ([]*User, int64, error) {
 	var users []*User
 	var total int64
 
 	// 1. Get total count
 	err := r.DB(ctx).Model(&User{}).
-		Scopes(pagination.SearchScope(opts)).
+		Scopes(filterScope(filter), pagination.SearchScope(opts)).
 		Count(&total).Error
 	if err != nil {
 		return nil, 0, apperror.Wrap(
@@ -99,7 +161,7 @@ func (r *Repository) List(ctx context.Context, opts *pagination.QueryOptions) ([
 	// 2. Fetch data
 	// If we add relationships later, don't forget to use Preload here
 	err = r.DB(ctx).
-		Scopes(pagination.Paginate(opts)).
+		Scopes(filterScope(filter), pagination.Paginate(opts)).
 		Find(&users).Error
 	if err != nil {
 		return nil, 0, apperror.Wrap(
@@ -181,31 +243,308 @@ func (r *Repository) Reactivate(ctx context.Context, id string) (int64, error) {
 	return result.RowsAffected, nil
 }
 
-func (r *Repository) Activate(ctx context.Context, id string) (*User, error) {
+func (r *Repository) UpdateRole(ctx context.Context, id string, role security.Role) (int64, error) {
+	result := r.DB(ctx).
+		Model(&User{}).
+		Where("id = ?", id).
+		Update("role", role)
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to update user role",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *Repository) VerifyEmail(ctx context.Context, id string) (int64, error) {
+	// only pending users can be verified, so a replayed verification can't undo a block
+	result := r.DB(ctx).
+		Model(&User{}).
+		Where("id = ? AND status = ?", id, security.UserStatusPendingVerification).
+		Update("status", security.UserStatusInactive)
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to verify user email",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// RecordLogin activates a user after a successful login and updates their login activity in a
+// single statement, so concurrent logins can't lose counts. A user blocked since the credentials
+// were checked stays blocked and the login is rejected.
+func (r *Repository) RecordLogin(ctx context.Context, id, ip string) (*User, error) {
 	var user User
+	result := r.DB(ctx).
+		Model(&user).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND status <> ?", id, security.UserStatusBlocked).
+		Updates(map[string]any{
+			"status":             security.UserStatusActive,
+			"last_login_at":      time.Now(),
+			"last_login_ip":      ip,
+			"login_count":        gorm.Expr("login_count + 1"),
+			"failed_login_count": 0,
+		})
 
-	// 1. Find the user first to ensure they exist
-	if err := r.DB(ctx).First(&user, "id = ?", id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errUserNotFound
+	if result.Error != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to record user login",
+			result.Error,
+		)
+	}
+	if result.RowsAffected == 0 {
+		return nil, security.ErrBlockedUser
+	}
+
+	return &user, nil
+}
+
+// RecordFailedLogin counts a failed login attempt of a user
+func (r *Repository) RecordFailedLogin(ctx context.Context, id string) error {
+	err := r.DB(ctx).
+		Model(&User{}).
+		Where("id = ?", id).
+		Update("failed_login_count", gorm.Expr("failed_login_count + 1")).
+		Error
+
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to record failed login",
+			err,
+		)
+	}
+	return nil
+}
+
+// MarkDormant marks active and inactive users dormant whose last login (or creation, if they never
+// logged in) is before idleSince. Blocked and unverified users keep their status.
+func (r *Repository) MarkDormant(ctx context.Context, idleSince time.Time) (int64, error) {
+	result := r.DB(ctx).
+		Model(&User{}).
+		Where("status IN ?", []security.UserStatus{security.UserStatusActive, security.UserStatusInactive}).
+		Where("COALESCE(last_login_at, created_at) < ?", idleSince).
+		Update("status", security.UserStatusDormant)
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to mark users dormant",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *Repository) UpdatePassword(ctx context.Context, id, passwordHash string) (int64, error) {
+	result := r.DB(ctx).
+		Model(&User{}).
+		Where("id = ?", id).
+		Update("password_hash", passwordHash)
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to update password",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// UpdateProfile updates the given profile columns of a user
+func (r *Repository) UpdateProfile(ctx context.Context, id string, fields map[string]any) (int64, error) {
+	result := r.DB(ctx).
+		Model(&User{}).
+		Where("id = ?", id).
+		Updates(fields)
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to update user profile",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// SetPendingEmail stores the email a user asked to change to until they confirm it
+func (r *Repository) SetPendingEmail(ctx context.Context, id, email string) (int64, error) {
+	result := r.DB(ctx).
+		Model(&User{}).
+		Where("id = ?", id).
+		Update("pending_email", email)
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to store pending email",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// ConfirmPendingEmail swaps the pending email of a user into their email. Zero affected rows means
+// there was no pending email (anymore).
+func (r *Repository) ConfirmPendingEmail(ctx context.Context, id string) (int64, error) {
+	result := r.DB(ctx).
+		Model(&User{}).
+		Where("id = ? AND pending_email IS NOT NULL", id).
+		Updates(map[string]any{
+			"email":         gorm.Expr("pending_email"),
+			"pending_email": nil,
+		})
+
+	if result.Error != nil {
+		// someone else took the address between the request and its confirmation
+		if repo.IsUniqueViolation(result.Error) {
+			return 0, errEmailExists
 		}
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to change email",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// FindExistingEmails returns which of the given emails belong to a user, deleted users included, as
+// emails are unique across all of them. Call it without tenant to check every organization.
+func (r *Repository) FindExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	var existing []string
+	err := r.DB(ctx).
+		Unscoped().
+		Model(&User{}).
+		Where("email IN ?", emails).
+		Pluck("email", &existing).Error
+	if err != nil {
 		return nil, apperror.Wrap(
 			apperror.Internal,
 			apperror.ErrDatabaseError.Code,
-			"failed to find user",
+			"failed to find existing emails",
+			err,
+		)
+	}
+	return existing, nil
+}
+
+// Stream calls fn for each user matching the filter in sort order, reading them from the database
+// one by one instead of loading the whole list
+func (r *Repository) Stream(ctx context.Context, filter ListFilter, opts *pagination.QueryOptions, fn func(*User) error) error {
+	db := r.DB(ctx)
+	rows, err := db.Model(&User{}).
+		Scopes(filterScope(filter), pagination.SortScope(opts)).
+		Rows()
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find users",
+			err,
+		)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user User
+		if err = db.ScanRows(rows, &user); err != nil {
+			return apperror.Wrap(
+				apperror.Internal,
+				apperror.ErrDatabaseError.Code,
+				"failed to read user",
+				err,
+			)
+		}
+		if err = fn(&user); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to read users",
 			err,
 		)
 	}
+	return nil
+}
+
+// FindByIDs finds the users with the given ids, including soft-deleted users if withDeleted is set.
+// Ids without a user are left out.
+func (r *Repository) FindByIDs(ctx context.Context, ids []string, withDeleted bool) ([]*User, error) {
+	db := r.DB(ctx)
+	if withDeleted {
+		db = db.Unscoped()
+	}
 
-	// 2. Update the status
-	if err := r.DB(ctx).Model(&user).Update("status", security.UserStatusActive).Error; err != nil {
+	var users []*User
+	if err := db.Where("id IN ?", ids).Find(&users).Error; err != nil {
 		return nil, apperror.Wrap(
 			apperror.Internal,
 			apperror.ErrDatabaseError.Code,
-			"failed to activate user",
+			"failed to find users",
 			err,
 		)
 	}
+	return users, nil
+}
 
-	return &user, nil
+// FindIDs returns the ids of up to limit users matching the filter and having one of the roles,
+// oldest first. With deleted set, only soft-deleted users that weren't erased are considered.
+func (r *Repository) FindIDs(
+	ctx context.Context,
+	filter ListFilter,
+	roles []security.Role,
+	deleted bool,
+	limit int,
+) ([]string, error) {
+	db := r.DB(ctx)
+	if deleted {
+		db = db.Unscoped().Where("deleted_at IS NOT NULL AND erased_at IS NULL")
+	}
+
+	var ids []string
+	err := db.Model(&User{}).
+		Scopes(filterScope(filter)).
+		Where("role IN ?", roles).
+		Order("created_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find users",
+			err,
+		)
+	}
+	return ids, nil
 }
@@ -3,79 +3,156 @@ package users
 import (
 	"context"
 	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/audit"
+	"github.com/mrhpn/go-rest-api/internal/constants"
 	"github.com/mrhpn/go-rest-api/internal/httpx"
+	"github.com/mrhpn/go-rest-api/internal/modules/sessions"
 	"github.com/mrhpn/go-rest-api/internal/pagination"
 	"github.com/mrhpn/go-rest-api/internal/security"
+	"github.com/mrhpn/go-rest-api/internal/tenancy"
 )
 
 // userRepository interface defines the methods required for user data persistence.
 type userRepository interface {
 	Create(ctx context.Context, user *User) error
 	FindByID(ctx context.Context, id string) (*User, error)
+	FindByIDWithDeleted(ctx context.Context, id string) (*User, error)
 	FindByEmail(ctx context.Context, email string) (*User, error)
-	List(ctx context.Context, opts *pagination.QueryOptions) ([]*User, int64, error)
+	List(ctx context.Context, filter ListFilter, opts *pagination.QueryOptions) ([]*User, int64, error)
 	Delete(ctx context.Context, id string) (int64, error)
 	Restore(ctx context.Context, id string) (int64, error)
 	Block(ctx context.Context, id string) (int64, error)
 	Reactivate(ctx context.Context, id string) (int64, error)
-	Activate(ctx context.Context, id string) (*User, error)
+	UpdateRole(ctx context.Context, id string, role security.Role) (int64, error)
+	RecordLogin(ctx context.Context, id, ip string) (*User, error)
+	RecordFailedLogin(ctx context.Context, id string) error
+	VerifyEmail(ctx context.Context, id string) (int64, error)
+	UpdatePassword(ctx context.Context, id, passwordHash string) (int64, error)
+	UpdateProfile(ctx context.Context, id string, fields map[string]any) (int64, error)
+	SetPendingEmail(ctx context.Context, id, email string) (int64, error)
+	ConfirmPendingEmail(ctx context.Context, id string) (int64, error)
+	FindByIDs(ctx context.Context, ids []string, withDeleted bool) ([]*User, error)
+	FindIDs(ctx context.Context, filter ListFilter, roles []security.Role, deleted bool, limit int) ([]string, error)
+	FindExistingEmails(ctx context.Context, emails []string) ([]string, error)
+	Stream(ctx context.Context, filter ListFilter, opts *pagination.QueryOptions, fn func(*User) error) error
+	Transaction(ctx context.Context, fn func(context.Context) error) error
+}
+
+// memberAdder adds users to organizations.
+type memberAdder interface {
+	AddMember(ctx context.Context, organizationID, userID string, role tenancy.MemberRole) error
+}
+
+// tokenRevoker revokes access tokens that were already issued to a user.
+type tokenRevoker interface {
+	RevokeUser(ctx context.Context, userID string) error
+	RevokeSession(ctx context.Context, sessionID string) error
+}
+
+// sessionManager lists and ends the login sessions of a user.
+type sessionManager interface {
+	ListActive(ctx context.Context, userID string) ([]*sessions.Session, error)
+	RevokeSession(ctx context.Context, sessionID, userID string) error
+}
+
+// accountUnlocker clears failed login attempts and lockouts of an account.
+type accountUnlocker interface {
+	Reset(ctx context.Context, account string) error
+}
+
+// passwordHasher hashes new passwords and verifies stored hashes.
+type passwordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, hash string) (ok, needsRehash bool, err error)
+}
+
+// passwordPolicy checks a single rule of the password policy.
+type passwordPolicy interface {
+	Satisfies(rule security.PasswordRule, password, email string) bool
+}
+
+// fileRemover deletes stored media files that are no longer referenced, e.g. replaced avatars.
+type fileRemover interface {
+	Delete(ctx context.Context, path string) error
 }
 
 type service struct {
-	repo userRepository
+	repo         userRepository
+	tokenRevoker tokenRevoker
+	lockout      accountUnlocker
+	auditor      audit.Recorder
+	hasher       passwordHasher
+	policy       passwordPolicy
+	sessions     sessionManager
+	members      memberAdder
+	files        fileRemover
 }
 
 // NewService constructs a users Service with the provided repository.
-func NewService(repo userRepository) Service {
-	return &service{repo: repo}
+func NewService(
+	repo userRepository,
+	tokenRevoker tokenRevoker,
+	lockout accountUnlocker,
+	auditor audit.Recorder,
+	hasher passwordHasher,
+	policy passwordPolicy,
+	sessions sessionManager,
+	members memberAdder,
+	files fileRemover,
+) Service {
+	return &service{
+		repo:         repo,
+		tokenRevoker: tokenRevoker,
+		lockout:      lockout,
+		auditor:      auditor,
+		hasher:       hasher,
+		policy:       policy,
+		sessions:     sessions,
+		members:      members,
+		files:        files,
+	}
 }
 
-func (s *service) Create(ctx context.Context, req CreateUserRequest) (*User, error) {
-	// check email uniqueness
-	_, err := s.repo.FindByEmail(ctx, req.Email)
-	if err == nil {
-		return nil, errEmailExists
+func (s *service) Create(ctx context.Context, actor *security.UserClaims, req CreateUserRequest) (*User, error) {
+	role := req.Role
+	if role == "" {
+		role = security.RoleEmployee
 	}
-	if !errors.Is(err, errUserNotFound) {
-		return nil, err // unexpected DB error
+	if !actor.Role.Outranks(role) {
+		return nil, errRoleNotAssignable
 	}
 
-	// hash password
-	hash, err := security.HashPassword(req.Password)
+	return s.create(ctx, req.Email, req.Password, role, security.UserStatusInactive)
+}
+
+func (s *service) Register(ctx context.Context, email, password string) (*User, error) {
+	// self-registered users always get the lowest role and must verify their email first
+	return s.create(ctx, email, password, security.RoleUser, security.UserStatusPendingVerification)
+}
+
+func (s *service) RegisterExternal(ctx context.Context, email string) (*User, error) {
+	// the account signs in through its identity provider. the random password is never handed out,
+	// but the user can still choose one with the password reset flow
+	password, err := security.GenerateOpaqueToken(constants.UserTokenByteLength)
 	if err != nil {
 		return nil, apperror.Wrap(
 			apperror.Internal,
 			apperror.ErrInternal.Code,
-			"failed to hash password",
+			"failed to generate password",
 			err,
 		)
 	}
 
-	// create user
-	user := &User{
-		Email:        req.Email,
-		Role:         req.Role,
-		PasswordHash: hash,
-	}
-
-	if user.Role == "" {
-		user.Role = security.RoleEmployee
-	}
-
-	if err = s.repo.Create(ctx, user); err != nil {
-		return nil, err
-	}
-
-	log.Ctx(ctx).Info().
-		Str("email", req.Email).
-		Str("role", string(user.Role)).
-		Msg("user created")
-
-	return user, nil
+	// the provider verified the email already
+	return s.create(ctx, email, password, security.RoleUser, security.UserStatusInactive)
 }
 
 func (s *service) GetByID(ctx context.Context, id string) (*User, error) {
@@ -86,8 +163,8 @@ func (s *service) GetByEmail(ctx context.Context, email string) (*User, error) {
 	return s.repo.FindByEmail(ctx, email)
 }
 
-func (s *service) List(ctx context.Context, opts *pagination.QueryOptions) ([]*User, *httpx.PaginationMeta, error) {
-	users, total, err := s.repo.List(ctx, opts)
+func (s *service) List(ctx context.Context, filter ListFilter, opts *pagination.QueryOptions) ([]*User, *httpx.PaginationMeta, error) {
+	users, total, err := s.repo.List(ctx, filter, opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -95,7 +172,11 @@ func (s *service) List(ctx context.Context, opts *pagination.QueryOptions) ([]*U
 	return users, pagination.BuildMeta(opts, total), nil
 }
 
-func (s *service) Delete(ctx context.Context, id string) error {
+func (s *service) Delete(ctx context.Context, actor *security.UserClaims, id string) error {
+	if _, err := s.manageableUser(ctx, actor, id); err != nil {
+		return err
+	}
+
 	affected, err := s.repo.Delete(ctx, id)
 	if err != nil {
 		return err
@@ -105,12 +186,28 @@ func (s *service) Delete(ctx context.Context, id string) error {
 		return errUserNotFound
 	}
 
+	if err = s.revokeTokens(ctx, id); err != nil {
+		return err
+	}
+
 	log.Ctx(ctx).Info().Str("user_id", id).Msg("user deleted")
 
 	return nil
 }
 
-func (s *service) Restore(ctx context.Context, id string) error {
+func (s *service) Restore(ctx context.Context, actor *security.UserClaims, id string) error {
+	// deleted users can only be found unscoped
+	target, err := s.repo.FindByIDWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = AuthorizeManagement(actor, target); err != nil {
+		return err
+	}
+	if target.ErasedAt != nil {
+		return errUserErased
+	}
+
 	affected, err := s.repo.Restore(ctx, id)
 	if err != nil {
 		return err
@@ -125,7 +222,11 @@ func (s *service) Restore(ctx context.Context, id string) error {
 	return nil
 }
 
-func (s *service) Block(ctx context.Context, id string) error {
+func (s *service) Block(ctx context.Context, actor *security.UserClaims, id string) error {
+	if _, err := s.manageableUser(ctx, actor, id); err != nil {
+		return err
+	}
+
 	affected, err := s.repo.Block(ctx, id)
 	if err != nil {
 		return err
@@ -135,12 +236,21 @@ func (s *service) Block(ctx context.Context, id string) error {
 		return errUserNotFound
 	}
 
+	// blocked users must lose access immediately, not when their access token expires
+	if err = s.revokeTokens(ctx, id); err != nil {
+		return err
+	}
+
 	log.Ctx(ctx).Info().Str("user_id", id).Msg("user blocked")
 
 	return nil
 }
 
-func (s *service) Reactivate(ctx context.Context, id string) error {
+func (s *service) Reactivate(ctx context.Context, actor *security.UserClaims, id string) error {
+	if _, err := s.manageableUser(ctx, actor, id); err != nil {
+		return err
+	}
+
 	affected, err := s.repo.Reactivate(ctx, id)
 	if err != nil {
 		return err
@@ -155,12 +265,769 @@ func (s *service) Reactivate(ctx context.Context, id string) error {
 	return nil
 }
 
-func (s *service) Activate(ctx context.Context, id string) (*User, error) {
-	user, err := s.repo.Activate(ctx, id)
+func (s *service) Bulk(ctx context.Context, actor *security.UserClaims, action BulkAction, req BulkActionRequest) (*BulkActionResult, error) {
+	ids, err := s.bulkTargets(ctx, actor, action, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// deleted users can only be found unscoped
+	targets, err := s.repo.FindByIDs(ctx, ids, action == BulkActionRestore)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*User, len(targets))
+	for _, target := range targets {
+		byID[target.ID] = target
+	}
+
+	// the role hierarchy is checked per user, before anything changes
+	errs := make([]error, len(ids))
+	for i, id := range ids {
+		errs[i] = checkBulkTarget(actor, action, byID[id])
+	}
+
+	if req.Atomic {
+		if fields := bulkFailures(ids, errs); len(fields) > 0 {
+			return nil, bulkRejected(fields)
+		}
+		err = s.repo.Transaction(ctx, func(txCtx context.Context) error {
+			for _, id := range ids {
+				if applyErr := s.applyBulk(txCtx, action, id); applyErr != nil {
+					if errors.Is(applyErr, errUserNotFound) {
+						return bulkRejected(map[string]string{id: errUserNotFound.Message})
+					}
+					return applyErr
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// every user is changed in a transaction of its own, so one failure doesn't undo the others
+		for i, id := range ids {
+			if errs[i] == nil {
+				errs[i] = s.repo.Transaction(ctx, func(txCtx context.Context) error {
+					return s.applyBulk(txCtx, action, id)
+				})
+			}
+		}
+	}
+
+	result := &BulkActionResult{
+		Action:  action,
+		Atomic:  req.Atomic,
+		Total:   len(ids),
+		Results: make([]BulkItemResult, len(ids)),
+	}
+	for i, id := range ids {
+		// blocked and deleted users must lose access immediately, like with the single actions
+		if errs[i] == nil && action != BulkActionRestore {
+			errs[i] = s.revokeTokens(ctx, id)
+		}
+
+		result.Results[i] = bulkItemResult(id, errs[i])
+		if errs[i] != nil {
+			result.Failed++
+			if !isClientError(errs[i]) {
+				log.Ctx(ctx).Error().Err(errs[i]).Str("user_id", id).Str("action", string(action)).Msg("bulk action failed for user")
+			}
+			continue
+		}
+		result.Succeeded++
+	}
+
+	log.Ctx(ctx).Info().
+		Str("action", string(action)).
+		Bool("atomic", req.Atomic).
+		Int("succeeded", result.Succeeded).
+		Int("failed", result.Failed).
+		Msg("bulk user action applied")
+
+	return result, nil
+}
+
+func (s *service) Unlock(ctx context.Context, actor *security.UserClaims, id string) error {
+	user, err := s.manageableUser(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.lockout.Reset(ctx, user.Email); err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to unlock user",
+			err,
+		)
+	}
+
+	if err = s.auditor.Record(ctx, audit.Entry{
+		Action:     audit.ActionAccountUnlocked,
+		TargetType: audit.TargetUser,
+		TargetID:   id,
+	}); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", id).Msg("failed to record account unlock")
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", id).Msg("user unlocked")
+
+	return nil
+}
+
+func (s *service) ListSessions(ctx context.Context, actor *security.UserClaims, id string) ([]*sessions.Session, error) {
+	// the devices of a user are only visible to themselves and to those who may manage them
+	var err error
+	if actor.UserID == id {
+		_, err = s.repo.FindByID(ctx, id)
+	} else {
+		_, err = s.manageableUser(ctx, actor, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sessions.ListActive(ctx, id)
+}
+
+func (s *service) RevokeSession(ctx context.Context, actor *security.UserClaims, id, sessionID string) error {
+	if _, err := s.manageableUser(ctx, actor, id); err != nil {
+		return err
+	}
+
+	if err := s.sessions.RevokeSession(ctx, sessionID, id); err != nil {
+		return err
+	}
+	if err := s.tokenRevoker.RevokeSession(ctx, sessionID); err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to revoke session tokens",
+			err,
+		)
+	}
+
+	if err := s.auditor.Record(ctx, audit.Entry{
+		Action:     audit.ActionSessionRevoked,
+		TargetType: audit.TargetUser,
+		TargetID:   id,
+		Metadata:   map[string]any{"session_id": sessionID},
+	}); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", id).Msg("failed to record session revocation")
+	}
+
+	return nil
+}
+
+func (s *service) ChangeRole(ctx context.Context, actor *security.UserClaims, id string, role security.Role) (*User, error) {
+	user, err := s.manageableUser(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	// promoting to the caller's own role (or above) is an escalation too
+	if !actor.Role.Outranks(role) {
+		return nil, errRoleNotAssignable
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	affected, err := s.repo.UpdateRole(ctx, id, role)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, errUserNotFound
+	}
+
+	// the role is embedded in issued tokens, so they must not outlive the change
+	if err = s.revokeTokens(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err = s.auditor.Record(ctx, audit.Entry{
+		Action:     audit.ActionRoleChanged,
+		TargetType: audit.TargetUser,
+		TargetID:   id,
+		Metadata:   map[string]any{"from": user.Role, "to": role},
+	}); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", id).Msg("failed to record role change")
+	}
+
+	log.Ctx(ctx).Info().
+		Str("user_id", id).
+		Str("from", string(user.Role)).
+		Str("to", string(role)).
+		Msg("user role changed")
+
+	user.Role = role
+	return user, nil
+}
+
+func (s *service) UpdateProfile(ctx context.Context, id string, req UpdateProfileRequest) (*User, error) {
+	fields := profileFields(req)
+	if len(fields) == 0 {
+		return s.repo.FindByID(ctx, id)
+	}
+
+	affected, err := s.repo.UpdateProfile(ctx, id, fields)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, errUserNotFound
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", id).Msg("user profile updated")
+
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s *service) Update(ctx context.Context, actor *security.UserClaims, id string, req UpdateProfileRequest) (*User, error) {
+	if _, err := s.manageableUser(ctx, actor, id); err != nil {
+		return nil, err
+	}
+
+	return s.UpdateProfile(ctx, id, req)
+}
+
+func (s *service) SetAvatar(ctx context.Context, id, url string) error {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	previous := user.AvatarURL
+
+	affected, err := s.repo.UpdateProfile(ctx, id, map[string]any{"avatar_url": url})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errUserNotFound
+	}
+
+	// the replaced picture isn't referenced anymore. a failed cleanup only leaves an orphaned file behind
+	if previous != "" && previous != url {
+		if err = s.files.Delete(ctx, previous); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("user_id", id).Str("path", previous).Msg("failed to delete previous avatar")
+		}
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", id).Msg("user avatar updated")
+	return nil
+}
+
+func (s *service) RecordLogin(ctx context.Context, id, ip string) (*User, error) {
+	user, err := s.repo.RecordLogin(ctx, id, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", id).Int64("login_count", user.LoginCount).Msg("user logged in")
+	return user, nil
+}
+
+func (s *service) RecordFailedLogin(ctx context.Context, id string) error {
+	return s.repo.RecordFailedLogin(ctx, id)
+}
+
+func (s *service) VerifyEmail(ctx context.Context, id string) error {
+	affected, err := s.repo.VerifyEmail(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return errUserNotPendingVerification
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", id).Msg("user email verified")
+
+	return nil
+}
+
+func (s *service) SetPassword(ctx context.Context, id, password string) error {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// the password requests don't carry the email, so only here it can be compared with the account's
+	if !s.policy.Satisfies(security.PasswordRuleNoEmail, password, user.Email) {
+		return errPasswordContainsEmail
+	}
+
+	return s.setPassword(ctx, id, password)
+}
+
+func (s *service) DiscardPassword(ctx context.Context, id string) error {
+	// the policy is skipped, a random password could by chance contain the local part of the email
+	password, err := security.GenerateOpaqueToken(constants.UserTokenByteLength)
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to generate password",
+			err,
+		)
+	}
+
+	return s.setPassword(ctx, id, password)
+}
+
+// setPassword stores the hash of a password that passed the policy
+func (s *service) setPassword(ctx context.Context, id, password string) error {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to hash password",
+			err,
+		)
+	}
+
+	affected, err := s.repo.UpdatePassword(ctx, id, hash)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return errUserNotFound
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", id).Msg("user password changed")
+
+	return nil
+}
+
+func (s *service) RequestEmailChange(ctx context.Context, id, email string) error {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(user.Email, email) {
+		return errEmailUnchanged
+	}
+
+	// fail early for taken emails. the unique constraint still decides when the change is confirmed
+	_, err = s.repo.FindByEmail(tenancy.WithoutTenant(ctx), email)
+	if err == nil {
+		return errEmailExists
+	}
+	if !errors.Is(err, errUserNotFound) {
+		return err
+	}
+
+	affected, err := s.repo.SetPendingEmail(ctx, id, email)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errUserNotFound
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", id).Msg("user email change requested")
+
+	return nil
+}
+
+func (s *service) ConfirmEmailChange(ctx context.Context, id string) (*User, error) {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.PendingEmail == nil {
+		return nil, errNoPendingEmail
+	}
+	previous, email := user.Email, *user.PendingEmail
+
+	affected, err := s.repo.ConfirmPendingEmail(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, errNoPendingEmail
+	}
+
+	if err = s.auditor.Record(ctx, audit.Entry{
+		Action:     audit.ActionEmailChanged,
+		TargetType: audit.TargetUser,
+		TargetID:   id,
+		Metadata: map[string]any{
+			"from": previous,
+			"to":   email,
+		},
+	}); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", id).Msg("failed to record email change")
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", id).Msg("user email changed")
+
+	user.Email = email
+	user.PendingEmail = nil
+	return user, nil
+}
+
+func (s *service) VerifyPassword(ctx context.Context, user *User, password string) (bool, error) {
+	ok, needsRehash, err := s.hasher.Verify(password, user.PasswordHash)
+	if err != nil {
+		return false, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to verify password",
+			err,
+		)
+	}
+	if !ok || !needsRehash {
+		return ok, nil
+	}
+
+	// the password is known right now, so upgrade a hash created with an outdated algorithm or cost.
+	// a failed upgrade doesn't fail the login, it's retried next time
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID).Msg("failed to rehash password")
+		return true, nil
+	}
+	if _, err = s.repo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", user.ID).Msg("failed to store rehashed password")
+		return true, nil
+	}
+	user.PasswordHash = hash
+
+	log.Ctx(ctx).Info().Str("user_id", user.ID).Msg("user password rehashed")
+
+	return true, nil
+}
+
+func (s *service) Import(ctx context.Context, actor *security.UserClaims, rows []CreateUserRequest, dryRun bool) (*ImportResult, error) {
+	result := &ImportResult{DryRun: dryRun, Total: len(rows)}
+
+	fields, err := s.validateImport(ctx, actor, rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		if dryRun {
+			result.Errors = fields
+			return result, nil
+		}
+		return nil, invalidImport(fields)
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	users, err := s.importUsers(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// all or nothing, a file that is fixed and uploaded again must not create users twice
+	err = s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		for i, user := range users {
+			if insertErr := s.insert(txCtx, user); insertErr != nil {
+				// the email was taken since validating
+				if errors.Is(insertErr, errEmailExists) {
+					return invalidImport(map[string]string{importRowKey(i+1, "email"): errEmailExists.Message})
+				}
+				return insertErr
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Created = len(users)
+
+	log.Ctx(ctx).Info().Int("created", result.Created).Msg("users imported")
+
+	return result, nil
+}
+
+func (s *service) Export(ctx context.Context, filter ListFilter, opts *pagination.QueryOptions, fn func(*User) error) error {
+	return s.repo.Stream(ctx, filter, opts, fn)
+}
+
+// validateImport checks every row of an import and returns the failures keyed by row and field
+func (s *service) validateImport(ctx context.Context, actor *security.UserClaims, rows []CreateUserRequest) (map[string]string, error) {
+	fields := make(map[string]string)
+	rowByEmail := make(map[string]int, len(rows))
+	emails := make([]string, 0, len(rows))
+
+	for i := range rows {
+		row, n := &rows[i], i+1
+
+		if err := httpx.ValidateStruct(row); err != nil {
+			var appErr *apperror.AppError
+			if !errors.As(err, &appErr) || len(appErr.Fields) == 0 {
+				fields[importRowKey(n, "")] = "row is invalid"
+				continue
+			}
+			for field, message := range appErr.Fields {
+				fields[importRowKey(n, field)] = message
+			}
+			continue
+		}
+		if !actor.Role.Outranks(row.Role) {
+			fields[importRowKey(n, "role")] = errRoleNotAssignable.Message
+			continue
+		}
+
+		email := strings.ToLower(row.Email)
+		if first, ok := rowByEmail[email]; ok {
+			fields[importRowKey(n, "email")] = fmt.Sprintf("duplicate of row %d", first)
+			continue
+		}
+		rowByEmail[email] = n
+		emails = append(emails, row.Email)
+	}
+
+	if len(emails) == 0 {
+		return fields, nil
+	}
+
+	// emails are unique across all organizations
+	existing, err := s.repo.FindExistingEmails(tenancy.WithoutTenant(ctx), emails)
 	if err != nil {
 		return nil, err
 	}
+	for _, email := range existing {
+		fields[importRowKey(rowByEmail[strings.ToLower(email)], "email")] = errEmailExists.Message
+	}
+
+	return fields, nil
+}
+
+// importUsers builds the users of validated import rows. Hashing is slow by design, so the
+// passwords are hashed in parallel.
+func (s *service) importUsers(rows []CreateUserRequest) ([]*User, error) {
+	users := make([]*User, len(rows))
+	errs := make([]error, len(rows))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(runtime.NumCPU(), len(rows)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				hash, err := s.hasher.Hash(rows[i].Password)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				users[i] = &User{
+					Email:        rows[i].Email,
+					Role:         rows[i].Role,
+					Status:       security.UserStatusInactive,
+					PasswordHash: hash,
+				}
+			}
+		}()
+	}
+	for i := range rows {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to hash password",
+			err,
+		)
+	}
+	return users, nil
+}
+
+func (s *service) create(
+	ctx context.Context,
+	email, password string,
+	role security.Role,
+	status security.UserStatus,
+) (*User, error) {
+	// hash password
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to hash password",
+			err,
+		)
+	}
+
+	// create user
+	user := &User{
+		Email:        email,
+		Role:         role,
+		Status:       status,
+		PasswordHash: hash,
+	}
+	if err = s.insert(ctx, user); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("email", email).
+		Str("role", string(user.Role)).
+		Str("status", string(user.Status)).
+		Msg("user created")
 
-	log.Ctx(ctx).Info().Str("user_id", id).Msg("user activated")
 	return user, nil
 }
+
+// insert stores a new user and adds them to the organization of the request, if any
+func (s *service) insert(ctx context.Context, user *User) error {
+	// emails are unique across all organizations. the repository reports a taken one as
+	// errEmailExists, which also covers two concurrent requests for the same email
+	return s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		if createErr := s.repo.Create(txCtx, user); createErr != nil {
+			return createErr
+		}
+		// users created within an organization join it, the tenant scope would hide them otherwise
+		if tenant, ok := tenancy.FromContext(txCtx); ok {
+			return s.members.AddMember(txCtx, tenant.ID, user.ID, tenancy.MemberRoleMember)
+		}
+		return nil
+	})
+}
+
+// bulkTargets returns the ids of the users a bulk action applies to
+func (s *service) bulkTargets(ctx context.Context, actor *security.UserClaims, action BulkAction, req BulkActionRequest) ([]string, error) {
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		return nil, errBulkTargets
+	}
+
+	if req.Filter == nil {
+		// a listed user is changed once
+		ids := make([]string, 0, len(req.IDs))
+		seen := make(map[string]struct{}, len(req.IDs))
+		for _, id := range req.IDs {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	if *req.Filter == (ListFilter{}) {
+		return nil, errBulkFilterEmpty
+	}
+
+	// a filter selects only users the actor may manage, which leaves out the actor too
+	var roles []security.Role
+	for _, role := range security.AllRoles() {
+		if actor.Role.Outranks(role) {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		return []string{}, nil
+	}
+
+	ids, err := s.repo.FindIDs(ctx, *req.Filter, roles, action == BulkActionRestore, constants.UserBulkMaxTargets+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > constants.UserBulkMaxTargets {
+		return nil, errBulkTooMany
+	}
+	return ids, nil
+}
+
+// applyBulk applies a bulk action to one user and records it in the audit log
+func (s *service) applyBulk(ctx context.Context, action BulkAction, id string) error {
+	var (
+		affected    int64
+		err         error
+		auditAction string
+	)
+	switch action {
+	case BulkActionBlock:
+		affected, err = s.repo.Block(ctx, id)
+		auditAction = audit.ActionUserBlocked
+	case BulkActionRestore:
+		affected, err = s.repo.Restore(ctx, id)
+		auditAction = audit.ActionUserRestored
+	case BulkActionDelete:
+		affected, err = s.repo.Delete(ctx, id)
+		auditAction = audit.ActionUserDeleted
+	}
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errUserNotFound
+	}
+
+	// the record is part of the change, a change without its record is rolled back
+	return s.auditor.Record(ctx, audit.Entry{
+		Action:     auditAction,
+		TargetType: audit.TargetUser,
+		TargetID:   id,
+		Metadata:   map[string]any{"bulk": true},
+	})
+}
+
+// manageableUser returns the target of a user management action if the actor may act on it
+func (s *service) manageableUser(ctx context.Context, actor *security.UserClaims, id string) (*User, error) {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = AuthorizeManagement(actor, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// profileFields returns the profile columns a PATCH request sets
+func profileFields(req UpdateProfileRequest) map[string]any {
+	fields := make(map[string]any, 4)
+	if req.DisplayName != nil {
+		fields["display_name"] = strings.TrimSpace(*req.DisplayName)
+	}
+	if req.Bio != nil {
+		fields["bio"] = strings.TrimSpace(*req.Bio)
+	}
+	if req.Locale != nil {
+		fields["locale"] = *req.Locale
+	}
+	if req.Timezone != nil {
+		fields["timezone"] = *req.Timezone
+	}
+	return fields
+}
+
+// AuthorizeManagement enforces the role hierarchy: users can only be managed by someone with a
+// strictly higher role, and never by themselves
+func AuthorizeManagement(actor *security.UserClaims, target *User) error {
+	if actor.UserID == target.ID {
+		return errCannotManageSelf
+	}
+	if !actor.Role.Outranks(target.Role) {
+		return errTargetRoleNotLower
+	}
+	return nil
+}
+
+func (s *service) revokeTokens(ctx context.Context, id string) error {
+	if err := s.tokenRevoker.RevokeUser(ctx, id); err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to revoke user tokens",
+			err,
+		)
+	}
+	return nil
+}
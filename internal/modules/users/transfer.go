@@ -0,0 +1,222 @@
+package users
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/security"
+	"github.com/mrhpn/go-rest-api/internal/timex"
+)
+
+// Formats of bulk user imports
+const (
+	importFormatCSV    = "csv"
+	importFormatNDJSON = "ndjson"
+)
+
+// importColumns are the columns of an import CSV, named in its header row in any order
+var importColumns = []string{"email", "password", "role"}
+
+// exportColumns are the columns of the users CSV export
+var exportColumns = []string{
+	"id",
+	"email",
+	"role",
+	"status",
+	"display_name",
+	"locale",
+	"timezone",
+	"created_at",
+	"last_login_at",
+	"login_count",
+}
+
+// importFormat picks the format of an uploaded import file, preferring the requested one
+func importFormat(requested, filename string) (string, error) {
+	if requested != "" {
+		return requested, nil
+	}
+
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".csv"):
+		return importFormatCSV, nil
+	case strings.HasSuffix(lower, ".ndjson"), strings.HasSuffix(lower, ".jsonl"):
+		return importFormatNDJSON, nil
+	default:
+		return "", errImportFormat
+	}
+}
+
+// parseImport reads the rows of an import file. Rows are numbered from 1, not counting the CSV
+// header or blank NDJSON lines.
+func parseImport(r io.Reader, format string) ([]CreateUserRequest, error) {
+	var (
+		rows []CreateUserRequest
+		err  error
+	)
+	if format == importFormatCSV {
+		rows, err = parseImportCSV(r)
+	} else {
+		rows, err = parseImportNDJSON(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errImportEmpty
+	}
+	return rows, nil
+}
+
+func parseImportCSV(r io.Reader) ([]CreateUserRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errImportEmpty
+	}
+	if err != nil {
+		return nil, malformedImportRow(0, err)
+	}
+
+	// map the header to the known columns, a typo must not silently drop a column. Excel prepends
+	// a byte order mark to UTF-8 files
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		known := false
+		for _, column := range importColumns {
+			known = known || column == name
+		}
+		if !known {
+			return nil, invalidImportRow(0, fmt.Sprintf("unknown column %q, expected %s", name, strings.Join(importColumns, ", ")))
+		}
+		index[name] = i
+	}
+	for _, column := range importColumns {
+		if _, ok := index[column]; !ok {
+			return nil, invalidImportRow(0, fmt.Sprintf("missing column %q", column))
+		}
+	}
+
+	var rows []CreateUserRequest
+	for {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			return rows, nil
+		}
+		if readErr != nil {
+			return nil, malformedImportRow(len(rows)+1, readErr)
+		}
+		if len(rows) == constants.UserImportMaxRows {
+			return nil, errImportTooLarge
+		}
+
+		rows = append(rows, CreateUserRequest{
+			Email:    strings.TrimSpace(record[index["email"]]),
+			Password: record[index["password"]],
+			Role:     security.Role(strings.TrimSpace(record[index["role"]])),
+		})
+	}
+}
+
+func parseImportNDJSON(r io.Reader) ([]CreateUserRequest, error) {
+	scanner := bufio.NewScanner(r)
+	var rows []CreateUserRequest
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if len(rows) == constants.UserImportMaxRows {
+			return nil, errImportTooLarge
+		}
+
+		var row CreateUserRequest
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row); err != nil {
+			return nil, malformedImportRow(len(rows)+1, err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, malformedImportRow(len(rows)+1, err)
+	}
+	return rows, nil
+}
+
+// importRowKey names a field of an import row in the reported errors
+func importRowKey(row int, field string) string {
+	if field == "" {
+		return "rows." + strconv.Itoa(row)
+	}
+	return "rows." + strconv.Itoa(row) + "." + field
+}
+
+// invalidImportRow reports a file that can't be read as rows. Row 0 is the CSV header.
+func invalidImportRow(row int, message string) error {
+	return &apperror.AppError{
+		Kind:    apperror.BadRequest,
+		Code:    errImportMalformed.Code,
+		Message: errImportMalformed.Message,
+		Fields:  map[string]string{importRowKey(row, ""): message},
+	}
+}
+
+func malformedImportRow(row int, err error) error {
+	appErr := invalidImportRow(row, "row could not be parsed").(*apperror.AppError)
+	appErr.Err = err
+	return appErr
+}
+
+// invalidImport reports the rows of an import that failed validation
+func invalidImport(fields map[string]string) error {
+	return &apperror.AppError{
+		Kind:    errImportInvalid.Kind,
+		Code:    errImportInvalid.Code,
+		Message: errImportInvalid.Message,
+		Fields:  fields,
+	}
+}
+
+// exportRecord returns the CSV record of a user in exportColumns order
+func exportRecord(user *User) []string {
+	var lastLoginAt string
+	if user.LastLoginAt != nil {
+		lastLoginAt = timex.ToAPIDateTimeFormat(*user.LastLoginAt)
+	}
+
+	return []string{
+		user.ID,
+		csvSafe(user.Email),
+		string(user.Role),
+		string(user.Status),
+		csvSafe(user.DisplayName),
+		user.Locale,
+		user.Timezone,
+		timex.ToAPIDateTimeFormat(user.CreatedAt),
+		lastLoginAt,
+		strconv.FormatInt(user.LoginCount, 10),
+	}
+}
+
+// csvSafe keeps spreadsheets from evaluating user controlled values as formulas
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,2 @@
+// Package usertokens issues and consumes hashed, single-use, time-limited tokens sent to users (e.g. password reset links).
+package usertokens
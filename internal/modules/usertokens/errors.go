@@ -0,0 +1,12 @@
+package usertokens
+
+import "github.com/mrhpn/go-rest-api/internal/apperror"
+
+var (
+	// errInvalidOrExpiredToken indicates that the token does not exist, was already used or has expired.
+	errInvalidOrExpiredToken = apperror.New(
+		apperror.BadRequest,
+		"INVALID_OR_EXPIRED_TOKEN",
+		"token is invalid or has expired",
+	)
+)
@@ -0,0 +1,35 @@
+package usertokens
+
+import (
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/model"
+)
+
+// Purpose represents what a user token can be used for
+type Purpose string
+
+const (
+	// PurposePasswordReset indicates a token that allows resetting a forgotten password.
+	PurposePasswordReset Purpose = "password_reset"
+	// PurposeEmailVerification indicates a token that confirms ownership of a self-registered email address.
+	PurposeEmailVerification Purpose = "email_verification"
+	// PurposeEmailChange indicates a token that confirms ownership of the new address of an email change.
+	PurposeEmailChange Purpose = "email_change"
+)
+
+// Token represents the db model for a single-use user token. Only the hash of the token is stored.
+type Token struct {
+	model.Base
+
+	UserID    string     `gorm:"type:char(26);not null;index"`
+	Purpose   Purpose    `gorm:"type:varchar(30);not null"`
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // set once the token has been consumed
+}
+
+// TableName specifies the table name for the Token model
+func (Token) TableName() string {
+	return "user_tokens"
+}
@@ -0,0 +1,98 @@
+package usertokens
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	repo "github.com/mrhpn/go-rest-api/internal/repository"
+)
+
+type Repository struct {
+	repo.Base
+}
+
+// NewRepository constructs a usertokens Repository backed by a GORM database.
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{
+		Base: repo.Base{
+			DBInstance: db,
+		},
+	}
+}
+
+func (r *Repository) Create(ctx context.Context, token *Token) error {
+	err := r.DB(ctx).Create(token).Error
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to create user token",
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *Repository) FindByHash(ctx context.Context, hash string, purpose Purpose) (*Token, error) {
+	var token Token
+	err := r.DB(ctx).
+		Where("token_hash = ? AND purpose = ?", hash, purpose).
+		First(&token).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errInvalidOrExpiredToken
+		}
+		return nil, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to find user token",
+			err,
+		)
+	}
+
+	return &token, nil
+}
+
+// MarkUsed flags a token as used only if it is still unused and not expired.
+// Zero affected rows means the token was consumed concurrently or expired in the meantime.
+func (r *Repository) MarkUsed(ctx context.Context, id string) (int64, error) {
+	now := time.Now()
+	result := r.DB(ctx).
+		Model(&Token{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Update("used_at", now)
+
+	if result.Error != nil {
+		return 0, apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to mark user token as used",
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// InvalidateByUserID marks all outstanding tokens of the given purpose as used.
+func (r *Repository) InvalidateByUserID(ctx context.Context, userID string, purpose Purpose) error {
+	err := r.DB(ctx).
+		Model(&Token{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+
+	if err != nil {
+		return apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrDatabaseError.Code,
+			"failed to invalidate user tokens",
+			err,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package usertokens
+
+import (
+	"context"
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/apperror"
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// Service defines the business logic for issuing and consuming single-use user tokens.
+type Service interface {
+	// Issue creates a new token for the user and returns its raw value, which must be delivered to the user
+	// and is never stored. Any previously issued, unused token of the same purpose is invalidated.
+	Issue(ctx context.Context, userID string, purpose Purpose, ttl time.Duration) (string, error)
+
+	// Consume validates the raw token and marks it as used. It can only succeed once.
+	Consume(ctx context.Context, rawToken string, purpose Purpose) (*Token, error)
+}
+
+// tokenRepository interface defines the methods required for user token persistence.
+type tokenRepository interface {
+	Create(ctx context.Context, token *Token) error
+	FindByHash(ctx context.Context, hash string, purpose Purpose) (*Token, error)
+	MarkUsed(ctx context.Context, id string) (int64, error)
+	InvalidateByUserID(ctx context.Context, userID string, purpose Purpose) error
+}
+
+type service struct {
+	repo tokenRepository
+}
+
+// NewService constructs a usertokens Service with the provided repository.
+func NewService(repo tokenRepository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Issue(ctx context.Context, userID string, purpose Purpose, ttl time.Duration) (string, error) {
+	// 1. only the latest token should work
+	if err := s.repo.InvalidateByUserID(ctx, userID, purpose); err != nil {
+		return "", err
+	}
+
+	// 2. generate the raw token
+	raw, err := security.GenerateOpaqueToken(constants.UserTokenByteLength)
+	if err != nil {
+		return "", apperror.Wrap(
+			apperror.Internal,
+			apperror.ErrInternal.Code,
+			"failed to generate user token",
+			err,
+		)
+	}
+
+	// 3. persist only its hash
+	token := &Token{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: security.HashOpaqueToken(raw),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err = s.repo.Create(ctx, token); err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+func (s *service) Consume(ctx context.Context, rawToken string, purpose Purpose) (*Token, error) {
+	token, err := s.repo.FindByHash(ctx, security.HashOpaqueToken(rawToken), purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+		return nil, errInvalidOrExpiredToken
+	}
+
+	affected, err := s.repo.MarkUsed(ctx, token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, errInvalidOrExpiredToken
+	}
+
+	return token, nil
+}
@@ -0,0 +1,3 @@
+// Package oidc implements the relying-party side of OpenID Connect: the authorization code flow
+// with PKCE, provider discovery and ID token verification against the provider's published keys.
+package oidc
@@ -0,0 +1,319 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// ProviderConfig configures a single OpenID Connect identity provider
+type ProviderConfig struct {
+	Name         string // used in urls, e.g. /auth/oidc/google/login
+	Issuer       string // discovery document is read from <issuer>/.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string // empty for public clients, pkce protects the code exchange then
+	RedirectURL  string // our callback, must be registered at the provider
+	Scopes       []string
+	Leeway       time.Duration // tolerated clock skew when checking the id token
+}
+
+// Identity is the verified identity of a user at a provider
+type Identity struct {
+	Provider      string
+	Subject       string // stable user id at the provider
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// discovery is the subset of the provider metadata (OpenID Connect Discovery 1.0) we rely on
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is an OpenID Connect identity provider. The discovery document is fetched on first
+// use, the provider's keys whenever an id token is signed with a key we don't know yet.
+type Provider struct {
+	cfg    ProviderConfig
+	client *http.Client
+
+	mu            sync.Mutex
+	metadata      *discovery
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// NewProvider constructs a Provider. A nil client falls back to one with a short timeout.
+func NewProvider(cfg ProviderConfig, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: constants.OIDCHTTPTimeoutSecond * time.Second}
+	}
+	return &Provider{cfg: cfg, client: client}
+}
+
+// Name returns the configured provider name
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+// AuthCodeURL returns the provider's login page url the browser is redirected to
+func (p *Provider) AuthCodeURL(ctx context.Context, flow *Flow) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", flow.State)
+	q.Set("nonce", flow.Nonce)
+	q.Set("code_challenge", flow.CodeChallenge())
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Exchange redeems an authorization code at the token endpoint and returns the identity from
+// the verified id token
+func (p *Provider) Exchange(ctx context.Context, code string, flow *Flow) (*Identity, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {flow.CodeVerifier},
+		"client_id":     {p.cfg.ClientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		// client_secret_basic, credentials are form encoded first (RFC 6749 2.3.1)
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var res struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.doJSON(req, &res)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK || res.Error != "" {
+		return nil, fmt.Errorf("token endpoint returned %d %s: %s", status, res.Error, res.ErrorDescription)
+	}
+	if res.IDToken == "" {
+		return nil, errors.New("token response has no id_token, is the openid scope requested?")
+	}
+
+	return p.verifyIDToken(ctx, res.IDToken, flow.Nonce)
+}
+
+// idTokenClaims are the id token claims we read, next to the registered ones
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	AuthorizedParty string   `json:"azp"`
+	Nonce           string   `json:"nonce"`
+	Email           string   `json:"email"`
+	EmailVerified   flexBool `json:"email_verified"`
+	Name            string   `json:"name"`
+}
+
+func (p *Provider) verifyIDToken(ctx context.Context, raw, nonce string) (*Identity, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(
+		raw,
+		claims,
+		func(t *jwt.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+			return p.key(ctx, kid)
+		},
+		// providers sign id tokens with their published keys, a shared secret is never accepted
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(p.cfg.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(p.cfg.Leeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("invalid id token: sub is missing")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, errors.New("invalid id token: azp does not match the client id")
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, errors.New("invalid id token: nonce mismatch")
+	}
+
+	return &Identity{
+		Provider:      p.cfg.Name,
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(strings.TrimSpace(claims.Email)),
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// discover returns the provider metadata, fetching it once
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var meta discovery
+	if err := p.getJSON(ctx, strings.TrimSuffix(p.cfg.Issuer, "/")+"/.well-known/openid-configuration", &meta); err != nil {
+		return nil, fmt.Errorf("failed to fetch discovery document: %w", err)
+	}
+	// the issuer must match exactly, otherwise a compromised document could impersonate another provider
+	if meta.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("discovery document issuer %q does not match %q", meta.Issuer, p.cfg.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("discovery document is missing an endpoint")
+	}
+
+	p.metadata = &meta
+	return p.metadata, nil
+}
+
+// key returns the provider key with the given kid. Unknown kids refetch the key set, so key
+// rotations at the provider are picked up, but at most once per refresh interval.
+func (p *Provider) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	if time.Since(p.keysFetchedAt) < constants.OIDCKeyRefreshIntervalSecond*time.Second {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var set security.JWKSet
+	if err = p.getJSON(ctx, meta.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// keys we can't use (e.g. an unsupported curve) are skipped instead of failing the whole set
+		if pub, keyErr := jwk.PublicKey(); keyErr == nil {
+			keys[jwk.Kid] = pub
+		}
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey finds a cached key. Tokens without kid are accepted when the provider has a single key.
+func (p *Provider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if key, ok := p.keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func (p *Provider) getJSON(ctx context.Context, rawURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	status, err := p.doJSON(req, v)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", rawURL, status)
+	}
+	return nil
+}
+
+// doJSON sends the request and decodes a (possibly non-2xx) json response
+func (p *Provider) doJSON(req *http.Request, v any) (int, error) {
+	res, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if err = json.NewDecoder(io.LimitReader(res.Body, constants.OIDCMaxResponseBytes)).Decode(v); err != nil {
+		return res.StatusCode, fmt.Errorf("invalid json response (status %d): %w", res.StatusCode, err)
+	}
+	return res.StatusCode, nil
+}
+
+// flexBool accepts booleans as well as "true"/"false" strings, which some providers send for email_verified
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	case "false", "null", "":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	return nil
+}
@@ -85,19 +85,20 @@ func Paginate(opts *QueryOptions) func(db *gorm.DB) *gorm.DB {
 		db = applySearch(db, opts)
 
 		// 2. Apply Sorting
-		sortField := "created_at" // default
-		if opts.SortBy != "" {
-			if col, ok := opts.SortableColumns[stringx.ToSnakeCase(opts.SortBy)]; ok {
-				sortField = col
-			}
-		}
-		db = db.Order(sortField + " " + opts.Order) // opts.Order is safe. already validated in NewQueryOptions!
+		db = applySort(db, opts)
 
 		// 3. Apply Pagination
 		return db.Limit(opts.Limit).Offset(opts.Offset)
 	}
 }
 
+// SortScope is a GORM Scope applying search and sorting without paging, e.g. for exports of a whole list
+func SortScope(opts *QueryOptions) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return applySort(applySearch(db, opts), opts)
+	}
+}
+
 // SearchScope is a GORM Scope for the count query
 func SearchScope(opts *QueryOptions) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
@@ -105,6 +106,16 @@ func SearchScope(opts *QueryOptions) func(db *gorm.DB) *gorm.DB {
 	}
 }
 
+func applySort(db *gorm.DB, opts *QueryOptions) *gorm.DB {
+	sortField := "created_at" // default
+	if opts.SortBy != "" {
+		if col, ok := opts.SortableColumns[stringx.ToSnakeCase(opts.SortBy)]; ok {
+			sortField = col
+		}
+	}
+	return db.Order(sortField + " " + opts.Order) // opts.Order is safe. already validated in NewQueryOptions!
+}
+
 // sanitizeSearchInput sanitizes search input to prevent LIKE wildcard injection
 // It escapes special characters % and _ which are wildcards in SQL LIKE patterns
 func sanitizeSearchInput(search string) string {
@@ -4,16 +4,55 @@ import (
 	"context"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/mrhpn/go-rest-api/internal/database"
+	"github.com/mrhpn/go-rest-api/internal/tenancy"
 )
 
+// TenantScope restricts the queries of a repository to the rows of one organization, or to the rows
+// of no organization when tenantID is empty
+type TenantScope func(db *gorm.DB, tenantID string) *gorm.DB
+
+// TenantColumn scopes tenant-owned tables by their organization column
+func TenantColumn(column string) TenantScope {
+	return func(db *gorm.DB, tenantID string) *gorm.DB {
+		eq := clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: column}}
+		// a nil value renders as IS NULL
+		if tenantID != "" {
+			eq.Value = tenantID
+		}
+		return db.Where(eq)
+	}
+}
+
 type Base struct {
 	DBInstance *gorm.DB
+
+	// TenantScope is applied to every query made while the context is scoped to a tenant (or to no
+	// organization), so a repository of tenant-owned data can't leak rows across organizations.
+	// nil for shared tables.
+	TenantScope TenantScope
 }
 
 // DB returns a gorm.DB instance tied to the provided context.
 func (r *Base) DB(ctx context.Context) *gorm.DB {
+	db := r.session(ctx)
+
+	// scope tenant-owned data to the organization of the request
+	if tenantID, ok := tenancy.Scope(ctx); ok && r.TenantScope != nil {
+		return r.TenantScope(db, tenantID)
+	}
+	return db
+}
+
+// Transaction runs fn in a database transaction, or in a savepoint of the transaction already in
+// ctx. Repositories called with the ctx passed to fn share the transaction.
+func (r *Base) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	return database.Transaction(ctx, r.DBInstance, fn)
+}
+
+func (r *Base) session(ctx context.Context) *gorm.DB {
 	// 1. check if there is an active transaction in the context
 	if tx := database.GetTx(ctx); tx != nil {
 		return tx
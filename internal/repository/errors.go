@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolationCode is the postgres error code raised when an insert or update breaks a unique constraint
+const uniqueViolationCode = "23505"
+
+// IsUniqueViolation reports whether err was caused by a unique constraint. Checking for an existing row
+// first is racy, so repositories map this error to a conflict instead.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
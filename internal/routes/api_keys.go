@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mrhpn/go-rest-api/internal/app"
+	mw "github.com/mrhpn/go-rest-api/internal/middlewares"
+	"github.com/mrhpn/go-rest-api/internal/modules/apikeys"
+)
+
+func registerAPIKeys(api *gin.RouterGroup, appCtx *app.Context, apiKeyH *apikeys.Handler) {
+	// a leaked api key must not be able to mint or hide other keys
+	apiKeysGroup := api.Group("/api-keys")
+	apiKeysGroup.Use(mw.RequireAuth(appCtx), mw.RejectAPIKeys(), mw.RejectImpersonation())
+	{
+		apiKeysGroup.POST("", apiKeyH.Create)
+		apiKeysGroup.GET("", apiKeyH.List)
+		apiKeysGroup.DELETE("/:id", apiKeyH.Revoke)
+	}
+}
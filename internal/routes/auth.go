@@ -7,6 +7,7 @@ import (
 	"github.com/mrhpn/go-rest-api/internal/constants"
 	mw "github.com/mrhpn/go-rest-api/internal/middlewares"
 	"github.com/mrhpn/go-rest-api/internal/modules/auth"
+	"github.com/mrhpn/go-rest-api/internal/security"
 )
 
 func registerAuth(api *gin.RouterGroup, appCtx *app.Context, authH *auth.Handler) {
@@ -20,6 +21,49 @@ func registerAuth(api *gin.RouterGroup, appCtx *app.Context, authH *auth.Handler
 	authGroup.Use(mw.RateLimitRedisWithConfig(appCtx, authRateLimit))
 	{
 		authGroup.POST("/login", authH.Login)
+		authGroup.POST("/login/mfa", authH.LoginMFA)
+		authGroup.POST("/login/mfa/setup", authH.SetupMFA)
 		authGroup.POST("/refresh", authH.Refresh)
+		// sign-in with external identity providers, only when some are configured
+		if len(appCtx.Cfg.OIDC.Providers) > 0 {
+			authGroup.GET("/oidc/:provider/login", authH.OIDCLogin)
+			authGroup.GET("/oidc/:provider/callback", authH.OIDCCallback)
+		}
+
+		authGroup.POST("/logout", mw.RequireAuth(appCtx), mw.RejectAPIKeys(), authH.Logout)
+		authGroup.POST("/logout-all", mw.RequireAuth(appCtx), mw.RejectAPIKeys(), mw.RejectImpersonation(), authH.LogoutAll)
+		authGroup.POST(
+			"/impersonate",
+			mw.RequireAuth(appCtx),
+			mw.RejectAPIKeys(),
+			mw.RejectImpersonation(),
+			mw.RequirePermission(appCtx, security.PermUsersImpersonate),
+			authH.Impersonate,
+		)
+
+		sessionsGroup := authGroup.Group("/sessions", mw.RequireAuth(appCtx), mw.RejectAPIKeys(), mw.RejectImpersonation())
+		{
+			sessionsGroup.GET("", authH.ListSessions)
+			sessionsGroup.DELETE("/:session_id", authH.RevokeSession)
+		}
+
+		authGroup.POST("/password/forgot", authH.ForgotPassword)
+		authGroup.POST("/password/reset", authH.ResetPassword)
+
+		// public sign-up is opt-in per deployment
+		if appCtx.Cfg.Auth.RegistrationEnabled {
+			authGroup.POST("/register", authH.Register)
+		}
+		authGroup.POST("/verify-email", authH.VerifyEmail)
+		authGroup.POST("/verify-email/resend", authH.ResendVerification)
+		authGroup.POST("/email/confirm", authH.ConfirmEmailChange)
+
+		mfaGroup := authGroup.Group("/mfa", mw.RequireAuth(appCtx), mw.RejectAPIKeys(), mw.RejectImpersonation())
+		{
+			mfaGroup.POST("/enroll", authH.EnrollMFA)
+			mfaGroup.POST("/confirm", authH.ConfirmMFA)
+			mfaGroup.POST("/disable", authH.DisableMFA)
+			mfaGroup.POST("/recovery-codes", authH.RegenerateRecoveryCodes)
+		}
 	}
 }
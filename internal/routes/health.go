@@ -17,6 +17,7 @@ func registerHealth(router *gin.Engine, api *gin.RouterGroup, healthH *health.Ha
 		healthGroup.GET("/", healthH.Check)
 		healthGroup.GET("/live", healthH.Liveness)
 		healthGroup.GET("/ready", healthH.Readiness)
+		healthGroup.GET("/metrics", healthH.Metrics)
 		rateLimitGroup := healthGroup.Group("/rate-limit")
 		{
 			rateLimitGroup.GET("/status", healthH.RateLimitStatus)
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mrhpn/go-rest-api/internal/app"
+	mw "github.com/mrhpn/go-rest-api/internal/middlewares"
+	"github.com/mrhpn/go-rest-api/internal/modules/organizations"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+func registerOrganizations(api *gin.RouterGroup, appCtx *app.Context, orgH *organizations.Handler) {
+	orgsGroup := api.Group("/organizations")
+	orgsGroup.Use(mw.RequireAuth(appCtx))
+	{
+		orgsGroup.POST("", mw.RequirePermission(appCtx, security.PermOrganizationsManage), orgH.Create)
+		orgsGroup.GET("", mw.RequirePermission(appCtx, security.PermOrganizationsManage), orgH.List)
+		orgsGroup.GET("/my", orgH.ListMine)
+		orgsGroup.GET("/:id", orgH.Get)
+		// an organization token extends the session, so neither api keys nor impersonation can mint one
+		orgsGroup.POST("/:id/token", mw.RejectAPIKeys(), mw.RejectImpersonation(), orgH.IssueToken)
+		orgsGroup.GET("/:id/members", orgH.ListMembers)
+		orgsGroup.POST("/:id/members", orgH.AddMember)
+		orgsGroup.PUT("/:id/members/:user_id", orgH.ChangeMemberRole)
+		orgsGroup.DELETE("/:id/members/:user_id", orgH.RemoveMember)
+	}
+}
@@ -10,7 +10,7 @@ import (
 
 func registerPosts(api *gin.RouterGroup, appCtx *app.Context, postH *posts.Handler) {
 	postsGroup := api.Group("/posts")
-	postsGroup.Use(mw.RequireAuth(appCtx))
+	postsGroup.Use(mw.RequireAuth(appCtx), mw.ResolveTenant(appCtx))
 	{
 		postsGroup.POST("", postH.Create)
 		postsGroup.GET("", postH.List)
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mrhpn/go-rest-api/internal/app"
+	mw "github.com/mrhpn/go-rest-api/internal/middlewares"
+	"github.com/mrhpn/go-rest-api/internal/modules/privacy"
+	"github.com/mrhpn/go-rest-api/internal/security"
+)
+
+// registerPrivacy registers the personal data routes. They cover a user's data across every
+// organization, so they are not scoped to a tenant.
+func registerPrivacy(api *gin.RouterGroup, appCtx *app.Context, privacyH *privacy.Handler) {
+	privacyGroup := api.Group("/users")
+	privacyGroup.Use(mw.RequireAuth(appCtx))
+	{
+		// only the user themselves may take out or erase their data
+		self := privacyGroup.Group("/me", mw.RejectAPIKeys(), mw.RejectImpersonation())
+		self.POST("/exports", privacyH.RequestExport)
+		self.GET("/exports", privacyH.ListExports)
+		self.GET("/exports/:id/download", privacyH.DownloadExport)
+		self.GET("/erasure", privacyH.GetErasure)
+		self.POST("/erasure", privacyH.RequestErasure)
+		self.DELETE("/erasure", privacyH.CancelErasure)
+
+		privacyGroup.POST("/:id/erasure", mw.RequirePermission(appCtx, security.PermUsersErase), privacyH.ScheduleErasure)
+		privacyGroup.DELETE("/:id/erasure", mw.RequirePermission(appCtx, security.PermUsersErase), privacyH.CancelScheduledErasure)
+	}
+}
@@ -6,13 +6,28 @@ import (
 	_ "github.com/mrhpn/go-rest-api/docs" // integrates docs
 	"github.com/mrhpn/go-rest-api/internal/app"
 	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/modules/apikeys"
 	"github.com/mrhpn/go-rest-api/internal/modules/auth"
 	"github.com/mrhpn/go-rest-api/internal/modules/health"
+	"github.com/mrhpn/go-rest-api/internal/modules/identities"
 	"github.com/mrhpn/go-rest-api/internal/modules/media"
+	"github.com/mrhpn/go-rest-api/internal/modules/mfa"
+	"github.com/mrhpn/go-rest-api/internal/modules/organizations"
 	"github.com/mrhpn/go-rest-api/internal/modules/posts"
+	"github.com/mrhpn/go-rest-api/internal/modules/privacy"
+	"github.com/mrhpn/go-rest-api/internal/modules/sessions"
 	"github.com/mrhpn/go-rest-api/internal/modules/users"
+	"github.com/mrhpn/go-rest-api/internal/modules/usertokens"
 )
 
+// StreamingPaths returns the routes that stream their response. They are exempt from the request
+// timeout, which buffers the whole response.
+func StreamingPaths() []string {
+	return []string{
+		constants.APIVersionPrefix + "/users/export",
+	}
+}
+
 // Register registers app's api endpoints
 func Register(router *gin.Engine, appCtx *app.Context) {
 	// API versioning: v1 is the current version
@@ -21,27 +36,80 @@ func Register(router *gin.Engine, appCtx *app.Context) {
 	// --- repositories --- //
 	userR := users.NewRepository(appCtx.DB)
 	postR := posts.NewRepository(appCtx.DB)
+	sessionR := sessions.NewRepository(appCtx.DB)
+	userTokenR := usertokens.NewRepository(appCtx.DB)
+	mfaR := mfa.NewRepository(appCtx.DB)
+	apiKeyR := apikeys.NewRepository(appCtx.DB)
+	identityR := identities.NewRepository(appCtx.DB)
+	orgR := organizations.NewRepository(appCtx.DB)
+	privacyR := privacy.NewRepository(appCtx.DB)
 
 	// --- services --- //
-	userS := users.NewService(userR)
-	postS := posts.NewService(postR)
-	authS := auth.NewService(userS, appCtx.SecurityHandler)
+	sessionS := sessions.NewService(sessionR)
+	userS := users.NewService(
+		userR,
+		appCtx.TokenRevocation,
+		appCtx.AccountLockout,
+		appCtx.Audit,
+		appCtx.PasswordHasher,
+		appCtx.PasswordPolicy,
+		sessionS,
+		orgR,
+		appCtx.MediaService,
+	)
+	postS := posts.NewService(postR, appCtx.Authorizer)
+	userTokenS := usertokens.NewService(userTokenR)
+	mfaS := mfa.NewService(mfaR, appCtx.Cfg.Auth.MFAIssuer)
+	apiKeyS := apikeys.NewService(apiKeyR, userR)
+	identityS := identities.NewService(identityR)
+	orgS := organizations.NewService(orgR, userR, appCtx.Authorizer, appCtx.Audit, appCtx.SecurityHandler)
+	authS := auth.NewService(
+		userS,
+		sessionS,
+		appCtx.TokenRevocation,
+		userTokenS,
+		userTokenR,
+		mfaS,
+		appCtx.AccountLockout,
+		appCtx.Audit,
+		appCtx.Mailer,
+		appCtx.OIDC,
+		identityS,
+		appCtx.SecurityHandler,
+		appCtx.Cfg.Auth,
+		appCtx.Cfg.OIDC,
+	)
+
+	privacyS := privacy.NewService(
+		privacyR,
+		appCtx.MediaService,
+		appCtx.TokenRevocation,
+		appCtx.Audit,
+		appCtx.Cfg.Privacy,
+	)
 
 	// --- handlers --- //
 	authH := auth.NewHandler(authS, appCtx)
-	userH := users.NewHandler(userS)
+	userH := users.NewHandler(userS, appCtx.Authorizer)
 	postH := posts.NewHandler(postS)
-	mediaH := media.NewHandler(appCtx.MediaService)
+	mediaH := media.NewHandler(appCtx.MediaService, userS)
+	apiKeyH := apikeys.NewHandler(apiKeyS)
+	orgH := organizations.NewHandler(orgS)
+	privacyH := privacy.NewHandler(privacyS, authS)
 	healthH := health.NewHandler(appCtx)
 
 	// --- routes --- //
 	registerSwagger(router)
 	registerHealth(router, api, healthH)
+	registerWellKnown(router, authH)
 
 	registerAuth(api, appCtx, authH)
-	registerUsers(api, appCtx, userH)
+	registerUsers(api, appCtx, userH, authH)
 	registerMedia(api, appCtx, mediaH)
 	registerPosts(api, appCtx, postH)
+	registerAPIKeys(api, appCtx, apiKeyH)
+	registerOrganizations(api, appCtx, orgH)
+	registerPrivacy(api, appCtx, privacyH)
 
 	registerFallbacks(router)
 }
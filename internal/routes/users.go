@@ -5,20 +5,43 @@ import (
 
 	"github.com/mrhpn/go-rest-api/internal/app"
 	mw "github.com/mrhpn/go-rest-api/internal/middlewares"
+	"github.com/mrhpn/go-rest-api/internal/modules/auth"
 	"github.com/mrhpn/go-rest-api/internal/modules/users"
 	"github.com/mrhpn/go-rest-api/internal/security"
 )
 
-func registerUsers(api *gin.RouterGroup, appCtx *app.Context, userH *users.Handler) {
+func registerUsers(api *gin.RouterGroup, appCtx *app.Context, userH *users.Handler, authH *auth.Handler) {
+	// the current user's own account is reachable whichever organization they act in, so it isn't
+	// scoped to a tenant
+	meGroup := api.Group("/users/me")
+	meGroup.Use(mw.RequireAuth(appCtx))
+	{
+		meGroup.GET("", userH.GetMe)
+		meGroup.PATCH("", userH.UpdateMe)
+		// credentials can only be changed by the user themselves, after confirming their password
+		meGroup.PUT("/password", mw.RejectAPIKeys(), mw.RejectImpersonation(), authH.ChangePassword)
+		meGroup.POST("/email", mw.RejectAPIKeys(), mw.RejectImpersonation(), authH.RequestEmailChange)
+	}
+
 	usersGroup := api.Group("/users")
-	usersGroup.Use(mw.RequireAuth(appCtx))
+	usersGroup.Use(mw.RequireAuth(appCtx), mw.ResolveTenant(appCtx))
 	{
-		usersGroup.GET("", userH.List)
-		usersGroup.GET("/:id", userH.Get)
-		usersGroup.POST("", mw.AllowRoles(security.RoleSuperAdmin, security.RoleAdmin), userH.Create)
-		usersGroup.DELETE("/:id", mw.AllowRoles(security.RoleSuperAdmin, security.RoleAdmin), userH.Delete)
-		usersGroup.PUT("/:id/restore", mw.AllowRoles(security.RoleSuperAdmin, security.RoleAdmin), userH.Restore)
-		usersGroup.PUT("/:id/block", mw.AllowRoles(security.RoleSuperAdmin, security.RoleAdmin), userH.Block)
-		usersGroup.PUT("/:id/reactivate", mw.AllowRoles(security.RoleSuperAdmin, security.RoleAdmin), userH.Reactivate)
+		usersGroup.GET("", mw.RequirePermission(appCtx, security.PermUsersRead), userH.List)
+		usersGroup.GET("/export", mw.RequirePermission(appCtx, security.PermUsersExport), userH.Export)
+		usersGroup.GET("/:id", mw.RequirePermission(appCtx, security.PermUsersRead), userH.Get)
+		usersGroup.POST("", mw.RequirePermission(appCtx, security.PermUsersCreate), userH.Create)
+		usersGroup.POST("/import", mw.RequirePermission(appCtx, security.PermUsersCreate), userH.Import)
+		usersGroup.POST("/bulk/block", mw.RequirePermission(appCtx, security.PermUsersBlock), userH.BulkBlock)
+		usersGroup.POST("/bulk/restore", mw.RequirePermission(appCtx, security.PermUsersRestore), userH.BulkRestore)
+		usersGroup.POST("/bulk/delete", mw.RequirePermission(appCtx, security.PermUsersDelete), userH.BulkDelete)
+		usersGroup.PATCH("/:id", mw.RequirePermission(appCtx, security.PermUsersUpdate), userH.Update)
+		usersGroup.DELETE("/:id", mw.RequirePermission(appCtx, security.PermUsersDelete), userH.Delete)
+		usersGroup.PUT("/:id/restore", mw.RequirePermission(appCtx, security.PermUsersRestore), userH.Restore)
+		usersGroup.PUT("/:id/block", mw.RequirePermission(appCtx, security.PermUsersBlock), userH.Block)
+		usersGroup.PUT("/:id/reactivate", mw.RequirePermission(appCtx, security.PermUsersBlock), userH.Reactivate)
+		usersGroup.PUT("/:id/role", mw.RequirePermission(appCtx, security.PermUsersRole), userH.ChangeRole)
+		usersGroup.PUT("/:id/unlock", mw.RequirePermission(appCtx, security.PermUsersUnlock), userH.Unlock)
+		usersGroup.GET("/:id/sessions", mw.RequirePermission(appCtx, security.PermUsersSessions), userH.ListSessions)
+		usersGroup.DELETE("/:id/sessions/:session_id", mw.RequirePermission(appCtx, security.PermUsersSessions), userH.RevokeSession)
 	}
 }
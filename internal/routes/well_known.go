@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mrhpn/go-rest-api/internal/modules/auth"
+)
+
+func registerWellKnown(router *gin.Engine, authH *auth.Handler) {
+	// Discovery documents live at the root (outside /api) as their paths are fixed by RFCs
+	router.GET("/.well-known/jwks.json", authH.JWKS)
+}
@@ -0,0 +1,2 @@
+// Package scheduler runs periodic background jobs (e.g. marking idle accounts dormant) inside the api process.
+package scheduler
@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// lockKeyPrefix namespaces the locks of jobs in the shared store
+const lockKeyPrefix = "scheduler:lock:"
+
+// Locker takes locks shared by every instance of the api, e.g. kvstore.Store backed by Redis.
+type Locker interface {
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+}
+
+// Exclusive returns a job that only one instance sharing the locker runs per interval. The lock is
+// not released but expires shortly before the next tick, so instances that tick a little later skip
+// the run. Runs taking longer than the interval may still overlap.
+func Exclusive(job Job, locker Locker) Job {
+	run := job.Run
+	key := lockKeyPrefix + job.Name
+	ttl := job.Interval * 9 / 10
+
+	owner, _ := os.Hostname()
+	job.Run = func(ctx context.Context) error {
+		acquired, err := locker.SetNX(ctx, key, owner, ttl)
+		if err != nil {
+			return err
+		}
+		if !acquired {
+			log.Ctx(ctx).Debug().Msg("scheduled job skipped, another instance holds the lock")
+			return nil
+		}
+		return run(ctx)
+	}
+	return job
+}
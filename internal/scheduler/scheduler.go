@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Job is a task that runs periodically in the background. Every instance of the api runs its
+// jobs (unless wrapped by Exclusive), so a job must be safe to run concurrently and repeatedly
+// (e.g. a conditional UPDATE).
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Start runs every job once right away and then at its interval, until ctx is done.
+// A failed run is logged and retried at the next interval.
+func Start(ctx context.Context, jobs ...Job) {
+	for _, job := range jobs {
+		if job.Interval <= 0 {
+			log.Warn().Str("job", job.Name).Msg("⚠ Scheduler — job skipped, its interval must be positive")
+			continue
+		}
+		go loop(ctx, job)
+	}
+}
+
+func loop(ctx context.Context, job Job) {
+	logger := log.With().Str("job", job.Name).Logger()
+	logger.Info().Dur("interval", job.Interval).Msg("📝 Scheduler — job started")
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		runOnce(logger.WithContext(ctx), job)
+
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg("✓ Scheduler job stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs the job and keeps a panicking job from taking the api down
+func runOnce(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Ctx(ctx).Error().Err(fmt.Errorf("panic: %v", r)).Msg("scheduled job panicked")
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		log.Ctx(ctx).Error().Err(err).Dur("duration", time.Since(start)).Msg("scheduled job failed")
+		return
+	}
+	log.Ctx(ctx).Debug().Dur("duration", time.Since(start)).Msg("scheduled job finished")
+}
@@ -0,0 +1,61 @@
+package security
+
+import (
+	"bufio"
+	"crypto/sha1" //nolint:gosec // breach corpora like Have I Been Pwned are distributed as SHA-1 hashes
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// BreachedPasswords is an offline set of passwords known from data breaches
+type BreachedPasswords struct {
+	hashes map[[sha1.Size]byte]struct{}
+}
+
+// LoadBreachedPasswords reads a list of SHA-1 password hashes, one hex encoded hash per line.
+// The "HASH:COUNT" format of Have I Been Pwned downloads is accepted as well; empty lines and
+// lines starting with # are ignored.
+func LoadBreachedPasswords(path string) (*BreachedPasswords, error) {
+	f, err := os.Open(path) //nolint:gosec // path comes from trusted configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	list := &BreachedPasswords{hashes: make(map[[sha1.Size]byte]struct{})}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		entry, _, _ = strings.Cut(entry, ":")
+
+		var hash [sha1.Size]byte
+		if len(entry) != hex.EncodedLen(sha1.Size) {
+			return nil, fmt.Errorf("breached password list line %d is not a SHA-1 hash", line)
+		}
+		if _, err = hex.Decode(hash[:], []byte(entry)); err != nil {
+			return nil, fmt.Errorf("breached password list line %d is not a SHA-1 hash", line)
+		}
+		list.hashes[hash] = struct{}{}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	return list, nil
+}
+
+// Contains reports whether the password is on the list
+func (b *BreachedPasswords) Contains(password string) bool {
+	_, ok := b.hashes[sha1.Sum([]byte(password))] //nolint:gosec // see import
+	return ok
+}
+
+// Len returns the number of hashes on the list
+func (b *BreachedPasswords) Len() int {
+	return len(b.hashes)
+}
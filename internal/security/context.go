@@ -0,0 +1,19 @@
+package security
+
+import "context"
+
+type contextKey string
+
+const claimsKey contextKey = "user_identity"
+
+// WithClaims returns a copy of ctx carrying the authenticated user's claims
+func WithClaims(ctx context.Context, claims *UserClaims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// ClaimsFromContext returns the authenticated user's claims, if any. It lets packages that
+// cannot depend on the http layer (e.g. audit logging) find out who is acting.
+func ClaimsFromContext(ctx context.Context) (*UserClaims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*UserClaims)
+	return claims, ok && claims != nil
+}
@@ -3,6 +3,21 @@ package security
 import "github.com/mrhpn/go-rest-api/internal/apperror"
 
 var (
+	// ErrUnauthorized indicates that the request lacks valid authentication credentials, such as a missing access token.
+	ErrUnauthorized = apperror.New(
+		apperror.Unauthorized,
+		"UNAUTHORIZED",
+		"unauthorized: missing token",
+	)
+
+	// ErrForbidden indicates that the authenticated identity does not
+	// have sufficient permissions to access the requested resource.
+	ErrForbidden = apperror.New(
+		apperror.Forbidden,
+		"FORBIDDEN",
+		"forbidden: insufficient permissions",
+	)
+
 	// ErrInvalidToken indicates that the provided authentication token is malformed, unverifiable, or otherwise invalid.
 	ErrInvalidToken = apperror.New(
 		apperror.Unauthorized,
@@ -17,6 +32,41 @@ var (
 		"token has expired",
 	)
 
+	// ErrRevokedToken indicates that the provided authentication token was revoked (e.g. by logout) before it expired.
+	ErrRevokedToken = apperror.New(
+		apperror.Unauthorized,
+		"TOKEN_REVOKED",
+		"token has been revoked",
+	)
+
+	// ErrInvalidAPIKey indicates that the provided API key is unknown, revoked or expired.
+	ErrInvalidAPIKey = apperror.New(
+		apperror.Unauthorized,
+		"INVALID_API_KEY",
+		"invalid api key",
+	)
+
+	// ErrInsufficientScope indicates that the API key used for the request lacks the scope the request needs.
+	ErrInsufficientScope = apperror.New(
+		apperror.Forbidden,
+		"INSUFFICIENT_SCOPE",
+		"forbidden: api key scope does not allow this request",
+	)
+
+	// ErrAPIKeyNotAllowed indicates that the endpoint requires an interactive login and cannot be used with an API key.
+	ErrAPIKeyNotAllowed = apperror.New(
+		apperror.Forbidden,
+		"API_KEY_NOT_ALLOWED",
+		"forbidden: this endpoint cannot be used with an api key",
+	)
+
+	// ErrImpersonationNotAllowed indicates that the endpoint cannot be used with an impersonation token.
+	ErrImpersonationNotAllowed = apperror.New(
+		apperror.Forbidden,
+		"IMPERSONATION_NOT_ALLOWED",
+		"forbidden: this endpoint cannot be used while impersonating a user",
+	)
+
 	// ErrBlockedUser indicates that the authenticated user is blocked and is not allowed to access protected resources.
 	ErrBlockedUser = apperror.New(
 		apperror.Unauthorized,
@@ -24,6 +74,20 @@ var (
 		"user is blocked",
 	)
 
+	// ErrAccountLocked indicates that the account is temporarily locked after too many failed login attempts.
+	ErrAccountLocked = apperror.New(
+		apperror.Forbidden,
+		"ACCOUNT_LOCKED",
+		"account is temporarily locked due to too many failed login attempts",
+	)
+
+	// ErrLoginThrottled indicates that the previous login attempt failed and the next one has to wait a moment.
+	ErrLoginThrottled = apperror.New(
+		apperror.TooManyRequests,
+		"LOGIN_THROTTLED",
+		"too many failed login attempts, please wait before trying again",
+	)
+
 	// ErrRequestTimeout indicates that the requests coming from client is too many and server blocked for a period of time.
 	ErrRequestTimeout = apperror.New(
 		apperror.RequestTimeout,
@@ -5,83 +5,257 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/mrhpn/go-rest-api/internal/constants"
+)
+
+// TokenType distinguishes what a signed token may be used for
+type TokenType string
+
+const (
+	// TokenTypeAccess is a token accepted by protected endpoints.
+	TokenTypeAccess TokenType = "access"
+	// TokenTypeRefresh is a token that can only be exchanged for a new token pair.
+	TokenTypeRefresh TokenType = "refresh"
+	// TokenTypeMFAChallenge is a short-lived token proving the password step of a login; it must be completed with a second factor.
+	TokenTypeMFAChallenge TokenType = "mfa_challenge"
 )
 
-// UserClaims describes JWT user claims
+// UserClaims describes JWT user claims. Subject always equals UserID.
 type UserClaims struct {
 	jwt.RegisteredClaims
-	UserID string `json:"user_id"`
+	UserID    string    `json:"user_id"`
+	Role      Role      `json:"role"`
+	TokenType TokenType `json:"token_type"`
+
+	// Actor is the user acting on behalf of UserID (RFC 8693 "act"), only set on impersonation tokens
+	Actor *Actor `json:"act,omitempty"`
+
+	// SessionID ties access and refresh tokens to the login they were issued for (the refresh token family),
+	// so that revoking a session also rejects its access tokens
+	SessionID string `json:"sid,omitempty"`
+
+	// OrganizationID is the organization the token was switched to, used when a request doesn't name one
+	OrganizationID string `json:"org,omitempty"`
+
+	// APIKeyID and Scopes are only set when the request was authenticated with an API key instead of a JWT
+	APIKeyID string  `json:"-"`
+	Scopes   []Scope `json:"-"`
+}
+
+// Actor identifies the user behind an impersonation token
+type Actor struct {
+	UserID string `json:"sub"`
 	Role   Role   `json:"role"`
 }
 
+// IsImpersonated reports whether the token was issued to an admin acting as UserID
+func (c *UserClaims) IsImpersonated() bool {
+	return c.Actor != nil
+}
+
 // TokenPair consists of AccessToken and RefreshToken
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+
+	// RefreshTokenID & RefreshTokenExpiresAt are needed to persist the refresh token server-side
+	RefreshTokenID        string    `json:"-"`
+	RefreshTokenExpiresAt time.Time `json:"-"`
+}
+
+// JWTOptions configures the registered claims and lifetimes of issued tokens
+type JWTOptions struct {
+	Issuer             string
+	Audience           []string // tokens must contain the first audience to be accepted
+	AccessTokenExpiry  time.Duration
+	RefreshTokenExpiry time.Duration
+	Leeway             time.Duration // tolerated clock skew for exp, nbf & iat
 }
 
 // JWTHandler struct
 type JWTHandler struct {
-	secret        []byte
+	keys          *KeySet
+	issuer        string
+	audience      []string
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
+	mfaExpiry     time.Duration
+	leeway        time.Duration
+
+	impersonationExpiry time.Duration
 }
 
-// NewJWTHandler constructs a JWTHandler
-func NewJWTHandler(secret string, accessTokenExpirySecond, refreshTokenExpirySecond int) *JWTHandler {
+// NewJWTHandler constructs a JWTHandler that signs with the active key of the key set
+func NewJWTHandler(keys *KeySet, opts JWTOptions) *JWTHandler {
 	return &JWTHandler{
-		secret:        []byte(secret),
-		accessExpiry:  time.Duration(accessTokenExpirySecond) * time.Second,
-		refreshExpiry: time.Duration(refreshTokenExpirySecond) * time.Second,
+		keys:          keys,
+		issuer:        opts.Issuer,
+		audience:      opts.Audience,
+		accessExpiry:  opts.AccessTokenExpiry,
+		refreshExpiry: opts.RefreshTokenExpiry,
+		mfaExpiry:     time.Duration(constants.MFAChallengeTokenExpirationSecond) * time.Second,
+		leeway:        opts.Leeway,
+
+		impersonationExpiry: time.Duration(constants.ImpersonationTokenExpirationSecond) * time.Second,
 	}
 }
 
-// GenerateTokenPair generates access & refresh tokens
-func (h *JWTHandler) GenerateTokenPair(userID string, role Role) (*TokenPair, error) {
-	accessToken, err := h.signToken(userID, role, h.accessExpiry)
+// GenerateTokenPair generates access & refresh tokens for a session. An empty sessionID starts
+// a new session, identified by the id of its first refresh token.
+func (h *JWTHandler) GenerateTokenPair(userID string, role Role, sessionID string) (*TokenPair, error) {
+	refreshID := ulid.Make().String()
+	if sessionID == "" {
+		sessionID = refreshID
+	}
+
+	accessToken, _, err := h.signToken(&UserClaims{
+		UserID:    userID,
+		Role:      role,
+		TokenType: TokenTypeAccess,
+		SessionID: sessionID,
+	}, h.accessExpiry)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := h.signToken(userID, role, h.refreshExpiry)
+	refreshToken, refreshClaims, err := h.signToken(&UserClaims{
+		RegisteredClaims: jwt.RegisteredClaims{ID: refreshID},
+		UserID:           userID,
+		Role:             role,
+		TokenType:        TokenTypeRefresh,
+		SessionID:        sessionID,
+	}, h.refreshExpiry)
 	if err != nil {
 		return nil, err
 	}
 
 	return &TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		AccessToken:           accessToken,
+		RefreshToken:          refreshToken,
+		RefreshTokenID:        refreshClaims.ID,
+		RefreshTokenExpiresAt: refreshClaims.ExpiresAt.Time,
 	}, nil
 }
 
 // GenerateAccessToken generates access token only.
 func (h *JWTHandler) GenerateAccessToken(userID string, role Role) (string, error) {
-	return h.signToken(userID, role, h.accessExpiry)
-}
-
-// private: sign token
-func (h *JWTHandler) signToken(userID string, role Role, expiry time.Duration) (string, error) {
-	claims := UserClaims{
-		UserID: userID,
-		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	token, _, err := h.signToken(&UserClaims{UserID: userID, Role: role, TokenType: TokenTypeAccess}, h.accessExpiry)
+	return token, err
+}
+
+// GenerateMFAChallengeToken generates a short-lived token that lets a user finish a login with their second factor.
+func (h *JWTHandler) GenerateMFAChallengeToken(userID string, role Role) (string, error) {
+	token, _, err := h.signToken(&UserClaims{UserID: userID, Role: role, TokenType: TokenTypeMFAChallenge}, h.mfaExpiry)
+	return token, err
+}
+
+// GenerateImpersonationToken generates a short-lived access token for userID that names the actor
+// in the act claim. It has no refresh token, so impersonation ends when it expires.
+func (h *JWTHandler) GenerateImpersonationToken(userID string, role Role, actor Actor) (string, time.Time, error) {
+	token, claims, err := h.signToken(&UserClaims{
+		UserID:    userID,
+		Role:      role,
+		TokenType: TokenTypeAccess,
+		Actor:     &actor,
+	}, h.impersonationExpiry)
+	if err != nil {
+		return "", time.Time{}, err
 	}
+	return token, claims.ExpiresAt.Time, nil
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(h.secret)
+// GenerateOrganizationToken generates an access token for the same user and session as claims that
+// acts in the given organization by default. Refreshing the session drops the organization again.
+func (h *JWTHandler) GenerateOrganizationToken(claims *UserClaims, organizationID string) (string, time.Time, error) {
+	token, signed, err := h.signToken(&UserClaims{
+		UserID:         claims.UserID,
+		Role:           claims.Role,
+		TokenType:      TokenTypeAccess,
+		SessionID:      claims.SessionID,
+		OrganizationID: organizationID,
+	}, h.accessExpiry)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, signed.ExpiresAt.Time, nil
 }
 
-// ValidateToken validates jwt token
-func (h *JWTHandler) ValidateToken(tokenString string) (*UserClaims, error) {
+// JWKS returns the public verification keys in JSON Web Key Set format
+func (h *JWTHandler) JWKS() JWKSet {
+	return h.keys.JWKS()
+}
+
+// ValidateAccessToken validates a token presented to protected endpoints. Refresh & mfa challenge tokens are rejected.
+func (h *JWTHandler) ValidateAccessToken(tokenString string) (*UserClaims, error) {
+	return h.validate(tokenString, TokenTypeAccess)
+}
+
+// ValidateRefreshToken validates a token presented to obtain a new token pair.
+func (h *JWTHandler) ValidateRefreshToken(tokenString string) (*UserClaims, error) {
+	return h.validate(tokenString, TokenTypeRefresh)
+}
+
+// ValidateMFAChallengeToken validates a token presented to complete a login with a second factor.
+func (h *JWTHandler) ValidateMFAChallengeToken(tokenString string) (*UserClaims, error) {
+	return h.validate(tokenString, TokenTypeMFAChallenge)
+}
+
+// private: sign token. the registered claims are filled in here; every token gets a unique jti
+// (unless one is given) so it can be tracked & revoked server-side
+func (h *JWTHandler) signToken(claims *UserClaims, expiry time.Duration) (string, *UserClaims, error) {
+	if claims.ID == "" {
+		claims.ID = ulid.Make().String()
+	}
+
+	now := time.Now()
+	claims.Issuer = h.issuer
+	claims.Subject = claims.UserID
+	claims.Audience = h.audience
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(expiry))
+	claims.NotBefore = jwt.NewNumericDate(now)
+	claims.IssuedAt = jwt.NewNumericDate(now)
+
+	signer := h.keys.Signer()
+	token := jwt.NewWithClaims(signer.Method, claims)
+	if signer.ID != "" {
+		token.Header["kid"] = signer.ID
+	}
+	signed, err := token.SignedString(signer.signKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+// private: validate signature, registered claims and the expected token type
+func (h *JWTHandler) validate(tokenString string, expected TokenType) (*UserClaims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(h.keys.Algorithms()),
+		jwt.WithIssuer(h.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(h.leeway),
+	}
+	if len(h.audience) > 0 {
+		opts = append(opts, jwt.WithAudience(h.audience[0]))
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		// tokens without kid are signed with the HS256 secret
+		kid, _ := token.Header["kid"].(string)
+		key, ok := h.keys.Verifier(kid)
+		if !ok {
 			return nil, ErrInvalidToken
 		}
-		return h.secret, nil
-	})
+
+		// the algorithm must match the key, otherwise e.g. a public key could be used as an HMAC secret
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, ErrInvalidToken
+		}
+		return key.verifyKey, nil
+	}, opts...)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -90,9 +264,15 @@ func (h *JWTHandler) ValidateToken(tokenString string) (*UserClaims, error) {
 		return nil, ErrInvalidToken
 	}
 
-	if claims, ok := token.Claims.(*UserClaims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*UserClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	// a token of another type (e.g. a refresh token used as access token) is never accepted
+	if claims.TokenType != expected || claims.ID == "" || claims.Subject == "" || claims.Subject != claims.UserID {
+		return nil, ErrInvalidToken
 	}
 
-	return nil, ErrInvalidToken
+	return claims, nil
 }
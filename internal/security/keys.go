@@ -0,0 +1,163 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/mrhpn/go-rest-api/internal/constants"
+)
+
+// Key is a JWT signing or verification key identified by its kid.
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+
+	signKey   any // []byte | *rsa.PrivateKey | *ecdsa.PrivateKey | ed25519.PrivateKey, nil for verify-only keys
+	verifyKey any // []byte | *rsa.PublicKey | *ecdsa.PublicKey | ed25519.PublicKey
+}
+
+// NewHMACKey constructs an HS256 key from a shared secret. HMAC keys are never published in the JWKS.
+func NewHMACKey(id, secret string) *Key {
+	return &Key{
+		ID:        id,
+		Method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+}
+
+// LoadPrivateKey reads a PEM encoded RSA, ECDSA or Ed25519 private key. The algorithm is derived from the key type.
+// An empty id defaults to a fingerprint of the public key.
+func LoadPrivateKey(id, path string) (*Key, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	priv, err := parsePrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T in %s", priv, path)
+	}
+
+	key, err := newAsymmetricKey(id, signer.Public())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	key.signKey = priv
+	return key, nil
+}
+
+// LoadPublicKey reads a PEM encoded RSA, ECDSA or Ed25519 public key (or a private key, keeping only its public part).
+// Public keys are used to keep verifying tokens signed by a retired key during rotation.
+func LoadPublicKey(id, path string) (*Key, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		priv, privErr := parsePrivateKey(block.Bytes)
+		if privErr != nil {
+			return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+		}
+		signer, ok := priv.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type %T in %s", priv, path)
+		}
+		pub = signer.Public()
+	}
+
+	key, err := newAsymmetricKey(id, pub)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// CanSign reports whether the key holds private material
+func (k *Key) CanSign() bool {
+	return k.signKey != nil
+}
+
+// newAsymmetricKey picks the signing method for a public key and derives the default kid
+func newAsymmetricKey(id string, pub crypto.PublicKey) (*Key, error) {
+	var method jwt.SigningMethod
+	switch p := pub.(type) {
+	case *rsa.PublicKey:
+		if p.N.BitLen() < constants.RSAMinKeyBits {
+			return nil, fmt.Errorf("rsa key must be at least %d bits", constants.RSAMinKeyBits)
+		}
+		method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		switch p.Curve {
+		case elliptic.P256():
+			method = jwt.SigningMethodES256
+		case elliptic.P384():
+			method = jwt.SigningMethodES384
+		case elliptic.P521():
+			method = jwt.SigningMethodES512
+		default:
+			return nil, errors.New("unsupported ecdsa curve")
+		}
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", pub)
+	}
+
+	if id == "" {
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(der)
+		id = hex.EncodeToString(sum[:constants.JWTKeyIDByteLength])
+	}
+
+	return &Key{ID: id, Method: method, verifyKey: pub}, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path comes from trusted configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	return block, nil
+}
+
+// parsePrivateKey accepts PKCS#8 as well as the legacy PKCS#1 (RSA) and SEC 1 (EC) encodings
+func parsePrivateKey(der []byte) (any, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unsupported private key encoding")
+}
@@ -0,0 +1,58 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+)
+
+// KeySet holds the active signing key and every key tokens may still be verified with.
+// Rotating keys means adding a new signer while keeping the previous key as a verifier
+// until the tokens it signed have expired.
+type KeySet struct {
+	signer    *Key
+	verifiers map[string]*Key
+}
+
+// NewKeySet constructs a KeySet. The signer is always a verifier as well.
+func NewKeySet(signer *Key, verifiers ...*Key) (*KeySet, error) {
+	if signer == nil || !signer.CanSign() {
+		return nil, errors.New("a signing key with private material is required")
+	}
+
+	ks := &KeySet{
+		signer:    signer,
+		verifiers: map[string]*Key{signer.ID: signer},
+	}
+	for _, k := range verifiers {
+		if _, exists := ks.verifiers[k.ID]; exists {
+			return nil, fmt.Errorf("duplicate jwt key id %q", k.ID)
+		}
+		ks.verifiers[k.ID] = k
+	}
+	return ks, nil
+}
+
+// Signer returns the key new tokens are signed with
+func (ks *KeySet) Signer() *Key {
+	return ks.signer
+}
+
+// Verifier returns the key for the given kid
+func (ks *KeySet) Verifier(kid string) (*Key, bool) {
+	k, ok := ks.verifiers[kid]
+	return k, ok
+}
+
+// Algorithms returns the distinct algorithms of all verification keys
+func (ks *KeySet) Algorithms() []string {
+	seen := make(map[string]struct{}, len(ks.verifiers))
+	algs := make([]string, 0, len(ks.verifiers))
+	for _, k := range ks.verifiers {
+		alg := k.Method.Alg()
+		if _, ok := seen[alg]; !ok {
+			seen[alg] = struct{}{}
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
@@ -0,0 +1,124 @@
+package security
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/kvstore"
+)
+
+// LockoutOptions configures brute-force protection of accounts
+type LockoutOptions struct {
+	MaxAttempts  int           // failures within Window that lock the account
+	Window       time.Duration // failures older than this are forgotten
+	LockDuration time.Duration // how long a locked account stays locked
+	BaseDelay    time.Duration // delay after the first failure, doubled after each further failure
+	MaxDelay     time.Duration // upper bound of the progressive delay
+}
+
+// LockoutStatus describes whether the next login attempt of an account is allowed
+type LockoutStatus struct {
+	Locked     bool          // the account is locked after too many failures
+	RetryAfter time.Duration // zero when an attempt is allowed right away
+	Attempts   int64         // failures counted in the current window (only set by RegisterFailure)
+}
+
+// AccountLockout tracks failed login attempts per account, independently of the client IP.
+// After each failure further attempts are delayed progressively, and after MaxAttempts
+// failures the account is locked for LockDuration. Locks expire on their own.
+type AccountLockout struct {
+	store kvstore.Store
+	opts  LockoutOptions
+}
+
+// NewAccountLockout constructs an AccountLockout
+func NewAccountLockout(store kvstore.Store, opts LockoutOptions) *AccountLockout {
+	return &AccountLockout{store: store, opts: opts}
+}
+
+// Check reports whether the account is currently locked or has to wait before the next attempt.
+func (l *AccountLockout) Check(ctx context.Context, account string) (LockoutStatus, error) {
+	account = normalizeAccount(account)
+
+	if until, ok, err := l.until(ctx, constants.LockoutLockKeyPrefix+account); err != nil || ok {
+		return LockoutStatus{Locked: ok, RetryAfter: until}, err
+	}
+
+	until, _, err := l.until(ctx, constants.LockoutDelayKeyPrefix+account)
+	return LockoutStatus{RetryAfter: until}, err
+}
+
+// RegisterFailure counts a failed attempt and delays or locks the account accordingly.
+func (l *AccountLockout) RegisterFailure(ctx context.Context, account string) (LockoutStatus, error) {
+	account = normalizeAccount(account)
+
+	attempts, err := l.store.Incr(ctx, constants.LockoutFailuresKeyPrefix+account, l.opts.Window)
+	if err != nil {
+		return LockoutStatus{}, err
+	}
+
+	// 1. too many failures: lock the account and start counting from zero once it unlocks
+	if attempts >= int64(l.opts.MaxAttempts) {
+		if err = l.set(ctx, constants.LockoutLockKeyPrefix+account, l.opts.LockDuration); err != nil {
+			return LockoutStatus{}, err
+		}
+		err = l.store.Delete(ctx, constants.LockoutFailuresKeyPrefix+account, constants.LockoutDelayKeyPrefix+account)
+		return LockoutStatus{Locked: true, RetryAfter: l.opts.LockDuration, Attempts: attempts}, err
+	}
+
+	// 2. otherwise make the next attempt wait a bit longer each time
+	delay := l.opts.BaseDelay << (attempts - 1)
+	if delay > l.opts.MaxDelay || delay <= 0 {
+		delay = l.opts.MaxDelay
+	}
+	if err = l.set(ctx, constants.LockoutDelayKeyPrefix+account, delay); err != nil {
+		return LockoutStatus{}, err
+	}
+	return LockoutStatus{RetryAfter: delay, Attempts: attempts}, nil
+}
+
+// Reset clears failures, delays and locks of the account, e.g. after a successful login or an admin unlock.
+func (l *AccountLockout) Reset(ctx context.Context, account string) error {
+	account = normalizeAccount(account)
+	return l.store.Delete(
+		ctx,
+		constants.LockoutFailuresKeyPrefix+account,
+		constants.LockoutDelayKeyPrefix+account,
+		constants.LockoutLockKeyPrefix+account,
+	)
+}
+
+// set stores the time until which the key blocks login attempts
+func (l *AccountLockout) set(ctx context.Context, key string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	until := strconv.FormatInt(time.Now().Add(ttl).UnixMilli(), 10)
+	return l.store.Set(ctx, key, until, ttl)
+}
+
+// until returns the remaining time of a blocking key
+func (l *AccountLockout) until(ctx context.Context, key string) (time.Duration, bool, error) {
+	val, ok, err := l.store.Get(ctx, key)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+
+	ms, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, false, nil // ignore garbage, the key expires anyway
+	}
+
+	remaining := time.Until(time.UnixMilli(ms))
+	if remaining <= 0 {
+		return 0, false, nil
+	}
+	return remaining, true, nil
+}
+
+func normalizeAccount(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
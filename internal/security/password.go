@@ -1,19 +1,198 @@
 package security
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
+)
 
-	"github.com/mrhpn/go-rest-api/internal/constants"
+// Supported password hashing algorithms
+const (
+	PasswordHashArgon2id = "argon2id"
+	PasswordHashBcrypt   = "bcrypt"
 )
 
-// HashPassword hashes a raw string using bcrypt
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), constants.DefaultBcryptCost)
-	return string(bytes), err
+// ErrUnknownPasswordHash is returned for stored hashes in an unsupported format
+var ErrUnknownPasswordHash = errors.New("unknown password hash format")
+
+// PasswordHashOptions configures how new password hashes are created
+type PasswordHashOptions struct {
+	Algorithm string // argon2id | bcrypt
+
+	BcryptCost int
+
+	Argon2Memory      int // in KiB
+	Argon2Iterations  int
+	Argon2Parallelism int
+	Argon2SaltLength  int // in bytes
+	Argon2KeyLength   int // in bytes
+}
+
+// PasswordHasher hashes passwords with the configured algorithm and verifies hashes of every
+// supported algorithm, so that hashes created with older settings keep working and can be upgraded.
+//
+// Argon2id hashes use the PHC string format: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>
+type PasswordHasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     argon2Params
+	saltLength uint32
+	keyLength  uint32
+}
+
+// NewPasswordHasher constructs a PasswordHasher, rejecting unusable parameters
+func NewPasswordHasher(opts PasswordHashOptions) (*PasswordHasher, error) {
+	switch opts.Algorithm {
+	case PasswordHashArgon2id, PasswordHashBcrypt:
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", opts.Algorithm)
+	}
+	// bcrypt settings are validated even when argon2id is used, they are needed to judge legacy hashes
+	if opts.BcryptCost < bcrypt.MinCost || opts.BcryptCost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	if opts.Argon2Memory < 1 || opts.Argon2Iterations < 1 || opts.Argon2SaltLength < 1 || opts.Argon2KeyLength < 1 ||
+		opts.Argon2Memory > math.MaxUint32 || opts.Argon2Iterations > math.MaxUint32 ||
+		opts.Argon2SaltLength > math.MaxUint32 || opts.Argon2KeyLength > math.MaxUint32 {
+		return nil, errors.New("argon2id memory, iterations, salt and key length must be positive")
+	}
+	if opts.Argon2Parallelism < 1 || opts.Argon2Parallelism > math.MaxUint8 {
+		return nil, fmt.Errorf("argon2id parallelism must be between 1 and %d", math.MaxUint8)
+	}
+
+	return &PasswordHasher{
+		algorithm:  opts.Algorithm,
+		bcryptCost: opts.BcryptCost,
+		argon2: argon2Params{
+			version:     argon2.Version,
+			memory:      uint32(opts.Argon2Memory),
+			iterations:  uint32(opts.Argon2Iterations),
+			parallelism: uint8(opts.Argon2Parallelism),
+		},
+		saltLength: uint32(opts.Argon2SaltLength),
+		keyLength:  uint32(opts.Argon2KeyLength),
+	}, nil
+}
+
+// Algorithm returns the algorithm used for new hashes
+func (h *PasswordHasher) Algorithm() string {
+	return h.algorithm
 }
 
-// CheckPassword compares a hash with a raw password
-func CheckPassword(password, hash string) bool {
+// Hash hashes a raw password with the configured algorithm
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.algorithm == PasswordHashBcrypt {
+		bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+		return string(bytes), err
+	}
+
+	salt := make([]byte, h.saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.argon2.iterations, h.argon2.memory, h.argon2.parallelism, h.keyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		h.argon2.version,
+		h.argon2.memory,
+		h.argon2.iterations,
+		h.argon2.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify compares a raw password with a stored hash of any supported algorithm. needsRehash
+// reports whether a matching hash was created with another algorithm or weaker parameters
+// than configured and should be replaced.
+func (h *PasswordHasher) Verify(password, hash string) (ok, needsRehash bool, err error) {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		return h.verifyArgon2id(password, hash)
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		return h.verifyBcrypt(password, hash)
+	default:
+		return false, false, ErrUnknownPasswordHash
+	}
+}
+
+func (h *PasswordHasher) verifyBcrypt(password, hash string) (bool, bool, error) {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+
+	if h.algorithm != PasswordHashBcrypt {
+		return true, true, nil
+	}
+	cost, costErr := bcrypt.Cost([]byte(hash))
+	return true, costErr != nil || cost < h.bcryptCost, nil
+}
+
+func (h *PasswordHasher) verifyArgon2id(password, hash string) (bool, bool, error) {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return false, false, err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, uint32(len(key))) //nolint:gosec // decoded from base64, far below 4GiB
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return false, false, nil
+	}
+
+	needsRehash := h.algorithm != PasswordHashArgon2id ||
+		params.version != h.argon2.version ||
+		params.memory < h.argon2.memory ||
+		params.iterations < h.argon2.iterations ||
+		params.parallelism < h.argon2.parallelism ||
+		len(key) < int(h.keyLength)
+	return true, needsRehash, nil
+}
+
+// argon2Params are the parameters stored in (and read from) an argon2id hash
+type argon2Params struct {
+	version     int
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// decodeArgon2id parses a PHC formatted argon2id hash
+func decodeArgon2id(hash string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 { //nolint:mnd // fixed PHC layout
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &params.version); err != nil {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+
+	return params, salt, key, nil
 }
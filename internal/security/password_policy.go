@@ -0,0 +1,279 @@
+package security
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordRule identifies a single rule of the password policy. The names double as validator tags.
+type PasswordRule string
+
+// Password policy rules, in the order they are checked
+const (
+	PasswordRuleMinLength   PasswordRule = "password_min"
+	PasswordRuleMaxLength   PasswordRule = "password_max"
+	PasswordRuleCharClasses PasswordRule = "password_classes"
+	PasswordRuleNoEmail     PasswordRule = "password_no_email"
+	PasswordRuleStrength    PasswordRule = "password_strength"
+	PasswordRuleNotBreached PasswordRule = "password_breached"
+)
+
+// Password strength scores, similar to zxcvbn
+const (
+	PasswordStrengthVeryWeak = iota
+	PasswordStrengthWeak
+	PasswordStrengthFair
+	PasswordStrengthStrong
+	PasswordStrengthVeryStrong
+)
+
+// estimated entropy (in bits) needed to reach the scores above very weak
+const (
+	strengthWeakBits       = 25
+	strengthFairBits       = 35
+	strengthStrongBits     = 45
+	strengthVeryStrongBits = 55
+)
+
+// emailLocalPartMinLength avoids rejecting passwords because of very short mailbox names like "jo"
+const emailLocalPartMinLength = 3
+
+// PasswordPolicyOptions configures the password policy
+type PasswordPolicyOptions struct {
+	MinLength      int                // in characters
+	MaxLength      int                // in characters
+	MinCharClasses int                // lowercase, uppercase, digits, symbols. 0 disables the rule
+	RejectEmail    bool               // reject passwords containing the email address or its local part
+	MinStrength    int                // PasswordStrength score, 0 disables the rule
+	Breached       *BreachedPasswords // nil disables the breached password check
+}
+
+// PasswordPolicy decides whether a password is acceptable for an account
+type PasswordPolicy struct {
+	opts PasswordPolicyOptions
+}
+
+// NewPasswordPolicy constructs a PasswordPolicy
+func NewPasswordPolicy(opts PasswordPolicyOptions) *PasswordPolicy {
+	return &PasswordPolicy{opts: opts}
+}
+
+// Rules returns the enabled rules in the order they are checked
+func (p *PasswordPolicy) Rules() []PasswordRule {
+	rules := []PasswordRule{PasswordRuleMinLength, PasswordRuleMaxLength}
+	if p.opts.MinCharClasses > 0 {
+		rules = append(rules, PasswordRuleCharClasses)
+	}
+	if p.opts.RejectEmail {
+		rules = append(rules, PasswordRuleNoEmail)
+	}
+	if p.opts.MinStrength > 0 {
+		rules = append(rules, PasswordRuleStrength)
+	}
+	if p.opts.Breached != nil {
+		rules = append(rules, PasswordRuleNotBreached)
+	}
+	return rules
+}
+
+// Param returns the configured parameter of a rule for error messages, empty when the rule has none
+func (p *PasswordPolicy) Param(rule PasswordRule) string {
+	switch rule {
+	case PasswordRuleMinLength:
+		return strconv.Itoa(p.opts.MinLength)
+	case PasswordRuleMaxLength:
+		return strconv.Itoa(p.opts.MaxLength)
+	case PasswordRuleCharClasses:
+		return strconv.Itoa(p.opts.MinCharClasses)
+	case PasswordRuleStrength:
+		return strconv.Itoa(p.opts.MinStrength)
+	default:
+		return ""
+	}
+}
+
+// Satisfies reports whether the password passes a single rule. email may be empty when unknown.
+func (p *PasswordPolicy) Satisfies(rule PasswordRule, password, email string) bool {
+	switch rule {
+	case PasswordRuleMinLength:
+		return utf8.RuneCountInString(password) >= p.opts.MinLength
+	case PasswordRuleMaxLength:
+		return utf8.RuneCountInString(password) <= p.opts.MaxLength
+	case PasswordRuleCharClasses:
+		return charClasses(password) >= p.opts.MinCharClasses
+	case PasswordRuleNoEmail:
+		return !p.opts.RejectEmail || !containsEmail(password, email)
+	case PasswordRuleStrength:
+		return PasswordStrength(password) >= p.opts.MinStrength
+	case PasswordRuleNotBreached:
+		return p.opts.Breached == nil || !p.opts.Breached.Contains(password)
+	default:
+		return false
+	}
+}
+
+// PasswordStrength estimates how hard a password is to guess, from PasswordStrengthVeryWeak (0)
+// to PasswordStrengthVeryStrong (4). Like zxcvbn it looks for patterns instead of only counting
+// character classes: common passwords and words (also in l33t speak), repeated characters and
+// sequences like "abc" or "321" add almost nothing to the estimated entropy.
+func PasswordStrength(password string) int {
+	runes := []rune(password)
+	plain := []rune(unleet(strings.ToLower(password)))
+	covered := make([]bool, len(runes))
+
+	// 1. dictionary words are guessed from a short list, whatever their length
+	bits := 0.0
+	wordBits := math.Log2(float64(len(commonPasswordWords))) + 1 // +1 for capitalization / l33t variants
+	for i := range plain {
+		for _, word := range commonPasswordWords {
+			if !hasRunePrefix(plain[i:], word) || covered[i] {
+				continue
+			}
+			for j := range utf8.RuneCountInString(word) {
+				covered[i+j] = true
+			}
+			bits += wordBits
+		}
+	}
+
+	// 2. every other character adds the entropy of the character pool, unless it repeats or
+	// continues a sequence
+	perChar := math.Log2(float64(charPoolSize(password)))
+	for i, r := range runes {
+		if covered[i] {
+			continue
+		}
+		if i > 0 && (r == runes[i-1] || r == runes[i-1]+1 || r == runes[i-1]-1) {
+			bits++
+			continue
+		}
+		bits += perChar
+	}
+
+	switch {
+	case bits >= strengthVeryStrongBits:
+		return PasswordStrengthVeryStrong
+	case bits >= strengthStrongBits:
+		return PasswordStrengthStrong
+	case bits >= strengthFairBits:
+		return PasswordStrengthFair
+	case bits >= strengthWeakBits:
+		return PasswordStrengthWeak
+	default:
+		return PasswordStrengthVeryWeak
+	}
+}
+
+// charClasses counts the used classes out of lowercase, uppercase, digits and symbols
+func charClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+// charPoolSize estimates the number of characters an attacker has to try per position
+func charPoolSize(password string) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < utf8.RuneSelf:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	size := 0
+	for _, pool := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if pool.used {
+			size += pool.size
+		}
+	}
+	return max(size, 2) //nolint:mnd // log2 of a single character pool would be 0
+}
+
+// containsEmail reports whether the password contains the email address or its local part
+func containsEmail(password, email string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return false
+	}
+
+	password = strings.ToLower(password)
+	if strings.Contains(password, email) {
+		return true
+	}
+	local, _, _ := strings.Cut(email, "@")
+	return utf8.RuneCountInString(local) >= emailLocalPartMinLength && strings.Contains(password, local)
+}
+
+// unleet undoes common character substitutions, e.g. "p@ssw0rd" becomes "password"
+func unleet(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '4', '@':
+			return 'a'
+		case '8':
+			return 'b'
+		case '3':
+			return 'e'
+		case '1', '!', '|':
+			return 'i'
+		case '0':
+			return 'o'
+		case '5', '$':
+			return 's'
+		case '7':
+			return 't'
+		default:
+			return r
+		}
+	}, s)
+}
+
+func hasRunePrefix(runes []rune, prefix string) bool {
+	i := 0
+	for _, r := range prefix {
+		if i >= len(runes) || runes[i] != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// commonPasswordWords are frequent building blocks of leaked passwords
+//
+//nolint:gochecknoglobals // immutable word list
+var commonPasswordWords = []string{
+	"password", "passwort", "passw", "qwerty", "azerty", "asdf", "zxcv", "letmein", "welcome", "admin",
+	"login", "master", "secret", "iloveyou", "love", "monkey", "dragon", "football", "baseball", "soccer",
+	"hockey", "shadow", "sunshine", "princess", "superman", "batman", "trustno", "whatever", "freedom",
+	"hello", "charlie", "michael", "jordan", "summer", "winter", "spring", "autumn", "flower", "cookie",
+	"pokemon", "starwars", "computer", "internet", "changeme", "default", "guest", "test", "user",
+	"root", "abc", "qaz", "wsx", "pass", "god", "money", "access", "ninja", "mustang", "killer",
+	"hunter", "ranger", "buster", "tigger", "pepper", "ginger", "maggie", "jessica", "ashley", "daniel",
+}
@@ -0,0 +1,183 @@
+package security
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Permission is a capability in the form "<resource>:<action>" or "<resource>:<action>:<scope>",
+// e.g. "users:block" or "posts:delete:any". Roles are granted permissions, routes and services
+// check permissions instead of roles.
+type Permission string
+
+const (
+	// PermissionAll grants every permission.
+	PermissionAll Permission = "*"
+
+	// PermUsersRead allows listing and viewing users.
+	PermUsersRead Permission = "users:read"
+	// PermUsersActivity allows seeing, filtering and sorting by the login activity of other users.
+	PermUsersActivity Permission = "users:activity"
+	// PermUsersExport allows downloading all users with their login activity.
+	PermUsersExport Permission = "users:export"
+	// PermUsersCreate allows creating users.
+	PermUsersCreate Permission = "users:create"
+	// PermUsersUpdate allows editing the profile of other users.
+	PermUsersUpdate Permission = "users:update"
+	// PermUsersDelete allows deleting users.
+	PermUsersDelete Permission = "users:delete"
+	// PermUsersRestore allows restoring deleted users.
+	PermUsersRestore Permission = "users:restore"
+	// PermUsersBlock allows blocking and reactivating users.
+	PermUsersBlock Permission = "users:block"
+	// PermUsersRole allows changing the role of users.
+	PermUsersRole Permission = "users:role"
+	// PermUsersUnlock allows unlocking accounts locked after failed logins.
+	PermUsersUnlock Permission = "users:unlock"
+	// PermUsersSessions allows listing and revoking the sessions of other users.
+	PermUsersSessions Permission = "users:sessions"
+	// PermUsersImpersonate allows acting as another user.
+	PermUsersImpersonate Permission = "users:impersonate"
+	// PermUsersErase allows scheduling and cancelling the erasure of other users' personal data.
+	PermUsersErase Permission = "users:erase"
+
+	// PermOrganizationsManage allows creating organizations and managing the members of any of them.
+	PermOrganizationsManage Permission = "organizations:manage"
+
+	// PermPostsReadAny allows reading archived posts of other users, and all their posts on request.
+	PermPostsReadAny Permission = "posts:read:any"
+	// PermPostsUpdateAny allows updating posts of other users.
+	PermPostsUpdateAny Permission = "posts:update:any"
+	// PermPostsDeleteAny allows deleting posts of other users.
+	PermPostsDeleteAny Permission = "posts:delete:any"
+)
+
+// AllPermissions returns every permission the app checks
+func AllPermissions() []Permission {
+	return []Permission{
+		PermUsersRead,
+		PermUsersActivity,
+		PermUsersExport,
+		PermUsersCreate,
+		PermUsersUpdate,
+		PermUsersDelete,
+		PermUsersRestore,
+		PermUsersBlock,
+		PermUsersRole,
+		PermUsersUnlock,
+		PermUsersSessions,
+		PermUsersImpersonate,
+		PermUsersErase,
+		PermOrganizationsManage,
+		PermPostsReadAny,
+		PermPostsUpdateAny,
+		PermPostsDeleteAny,
+	}
+}
+
+// DefaultRolePermissions returns the built-in role to permission mapping. Configured mappings
+// replace the permissions of a role entirely.
+func DefaultRolePermissions() map[Role][]Permission {
+	return map[Role][]Permission{
+		RoleSuperAdmin: {PermissionAll},
+		RoleAdmin: {
+			PermUsersRead,
+			PermUsersActivity,
+			PermUsersExport,
+			PermUsersCreate,
+			PermUsersUpdate,
+			PermUsersDelete,
+			PermUsersRestore,
+			PermUsersBlock,
+			PermUsersRole,
+			PermUsersUnlock,
+			PermUsersSessions,
+			PermUsersErase,
+			PermPostsReadAny,
+			PermPostsUpdateAny,
+			PermPostsDeleteAny,
+		},
+		RoleEmployee: {PermUsersRead},
+		RoleUser:     {PermUsersRead},
+	}
+}
+
+// IsValidPermission reports whether p is a known permission or a wildcard matching some,
+// e.g. "*", "users:*" or "posts:delete:*"
+func IsValidPermission(p Permission) bool {
+	if p == PermissionAll {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(string(p), "*"); ok {
+		if !strings.HasSuffix(prefix, ":") {
+			return false
+		}
+		return slices.ContainsFunc(AllPermissions(), func(known Permission) bool {
+			return strings.HasPrefix(string(known), prefix)
+		})
+	}
+	return slices.Contains(AllPermissions(), p)
+}
+
+// Authorizer answers permission checks from a role to permission mapping. It is safe for
+// concurrent use since the mapping is never modified after construction.
+type Authorizer struct {
+	grants map[Role][]Permission
+}
+
+// NewAuthorizer constructs an Authorizer. Roles missing from the mapping have no permissions.
+func NewAuthorizer(rolePermissions map[Role][]Permission) (*Authorizer, error) {
+	grants := make(map[Role][]Permission, len(rolePermissions))
+	for role, permissions := range rolePermissions {
+		if !IsValidRole(role) {
+			return nil, fmt.Errorf("invalid role %q in permission mapping", role)
+		}
+		for _, p := range permissions {
+			if !IsValidPermission(p) {
+				return nil, fmt.Errorf("invalid permission %q for role %q", p, role)
+			}
+		}
+		grants[role] = slices.Clone(permissions)
+	}
+	return &Authorizer{grants: grants}, nil
+}
+
+// HasPermission reports whether the role is granted the permission, directly or by a wildcard
+func (a *Authorizer) HasPermission(role Role, p Permission) bool {
+	for _, granted := range a.grants[role] {
+		if granted == p || granted == PermissionAll {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(string(granted), "*"); ok && strings.HasPrefix(string(p), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Can reports whether the authenticated user holds the permission. Permissions are resolved from
+// the role in the claims, so changes to the mapping apply to tokens already issued.
+func (a *Authorizer) Can(claims *UserClaims, p Permission) bool {
+	return claims != nil && a.HasPermission(claims.Role, p)
+}
+
+// CanAccess is the object-level check: owners may always act on their own resources, everybody
+// else needs the permission, e.g. CanAccess(claims, post.UserID, PermPostsDeleteAny)
+func (a *Authorizer) CanAccess(claims *UserClaims, ownerID string, p Permission) bool {
+	if claims == nil {
+		return false
+	}
+	return claims.UserID == ownerID || a.HasPermission(claims.Role, p)
+}
+
+// Permissions returns the known permissions the role is granted, with wildcards expanded
+func (a *Authorizer) Permissions(role Role) []Permission {
+	var granted []Permission
+	for _, p := range AllPermissions() {
+		if a.HasPermission(role, p) {
+			granted = append(granted, p)
+		}
+	}
+	return granted
+}
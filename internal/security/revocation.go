@@ -0,0 +1,96 @@
+package security
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/mrhpn/go-rest-api/internal/constants"
+	"github.com/mrhpn/go-rest-api/internal/kvstore"
+)
+
+// TokenRevocation keeps track of access tokens that must be rejected before they expire.
+// Single tokens are denylisted by jti and single sessions by sid, while "revoke everything" is
+// recorded as a per-user timestamp: any token issued before or within the same second is considered revoked.
+type TokenRevocation struct {
+	store          kvstore.Store
+	maxTokenExpiry time.Duration
+	leeway         time.Duration
+}
+
+// NewTokenRevocation constructs a TokenRevocation. maxTokenExpirySecond should be the longest lifetime
+// of a token checked against it and leewaySecond the clock skew tolerated when validating tokens, so that
+// revocation entries outlive the tokens they revoke.
+func NewTokenRevocation(store kvstore.Store, maxTokenExpirySecond, leewaySecond int) *TokenRevocation {
+	return &TokenRevocation{
+		store:          store,
+		maxTokenExpiry: time.Duration(maxTokenExpirySecond) * time.Second,
+		leeway:         time.Duration(leewaySecond) * time.Second,
+	}
+}
+
+// RevokeToken denylists a single token until it expires (including the validation leeway).
+func (r *TokenRevocation) RevokeToken(ctx context.Context, claims *UserClaims) error {
+	if claims.ID == "" {
+		return nil
+	}
+
+	ttl := r.maxTokenExpiry
+	if claims.ExpiresAt != nil {
+		ttl = time.Until(claims.ExpiresAt.Time)
+	}
+	ttl += r.leeway
+	if ttl <= 0 {
+		return nil // already expired, nothing to do
+	}
+
+	return r.store.Set(ctx, constants.RevokedTokenKeyPrefix+claims.ID, "1", ttl)
+}
+
+// RevokeUser revokes every token issued to the user up to now.
+func (r *TokenRevocation) RevokeUser(ctx context.Context, userID string) error {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	return r.store.Set(ctx, constants.RevokedUserKeyPrefix+userID, now, r.maxTokenExpiry+r.leeway)
+}
+
+// RevokeSession revokes every token issued for the session (login) so far and in the future.
+func (r *TokenRevocation) RevokeSession(ctx context.Context, sessionID string) error {
+	return r.store.Set(ctx, constants.RevokedSessionKeyPrefix+sessionID, "1", r.maxTokenExpiry+r.leeway)
+}
+
+// IsRevoked reports whether the token was revoked individually, with its session or by a user-wide revocation.
+func (r *TokenRevocation) IsRevoked(ctx context.Context, claims *UserClaims) (bool, error) {
+	var denylistKeys []string
+	if claims.ID != "" {
+		denylistKeys = append(denylistKeys, constants.RevokedTokenKeyPrefix+claims.ID)
+	}
+	if claims.SessionID != "" {
+		denylistKeys = append(denylistKeys, constants.RevokedSessionKeyPrefix+claims.SessionID)
+	}
+	for _, key := range denylistKeys {
+		_, denied, err := r.store.Get(ctx, key)
+		if err != nil {
+			return false, err
+		}
+		if denied {
+			return true, nil
+		}
+	}
+
+	val, found, err := r.store.Get(ctx, constants.RevokedUserKeyPrefix+claims.UserID)
+	if err != nil || !found {
+		return false, err
+	}
+
+	revokedAt, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return false, err
+	}
+
+	// tokens without iat cannot prove they were issued after the revocation
+	if claims.IssuedAt == nil {
+		return true, nil
+	}
+	// iat has a resolution of seconds, so a token issued within the second of the revocation may predate it
+	return claims.IssuedAt.Unix() <= revokedAt, nil
+}
@@ -1,5 +1,7 @@
 package security
 
+import "slices"
+
 // Role represents the roles that app supports
 type Role string
 
@@ -18,6 +20,26 @@ func (r Role) String() string {
 	return string(r)
 }
 
+// AllRoles returns every role the app supports, from most to least privileged.
+func AllRoles() []Role {
+	return []Role{RoleSuperAdmin, RoleAdmin, RoleEmployee, RoleUser}
+}
+
+// Rank returns the position of the role in the hierarchy, higher ranks are more privileged.
+// Unknown roles rank below every valid role.
+func (r Role) Rank() int {
+	roles := AllRoles()
+	if i := slices.Index(roles, r); i >= 0 {
+		return len(roles) - i
+	}
+	return 0
+}
+
+// Outranks reports whether the role is strictly more privileged than other
+func (r Role) Outranks(other Role) bool {
+	return r.Rank() > other.Rank()
+}
+
 // IsValidRole reports whether the given role is supported by the system.
 func IsValidRole(role Role) bool {
 	switch role {
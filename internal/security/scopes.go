@@ -0,0 +1,37 @@
+package security
+
+import (
+	"context"
+	"net/http"
+	"slices"
+)
+
+// Scope limits what a request authenticated with an API key may do
+type Scope string
+
+const (
+	// ScopeRead allows safe requests only (GET, HEAD, OPTIONS).
+	ScopeRead Scope = "read"
+	// ScopeWrite allows requests that change data.
+	ScopeWrite Scope = "write"
+)
+
+// IsValidScope checks if the given scope is valid
+func IsValidScope(scope Scope) bool {
+	return scope == ScopeRead || scope == ScopeWrite
+}
+
+// ScopesAllow reports whether the scopes allow a request with the given HTTP method
+func ScopesAllow(scopes []Scope, method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return slices.Contains(scopes, ScopeRead) || slices.Contains(scopes, ScopeWrite)
+	default:
+		return slices.Contains(scopes, ScopeWrite)
+	}
+}
+
+// APIKeyAuthenticator resolves a raw API key to the identity of its owner
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*UserClaims, error)
+}
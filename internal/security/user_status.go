@@ -10,6 +10,10 @@ const (
 	UserStatusInactive UserStatus = "inactive"
 	// UserStatusBlocked indicates a user gets blocked by admin/superadmin and cannot access the system
 	UserStatusBlocked UserStatus = "blocked"
+	// UserStatusPendingVerification indicates a self-registered user who has not verified their email yet and cannot log in
+	UserStatusPendingVerification UserStatus = "pending_verification"
+	// UserStatusDormant indicates a user who hasn't logged in for a long time. Logging in makes them active again
+	UserStatusDormant UserStatus = "dormant"
 )
 
 func (r UserStatus) String() string {
@@ -19,7 +23,7 @@ func (r UserStatus) String() string {
 // IsValidUserStatus reports whether the given status is supported by the system.
 func IsValidUserStatus(status UserStatus) bool {
 	switch status {
-	case UserStatusActive, UserStatusInactive, UserStatusBlocked:
+	case UserStatusActive, UserStatusInactive, UserStatusBlocked, UserStatusPendingVerification, UserStatusDormant:
 		return true
 	default:
 		return false
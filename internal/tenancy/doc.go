@@ -0,0 +1,3 @@
+// Package tenancy carries the organization (tenant) a request acts in, so that tenant-owned data
+// is scoped to it by the repositories.
+package tenancy
@@ -0,0 +1,19 @@
+package tenancy
+
+import "github.com/mrhpn/go-rest-api/internal/apperror"
+
+var (
+	// ErrTenantRequired indicates that the request did not say which organization it acts in.
+	ErrTenantRequired = apperror.New(
+		apperror.BadRequest,
+		"ORGANIZATION_REQUIRED",
+		"organization is required for this request",
+	)
+
+	// ErrNotMember indicates that the caller does not belong to the requested organization.
+	ErrNotMember = apperror.New(
+		apperror.Forbidden,
+		"NOT_ORGANIZATION_MEMBER",
+		"you are not a member of this organization",
+	)
+)
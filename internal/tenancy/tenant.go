@@ -0,0 +1,88 @@
+package tenancy
+
+import "context"
+
+// MemberRole is the role of a user within one organization, independent of their app-wide role
+type MemberRole string
+
+const (
+	// MemberRoleOwner can manage the organization and all of its members.
+	MemberRoleOwner MemberRole = "owner"
+	// MemberRoleAdmin can manage the members of the organization.
+	MemberRoleAdmin MemberRole = "admin"
+	// MemberRoleMember can use the organization's data.
+	MemberRoleMember MemberRole = "member"
+)
+
+// IsValidMemberRole reports whether the given organization role is supported
+func IsValidMemberRole(role MemberRole) bool {
+	switch role {
+	case MemberRoleOwner, MemberRoleAdmin, MemberRoleMember:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanManageMembers reports whether the organization role may add and remove members
+func (r MemberRole) CanManageMembers() bool {
+	return r == MemberRoleOwner || r == MemberRoleAdmin
+}
+
+// Tenant is the organization a request acts in
+type Tenant struct {
+	ID   string
+	Slug string
+	Role MemberRole // role of the caller in the organization, empty for platform admins who aren't members
+
+	unaffiliated bool // acting outside of every organization
+}
+
+// Resolver looks up organizations and memberships for incoming requests
+type Resolver interface {
+	// Resolve finds an organization by its id or slug
+	Resolve(ctx context.Context, ref string) (*Tenant, error)
+
+	// MemberRole returns the role of the user in the organization, empty if they aren't a member
+	MemberRole(ctx context.Context, tenantID, userID string) (MemberRole, error)
+}
+
+type contextKey string
+
+const tenantKey contextKey = "tenant"
+
+// WithTenant returns a copy of ctx acting in the given organization
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+// WithoutTenant returns a copy of ctx that is not scoped to any organization, for platform-level
+// lookups that must see every row (e.g. checking that an email is unique across organizations)
+func WithoutTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantKey, Tenant{})
+}
+
+// WithNoOrganization returns a copy of ctx acting outside of every organization, so tenant-owned
+// data is limited to the rows that belong to no organization
+func WithNoOrganization(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantKey, Tenant{unaffiliated: true})
+}
+
+// Scope returns the organization tenant-owned data is limited to. An empty id limits it to the rows
+// of no organization, ok is false when ctx is not limited at all.
+func Scope(ctx context.Context) (id string, ok bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tenant, _ := ctx.Value(tenantKey).(Tenant)
+	return tenant.ID, tenant.ID != "" || tenant.unaffiliated
+}
+
+// FromContext returns the organization the request acts in, if any
+func FromContext(ctx context.Context) (Tenant, bool) {
+	if ctx == nil {
+		return Tenant{}, false
+	}
+	tenant, _ := ctx.Value(tenantKey).(Tenant)
+	return tenant, tenant.ID != ""
+}
@@ -30,6 +30,10 @@ var validationMessages = map[string]messageFormatter{
 		return "invalid id format"
 	},
 
+	"slug": func(field string, _ validator.FieldError) string {
+		return fmt.Sprintf("%s may only contain lowercase letters, digits and hyphens", field)
+	},
+
 	"url": func(_ string, _ validator.FieldError) string {
 		return "invalid url format"
 	},
@@ -38,6 +42,14 @@ var validationMessages = map[string]messageFormatter{
 		return "invalid ip address"
 	},
 
+	"bcp47_language_tag": func(field string, _ validator.FieldError) string {
+		return fmt.Sprintf("%s must be a language tag like en or en-US", field)
+	},
+
+	"timezone": func(field string, _ validator.FieldError) string {
+		return fmt.Sprintf("%s must be an IANA time zone like Europe/Berlin", field)
+	},
+
 	// ---------- size ----------
 	"min": func(field string, fe validator.FieldError) string {
 		return fmt.Sprintf("%s must be at least %s characters", field, fe.Param())
@@ -68,6 +80,31 @@ var validationMessages = map[string]messageFormatter{
 		return fmt.Sprintf("%s must be less than or equal to %s", field, fe.Param())
 	},
 
+	// ---------- password policy ----------
+	"password_min": func(field string, fe validator.FieldError) string {
+		return fmt.Sprintf("%s must be at least %s characters", field, fe.Param())
+	},
+
+	"password_max": func(field string, fe validator.FieldError) string {
+		return fmt.Sprintf("%s must be at most %s characters", field, fe.Param())
+	},
+
+	"password_classes": func(field string, fe validator.FieldError) string {
+		return fmt.Sprintf("%s must contain at least %s of: lowercase letters, uppercase letters, digits, symbols", field, fe.Param())
+	},
+
+	"password_no_email": func(field string, _ validator.FieldError) string {
+		return fmt.Sprintf("%s must not contain your email address", field)
+	},
+
+	"password_strength": func(field string, _ validator.FieldError) string {
+		return fmt.Sprintf("%s is too easy to guess, avoid common words, repetitions and sequences", field)
+	},
+
+	"password_breached": func(field string, _ validator.FieldError) string {
+		return fmt.Sprintf("%s has appeared in a data breach, please choose another one", field)
+	},
+
 	// ---------- collections ----------
 	"oneof": func(field string, fe validator.FieldError) string {
 		return fmt.Sprintf("%s must be one of [%s]", field, fe.Param())
@@ -75,9 +112,13 @@ var validationMessages = map[string]messageFormatter{
 }
 
 // GetValidationMessage returns a human-readable message for a validation error.
+// For aliases like "password" the message of the failing underlying tag is used.
 func GetValidationMessage(fe validator.FieldError) string {
 	field := stringx.ToSnakeCase(fe.Field())
 
+	if formatter, ok := validationMessages[fe.ActualTag()]; ok {
+		return formatter(field, fe)
+	}
 	if formatter, ok := validationMessages[fe.Tag()]; ok {
 		return formatter(field, fe)
 	}
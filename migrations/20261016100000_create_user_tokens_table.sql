@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_tokens (
+  id CHAR(26) PRIMARY KEY,
+  user_id CHAR(26) NOT NULL,
+  purpose VARCHAR(30) NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  deleted_at TIMESTAMPTZ,
+
+  CONSTRAINT fk_user_tokens_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_user_tokens_token_hash ON user_tokens(token_hash);
+CREATE INDEX idx_user_tokens_user_id_purpose ON user_tokens(user_id, purpose) WHERE used_at IS NULL;
+CREATE INDEX idx_user_tokens_expires_at ON user_tokens(expires_at);
+CREATE INDEX idx_user_tokens_deleted_at ON user_tokens(deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users DROP CONSTRAINT check_valid_status;
+ALTER TABLE users ADD CONSTRAINT check_valid_status
+  CHECK (status IN('active', 'inactive', 'blocked', 'pending_verification'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE users SET status = 'inactive' WHERE status = 'pending_verification';
+ALTER TABLE users DROP CONSTRAINT check_valid_status;
+ALTER TABLE users ADD CONSTRAINT check_valid_status
+  CHECK (status IN('active', 'inactive', 'blocked'));
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE mfa_factors (
+  id CHAR(26) PRIMARY KEY,
+  user_id CHAR(26) NOT NULL,
+  secret VARCHAR(64) NOT NULL,
+  confirmed_at TIMESTAMPTZ,
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  deleted_at TIMESTAMPTZ,
+
+  CONSTRAINT fk_mfa_factors_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_mfa_factors_user_id ON mfa_factors(user_id);
+CREATE INDEX idx_mfa_factors_deleted_at ON mfa_factors(deleted_at);
+
+CREATE TABLE mfa_recovery_codes (
+  id CHAR(26) PRIMARY KEY,
+  user_id CHAR(26) NOT NULL,
+  code_hash CHAR(64) NOT NULL,
+  used_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  deleted_at TIMESTAMPTZ,
+
+  CONSTRAINT fk_mfa_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id) WHERE used_at IS NULL;
+CREATE INDEX idx_mfa_recovery_codes_deleted_at ON mfa_recovery_codes(deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS mfa_factors;
+-- +goose StatementEnd
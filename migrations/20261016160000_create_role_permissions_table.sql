@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE role_permissions (
+  id CHAR(26) PRIMARY KEY,
+  role VARCHAR(32) NOT NULL,
+  permission VARCHAR(64) NOT NULL,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_role_permissions_role_permission ON role_permissions(role, permission);
+CREATE INDEX idx_role_permissions_deleted_at ON role_permissions(deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS role_permissions;
+-- +goose StatementEnd
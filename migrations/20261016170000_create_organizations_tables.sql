@@ -0,0 +1,82 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE organizations (
+  id CHAR(26) PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  slug VARCHAR(50) NOT NULL,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_organizations_slug ON organizations(slug);
+CREATE INDEX idx_organizations_deleted_at ON organizations(deleted_at);
+
+CREATE TABLE organization_members (
+  id CHAR(26) PRIMARY KEY,
+  organization_id CHAR(26) NOT NULL,
+  user_id CHAR(26) NOT NULL,
+  role VARCHAR(20) NOT NULL DEFAULT 'member',
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  deleted_at TIMESTAMPTZ,
+
+  CONSTRAINT fk_organization_members_organization FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+  CONSTRAINT fk_organization_members_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT check_valid_member_role CHECK (role IN('owner', 'admin', 'member'))
+);
+
+CREATE UNIQUE INDEX idx_organization_members_organization_user ON organization_members(organization_id, user_id);
+CREATE INDEX idx_organization_members_user_id ON organization_members(user_id);
+CREATE INDEX idx_organization_members_deleted_at ON organization_members(deleted_at);
+
+-- posts created outside any organization keep a NULL organization
+ALTER TABLE posts ADD COLUMN organization_id CHAR(26);
+ALTER TABLE posts ADD CONSTRAINT fk_posts_organization FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+CREATE INDEX idx_posts_organization_id ON posts(organization_id) WHERE deleted_at IS NULL;
+
+-- row-level security backs up the repository scoping when TENANCY_ROW_LEVEL_SECURITY is enabled.
+-- the app sets app.organization_id per request transaction; without it no row is visible. sessions
+-- that must see every organization say so explicitly with app.bypass_tenancy = 'on': the app's
+-- connections do, request transactions of an organization turn it off again. other clients (psql,
+-- later data migrations) have to set it as well.
+-- FORCE applies the policies to the table owner too, which the app usually connects as.
+ALTER TABLE posts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE posts FORCE ROW LEVEL SECURITY;
+CREATE POLICY posts_tenant_isolation ON posts
+  USING (
+    current_setting('app.bypass_tenancy', true) = 'on'
+    OR organization_id = NULLIF(current_setting('app.organization_id', true), '')
+  )
+  WITH CHECK (
+    current_setting('app.bypass_tenancy', true) = 'on'
+    OR organization_id = NULLIF(current_setting('app.organization_id', true), '')
+  );
+
+ALTER TABLE organization_members ENABLE ROW LEVEL SECURITY;
+ALTER TABLE organization_members FORCE ROW LEVEL SECURITY;
+CREATE POLICY organization_members_tenant_isolation ON organization_members
+  USING (
+    current_setting('app.bypass_tenancy', true) = 'on'
+    OR organization_id = NULLIF(current_setting('app.organization_id', true), '')
+  )
+  WITH CHECK (
+    current_setting('app.bypass_tenancy', true) = 'on'
+    OR organization_id = NULLIF(current_setting('app.organization_id', true), '')
+  );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY IF EXISTS posts_tenant_isolation ON posts;
+ALTER TABLE posts NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE posts DISABLE ROW LEVEL SECURITY;
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS fk_posts_organization;
+DROP INDEX IF EXISTS idx_posts_organization_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS organization_id;
+
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
+-- +goose StatementEnd
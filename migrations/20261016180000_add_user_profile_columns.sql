@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+  ADD COLUMN display_name VARCHAR(100) NOT NULL DEFAULT '',
+  ADD COLUMN bio TEXT NOT NULL DEFAULT '',
+  ADD COLUMN avatar_url TEXT NOT NULL DEFAULT '',
+  ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT '',
+  ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+  DROP COLUMN IF EXISTS timezone,
+  DROP COLUMN IF EXISTS locale,
+  DROP COLUMN IF EXISTS avatar_url,
+  DROP COLUMN IF EXISTS bio,
+  DROP COLUMN IF EXISTS display_name;
+-- +goose StatementEnd
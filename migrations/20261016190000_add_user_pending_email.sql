@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the new address of a requested email change, swapped into email once the user confirms it
+ALTER TABLE users ADD COLUMN pending_email TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS pending_email;
+-- +goose StatementEnd
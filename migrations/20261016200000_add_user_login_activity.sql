@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+  ADD COLUMN last_login_at TIMESTAMPTZ,
+  ADD COLUMN last_login_ip VARCHAR(45) NOT NULL DEFAULT '',
+  ADD COLUMN login_count BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN failed_login_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_users_last_login_at ON users(last_login_at) WHERE deleted_at IS NULL;
+
+-- accounts nobody logged into for a while are marked dormant by a scheduled job
+ALTER TABLE users DROP CONSTRAINT check_valid_status;
+ALTER TABLE users ADD CONSTRAINT check_valid_status
+  CHECK (status IN('active', 'inactive', 'blocked', 'pending_verification', 'dormant'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE users SET status = 'inactive' WHERE status = 'dormant';
+ALTER TABLE users DROP CONSTRAINT check_valid_status;
+ALTER TABLE users ADD CONSTRAINT check_valid_status
+  CHECK (status IN('active', 'inactive', 'blocked', 'pending_verification'));
+
+DROP INDEX IF EXISTS idx_users_last_login_at;
+ALTER TABLE users
+  DROP COLUMN IF EXISTS failed_login_count,
+  DROP COLUMN IF EXISTS login_count,
+  DROP COLUMN IF EXISTS last_login_ip,
+  DROP COLUMN IF EXISTS last_login_at;
+-- +goose StatementEnd
@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE data_exports (
+  id CHAR(26) PRIMARY KEY,
+  user_id CHAR(26) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  path TEXT NOT NULL DEFAULT '',
+  size BIGINT NOT NULL DEFAULT 0,
+  completed_at TIMESTAMPTZ,
+  expires_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  deleted_at TIMESTAMPTZ,
+
+  CONSTRAINT fk_data_exports_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT check_valid_data_export_status CHECK (status IN('pending', 'processing', 'ready', 'failed', 'expired'))
+);
+
+CREATE INDEX idx_data_exports_user_id ON data_exports(user_id);
+CREATE INDEX idx_data_exports_status ON data_exports(status, created_at);
+CREATE INDEX idx_data_exports_expires_at ON data_exports(expires_at) WHERE status = 'ready';
+CREATE INDEX idx_data_exports_deleted_at ON data_exports(deleted_at);
+
+-- no foreign key: the request is the record of the erasure and outlives the user
+CREATE TABLE erasure_requests (
+  id CHAR(26) PRIMARY KEY,
+  user_id CHAR(26) NOT NULL,
+  requested_by CHAR(26) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  scheduled_for TIMESTAMPTZ NOT NULL,
+  completed_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  deleted_at TIMESTAMPTZ,
+
+  CONSTRAINT check_valid_erasure_status CHECK (status IN('pending', 'cancelled', 'completed'))
+);
+
+CREATE UNIQUE INDEX idx_erasure_requests_pending_user ON erasure_requests(user_id) WHERE status = 'pending';
+CREATE INDEX idx_erasure_requests_scheduled_for ON erasure_requests(scheduled_for) WHERE status = 'pending';
+CREATE INDEX idx_erasure_requests_deleted_at ON erasure_requests(deleted_at);
+
+-- anonymized users stay as soft-deleted tombstones that keep their anonymized content
+ALTER TABLE users ADD COLUMN erased_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS erased_at;
+DROP TABLE IF EXISTS erasure_requests;
+DROP TABLE IF EXISTS data_exports;
+-- +goose StatementEnd